@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// dateLayout is how from= and to= and the days in responses are written.
+const dateLayout = time.DateOnly
+
+const (
+	// defaultDays is the length of the range when from= is left out.
+	defaultDays = 30
+	// maxDays is the longest range that can be asked for.
+	maxDays = 366
+
+	defaultTopLimit = 10
+	maxTopLimit     = 100
+)
+
+// DateRange is a range of UTC days, both ends included.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// Days returns the number of days in the range.
+func (r DateRange) Days() int {
+	return int(r.To.Sub(r.From).Hours()/24) + 1
+}
+
+// ParseDateRange reads from= and to= as YYYY-MM-DD. to defaults to the day
+// of now and from to the 30 days up to to.
+func ParseDateRange(values url.Values, now time.Time) (DateRange, error) {
+	r := DateRange{To: day(now)}
+	if v := values.Get("to"); v != "" {
+		to, err := time.Parse(dateLayout, v)
+		if err != nil {
+			return DateRange{}, apperrors.NewInvalidInputError(
+				"to must be a date such as 2025-07-31")
+		}
+		r.To = to
+	}
+	r.From = r.To.AddDate(0, 0, 1-defaultDays)
+	if v := values.Get("from"); v != "" {
+		from, err := time.Parse(dateLayout, v)
+		if err != nil {
+			return DateRange{}, apperrors.NewInvalidInputError(
+				"from must be a date such as 2025-07-01")
+		}
+		r.From = from
+	}
+	if r.From.After(r.To) {
+		return DateRange{}, apperrors.NewInvalidInputError(
+			"from must not be after to")
+	}
+	if r.Days() > maxDays {
+		return DateRange{}, apperrors.NewInvalidInputError(
+			"the range must not be longer than " + strconv.Itoa(maxDays) +
+				" days")
+	}
+	return r, nil
+}
+
+// parseLimit reads limit= for the top posts.
+func parseLimit(values url.Values) (int, error) {
+	v := values.Get("limit")
+	if v == "" {
+		return defaultTopLimit, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > maxTopLimit {
+		return 0, apperrors.NewInvalidInputError(
+			"limit must be a number between 1 and " + strconv.Itoa(maxTopLimit))
+	}
+	return n, nil
+}
+
+// PostViewsResponse is the daily views of a post in a date range.
+type PostViewsResponse struct {
+	PostID uuid.UUID `json:"post_id"`
+	From   string    `json:"from" example:"2025-07-01"`
+	To     string    `json:"to" example:"2025-07-31"`
+	Total  int64     `json:"total" example:"1234"`
+	// Days holds every day of the range, including those without views.
+	Days []*DayResponse `json:"days"`
+}
+
+type DayResponse struct {
+	Date  string `json:"date" example:"2025-07-01"`
+	Views int64  `json:"views" example:"42"`
+}
+
+// TopPostsResponse is the most viewed posts in a date range, most views
+// first.
+type TopPostsResponse struct {
+	From  string             `json:"from" example:"2025-07-01"`
+	To    string             `json:"to" example:"2025-07-31"`
+	Items []*TopPostResponse `json:"items"`
+}
+
+type TopPostResponse struct {
+	PostID uuid.UUID `json:"post_id"`
+	Title  string    `json:"title" example:"My First Post"`
+	Views  int64     `json:"views" example:"1234"`
+}
@@ -0,0 +1,117 @@
+package analytics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"net/http"
+	"time"
+)
+
+type Handler struct {
+	Service Service
+	now     func() time.Time
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{Service: service, now: time.Now}
+}
+
+func handleError(c *gin.Context, err error) {
+	status := apperrors.StatusCode(err)
+	if status == http.StatusInternalServerError {
+		logging.FromContext(c.Request.Context()).Error("request failed",
+			"error", err)
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/top", h.getTopPosts)
+}
+
+// RegisterPostRoutes adds the analytics of a post to the posts group.
+func (h *Handler) RegisterPostRoutes(r *gin.RouterGroup) {
+	r.GET("/:id/analytics", h.getPostViews)
+}
+
+// @Summary Get the views of a post
+// @Description Get the views of a post for every day of a date range, in
+// @Description UTC. Repeated views by one visitor within the dedupe window
+// @Description and views by bots are not counted, and views show up after
+// @Description the next flush.
+// @Tags analytics
+// @Produce json
+// @Param id path string true "Post ID" Format(uuid)
+// @Param from query string false "First day, YYYY-MM-DD, 29 days before to by default"
+// @Param to query string false "Last day, YYYY-MM-DD, today by default"
+// @Success 200 {object} PostViewsResponse
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /posts/{id}/analytics [get]
+// @Security ApiKeyAuth
+func (h *Handler) getPostViews(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleError(c, apperrors.NewInvalidInputError("ID must be a uuid"))
+		return
+	}
+	r, err := ParseDateRange(c.Request.URL.Query(), h.now())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	views, err := h.Service.GetPostViews(c.Request.Context(), id, r)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := &PostViewsResponse{PostID: id, From: r.From.Format(dateLayout),
+		To: r.To.Format(dateLayout), Days: make([]*DayResponse, len(views))}
+	for i, v := range views {
+		resp.Days[i] = &DayResponse{Date: v.Day.Format(dateLayout),
+			Views: v.Views}
+		resp.Total += v.Views
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Get the most viewed posts
+// @Description Get the posts with the most views in a date range, in UTC,
+// @Description most views first
+// @Tags analytics
+// @Produce json
+// @Param from query string false "First day, YYYY-MM-DD, 29 days before to by default"
+// @Param to query string false "Last day, YYYY-MM-DD, today by default"
+// @Param limit query int false "Number of posts" minimum(1) maximum(100) default(10)
+// @Success 200 {object} TopPostsResponse
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Router /analytics/top [get]
+// @Security ApiKeyAuth
+func (h *Handler) getTopPosts(c *gin.Context) {
+	r, err := ParseDateRange(c.Request.URL.Query(), h.now())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	limit, err := parseLimit(c.Request.URL.Query())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	top, err := h.Service.GetTopPosts(c.Request.Context(), r, limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := &TopPostsResponse{From: r.From.Format(dateLayout),
+		To: r.To.Format(dateLayout), Items: make([]*TopPostResponse, len(top))}
+	for i, p := range top {
+		resp.Items[i] = &TopPostResponse{PostID: p.PostID, Title: p.Title,
+			Views: p.Views}
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,134 @@
+package analytics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupTestRouter(t *testing.T) (*gin.Engine, *MockService) {
+	ctrl := gomock.NewController(t)
+	mockService := NewMockService(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewHandler(mockService)
+	handler.now = func() time.Time {
+		return time.Date(2025, 7, 31, 12, 0, 0, 0, time.UTC)
+	}
+	handler.RegisterRoutes(router.Group("/analytics"))
+	handler.RegisterPostRoutes(router.Group("/posts"))
+	return router, mockService
+}
+
+func TestHandler_GetPostViews(t *testing.T) {
+	postID := uuid.New()
+	r := DateRange{From: date("2025-07-30"), To: date("2025-07-31")}
+	tests := []struct {
+		name          string
+		id            string
+		query         string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name:  "success",
+			id:    postID.String(),
+			query: "?from=2025-07-30",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetPostViews(gomock.Any(), postID, r).
+					Return([]*model.PostView{
+						{PostID: postID, Day: r.From, Views: 3},
+						{PostID: postID, Day: r.To, Views: 4},
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"post_id":"` + postID.String() + `",
+				"from":"2025-07-30","to":"2025-07-31","total":7,"days":[
+				{"date":"2025-07-30","views":3},{"date":"2025-07-31","views":4}]}`,
+		},
+		{
+			name:       "not an uuid",
+			id:         "abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"ID must be a uuid"}`,
+		},
+		{
+			name:       "invalid range",
+			id:         postID.String(),
+			query:      "?from=2025-08-01",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"from must not be after to"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupTestRouter(t)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockService)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet,
+				"/posts/"+test.id+"/analytics"+test.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.JSONEq(t, test.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetTopPosts(t *testing.T) {
+	postID := uuid.New()
+	r := DateRange{From: date("2025-07-02"), To: date("2025-07-31")}
+	tests := []struct {
+		name          string
+		query         string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name:  "success",
+			query: "?limit=1",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetTopPosts(gomock.Any(), r, 1).
+					Return([]*TopPost{{PostID: postID, Title: "Hello",
+						Views: 12}}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"from":"2025-07-02","to":"2025-07-31","items":[
+				{"post_id":"` + postID.String() + `","title":"Hello","views":12}]}`,
+		},
+		{
+			name:       "invalid limit",
+			query:      "?limit=500",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"limit must be a number between 1 and 100"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupTestRouter(t)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockService)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet,
+				"/analytics/top"+test.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.JSONEq(t, test.wantBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package analytics
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/metrics"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"strings"
+	"sync"
+	"time"
+)
+
+// botUserAgents are user agent substrings of crawlers, link previews and
+// monitors, whose requests are not reads.
+var botUserAgents = []string{"bot", "crawl", "spider", "slurp",
+	"facebookexternalhit", "embedly", "preview", "headless", "lighthouse",
+	"pingdom", "monitor"}
+
+// flushTimeout bounds the last flush when the server shuts down.
+const flushTimeout = 5 * time.Second
+
+// Recorder counts views in memory and writes them to the daily totals in
+// batches, so reading a post does not cost a write. A visitor is the user
+// of the API key when it is linked to one and the client IP otherwise.
+// Deduplication is per process, so with several replicas a visitor may be
+// counted once by each.
+type Recorder struct {
+	repo   Repository
+	window time.Duration
+	bots   []string
+	now    func() time.Time
+
+	mu sync.Mutex
+	// seen holds until when each visitor's views of a post are repeats.
+	seen    map[visit]time.Time
+	pending map[postDay]int64
+}
+
+type visit struct {
+	postID  uuid.UUID
+	visitor string
+}
+
+type postDay struct {
+	postID uuid.UUID
+	day    time.Time
+}
+
+func NewRecorder(repo Repository, cfg config.AnalyticsConfig) *Recorder {
+	bots := append([]string(nil), botUserAgents...)
+	for _, b := range cfg.BotUserAgents {
+		bots = append(bots, strings.ToLower(b))
+	}
+	return &Recorder{repo: repo, window: cfg.DedupeWindow, bots: bots,
+		now: time.Now, seen: map[visit]time.Time{},
+		pending: map[postDay]int64{}}
+}
+
+func (r *Recorder) isBot(userAgent string) bool {
+	if userAgent == "" {
+		return true
+	}
+	userAgent = strings.ToLower(userAgent)
+	for _, b := range r.bots {
+		if strings.Contains(userAgent, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordView counts a view of a post unless it comes from a bot or the
+// visitor viewed the post within the dedupe window.
+func (r *Recorder) RecordView(ctx context.Context, postID uuid.UUID,
+	clientIP string, userAgent string) {
+	if r.isBot(userAgent) {
+		metrics.PostViews.WithLabelValues("bot").Inc()
+		return
+	}
+	visitor := "ip:" + clientIP
+	if id, ok := auth.UserID(ctx); ok {
+		visitor = "user:" + id.String()
+	}
+
+	now := r.now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v := visit{postID: postID, visitor: visitor}
+	if until, ok := r.seen[v]; ok && now.Before(until) {
+		metrics.PostViews.WithLabelValues("repeat").Inc()
+		return
+	}
+	r.seen[v] = now.Add(r.window)
+	r.pending[postDay{postID: postID, day: day(now)}]++
+	metrics.PostViews.WithLabelValues("counted").Inc()
+}
+
+// Flush writes the views counted since the last flush. When that fails
+// they are kept for the next one.
+func (r *Recorder) Flush(ctx context.Context) error {
+	now := r.now()
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = map[postDay]int64{}
+	for v, until := range r.seen {
+		if !now.Before(until) {
+			delete(r.seen, v)
+		}
+	}
+	r.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	views := make([]*model.PostView, 0, len(pending))
+	for k, n := range pending {
+		views = append(views, &model.PostView{PostID: k.postID, Day: k.day,
+			Views: n})
+	}
+	if err := r.repo.AddViews(ctx, views); err != nil {
+		r.mu.Lock()
+		for k, n := range pending {
+			r.pending[k] += n
+		}
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// FlushWorker flushes r every interval until ctx is cancelled and once
+// more before it returns, so no counted view is lost on shutdown.
+func FlushWorker(r *Recorder, interval time.Duration) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				flushCtx, cancel := context.WithTimeout(
+					context.WithoutCancel(ctx), flushTimeout)
+				defer cancel()
+				if err := r.Flush(flushCtx); err != nil {
+					logging.FromContext(ctx).Error("flush post views",
+						"error", err)
+				}
+				return
+			case <-ticker.C:
+				if err := r.Flush(ctx); err != nil {
+					logging.FromContext(ctx).Error("flush post views",
+						"error", err)
+				}
+			}
+		}
+	}
+}
+
+// day returns the UTC day of t.
+func day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,96 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+const browser = "Mozilla/5.0 (X11; Linux x86_64) Firefox/128.0"
+
+func setupRecorder(t *testing.T) (*MockRepository, *Recorder, *time.Time) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockRepository(ctrl)
+	r := NewRecorder(mockRepo, config.AnalyticsConfig{
+		DedupeWindow: 30 * time.Minute, BotUserAgents: []string{"Uptime"}})
+	now := time.Date(2025, 7, 18, 23, 50, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	return mockRepo, r, &now
+}
+
+// flushed returns the views r writes on its next flush.
+func flushed(t *testing.T, mockRepo *MockRepository, r *Recorder) []*model.PostView {
+	t.Helper()
+	var got []*model.PostView
+	mockRepo.EXPECT().AddViews(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, views []*model.PostView) error {
+			got = views
+			return nil
+		})
+	require.NoError(t, r.Flush(context.Background()))
+	return got
+}
+
+func TestRecorder_RecordView(t *testing.T) {
+	mockRepo, r, now := setupRecorder(t)
+	ctx := context.Background()
+	postID := uuid.New()
+
+	r.RecordView(ctx, postID, "10.0.0.1", browser)
+	r.RecordView(ctx, postID, "10.0.0.1", browser)
+	r.RecordView(ctx, postID, "10.0.0.2", browser)
+	r.RecordView(ctx, postID, "10.0.0.3", "Googlebot/2.1")
+	r.RecordView(ctx, postID, "10.0.0.3", "uptime-kuma")
+	r.RecordView(ctx, postID, "10.0.0.3", "")
+	// Users are told apart by their ID, not their address.
+	r.RecordView(auth.WithUser(ctx, uuid.New()), postID, "10.0.0.1", browser)
+	*now = now.Add(31 * time.Minute)
+	r.RecordView(ctx, postID, "10.0.0.1", browser)
+
+	got := flushed(t, mockRepo, r)
+	assert.ElementsMatch(t, []*model.PostView{
+		{PostID: postID, Day: time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC),
+			Views: 3},
+		{PostID: postID, Day: time.Date(2025, 7, 19, 0, 0, 0, 0, time.UTC),
+			Views: 1},
+	}, got)
+}
+
+func TestRecorder_Flush(t *testing.T) {
+	mockRepo, r, _ := setupRecorder(t)
+	ctx := context.Background()
+	postID := uuid.New()
+
+	// Nothing counted, nothing written.
+	require.NoError(t, r.Flush(ctx))
+
+	r.RecordView(ctx, postID, "10.0.0.1", browser)
+	mockRepo.EXPECT().AddViews(gomock.Any(), gomock.Any()).
+		Return(errors.New("connection refused"))
+	assert.Error(t, r.Flush(ctx))
+
+	// The failed views are written with the next ones.
+	r.RecordView(ctx, postID, "10.0.0.2", browser)
+	got := flushed(t, mockRepo, r)
+	require.Len(t, got, 1)
+	assert.Equal(t, int64(2), got[0].Views)
+}
+
+func TestFlushWorker_FlushesOnShutdown(t *testing.T) {
+	mockRepo, r, _ := setupRecorder(t)
+	r.RecordView(context.Background(), uuid.New(), "10.0.0.1", browser)
+	mockRepo.EXPECT().AddViews(gomock.Any(), gomock.Len(1)).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	FlushWorker(r, time.Hour)(ctx)
+}
@@ -0,0 +1,94 @@
+package analytics
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=repository.go -destination=repository_mock.go -package=analytics
+
+type Repository interface {
+	// AddViews adds the views to the daily totals. Views of posts deleted
+	// in the meantime are dropped.
+	AddViews(ctx context.Context, views []*model.PostView) error
+	// FindViews returns the daily totals of a post from from to to, both
+	// included, for the days with views.
+	FindViews(ctx context.Context, postID uuid.UUID, from time.Time, to time.Time) ([]*model.PostView, error)
+	// FindTop returns the limit posts with the most views from from to to.
+	FindTop(ctx context.Context, from time.Time, to time.Time, limit int) ([]*TopPost, error)
+	PostExists(ctx context.Context, postID uuid.UUID) (bool, error)
+}
+
+// TopPost is a post with the number of its views in a date range.
+type TopPost struct {
+	PostID uuid.UUID
+	Title  string
+	Views  int64
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) AddViews(ctx context.Context,
+	views []*model.PostView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	rows := make([]string, len(views))
+	args := make([]any, 0, 3*len(views))
+	for i, v := range views {
+		rows[i] = "(?::char(36), ?::date, ?::bigint)"
+		args = append(args, v.PostID, v.Day, v.Views)
+	}
+	// A plain insert would fail the whole batch on the foreign key of a
+	// post deleted since its views were recorded.
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO post_views (post_id, day, views)
+		SELECT v.post_id, v.day, v.views
+		FROM (VALUES `+strings.Join(rows, ", ")+`) AS v (post_id, day, views)
+		WHERE EXISTS (SELECT 1 FROM posts p WHERE p.id = v.post_id)
+		ON CONFLICT (post_id, day)
+			DO UPDATE SET views = post_views.views + EXCLUDED.views`,
+		args...).Error
+}
+
+func (r *repository) FindViews(ctx context.Context, postID uuid.UUID,
+	from time.Time, to time.Time) ([]*model.PostView, error) {
+	var views []*model.PostView
+	err := r.db.WithContext(ctx).
+		Where("post_id = ? AND day BETWEEN ? AND ?", postID, from, to).
+		Order("day").
+		Find(&views).Error
+	return views, err
+}
+
+func (r *repository) FindTop(ctx context.Context, from time.Time,
+	to time.Time, limit int) ([]*TopPost, error) {
+	var top []*TopPost
+	err := r.db.WithContext(ctx).Table("post_views v").
+		Select("v.post_id, p.title, SUM(v.views) AS views").
+		Joins("JOIN posts p ON p.id = v.post_id").
+		Where("v.day BETWEEN ? AND ?", from, to).
+		Group("v.post_id, p.title").
+		Order("views DESC").Order("v.post_id").
+		Limit(limit).
+		Scan(&top).Error
+	return top, err
+}
+
+func (r *repository) PostExists(ctx context.Context,
+	postID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Post{}).
+		Where("id = ?", postID).Count(&count).Error
+	return count > 0, err
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
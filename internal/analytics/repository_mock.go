@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package analytics is a generated GoMock package.
+package analytics
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	model "github.com/pandahawk/blog-api/internal/shared/model"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddViews mocks base method.
+func (m *MockRepository) AddViews(ctx context.Context, views []*model.PostView) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddViews", ctx, views)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddViews indicates an expected call of AddViews.
+func (mr *MockRepositoryMockRecorder) AddViews(ctx, views interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddViews", reflect.TypeOf((*MockRepository)(nil).AddViews), ctx, views)
+}
+
+// FindTop mocks base method.
+func (m *MockRepository) FindTop(ctx context.Context, from, to time.Time, limit int) ([]*TopPost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTop", ctx, from, to, limit)
+	ret0, _ := ret[0].([]*TopPost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTop indicates an expected call of FindTop.
+func (mr *MockRepositoryMockRecorder) FindTop(ctx, from, to, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTop", reflect.TypeOf((*MockRepository)(nil).FindTop), ctx, from, to, limit)
+}
+
+// FindViews mocks base method.
+func (m *MockRepository) FindViews(ctx context.Context, postID uuid.UUID, from, to time.Time) ([]*model.PostView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindViews", ctx, postID, from, to)
+	ret0, _ := ret[0].([]*model.PostView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindViews indicates an expected call of FindViews.
+func (mr *MockRepositoryMockRecorder) FindViews(ctx, postID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindViews", reflect.TypeOf((*MockRepository)(nil).FindViews), ctx, postID, from, to)
+}
+
+// PostExists mocks base method.
+func (m *MockRepository) PostExists(ctx context.Context, postID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostExists", ctx, postID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostExists indicates an expected call of PostExists.
+func (mr *MockRepositoryMockRecorder) PostExists(ctx, postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostExists", reflect.TypeOf((*MockRepository)(nil).PostExists), ctx, postID)
+}
@@ -0,0 +1,43 @@
+package analytics
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/shared/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRepository_Views(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	first, second := date("2025-07-01"), date("2025-07-02")
+
+	require.NoError(t, repo.AddViews(ctx, []*model.PostView{
+		{PostID: testdata.Post1.ID, Day: first, Views: 2},
+		{PostID: testdata.Post2.ID, Day: first, Views: 5},
+		// Views of a deleted post are dropped without failing the batch.
+		{PostID: uuid.New(), Day: first, Views: 1},
+	}))
+	require.NoError(t, repo.AddViews(ctx, []*model.PostView{
+		{PostID: testdata.Post1.ID, Day: first, Views: 3},
+		{PostID: testdata.Post1.ID, Day: second, Views: 4},
+	}))
+
+	views, err := repo.FindViews(ctx, testdata.Post1.ID, first, second)
+	require.NoError(t, err)
+	require.Len(t, views, 2)
+	assert.Equal(t, int64(5), views[0].Views)
+	assert.Equal(t, int64(4), views[1].Views)
+
+	top, err := repo.FindTop(ctx, first, second, 1)
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	assert.Equal(t, testdata.Post1.ID, top[0].PostID)
+	assert.Equal(t, testdata.Post1.Title, top[0].Title)
+	assert.Equal(t, int64(9), top[0].Views)
+}
@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock.go -package=analytics
+
+type Service interface {
+	// GetPostViews returns the views of a post for every day of r, zero for
+	// days without views.
+	GetPostViews(ctx context.Context, postID uuid.UUID, r DateRange) ([]*model.PostView, error)
+	// GetTopPosts returns the limit posts with the most views in r.
+	GetTopPosts(ctx context.Context, r DateRange, limit int) ([]*TopPost, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func (s *service) GetPostViews(ctx context.Context, postID uuid.UUID,
+	r DateRange) ([]*model.PostView, error) {
+	exists, err := s.repo.PostExists(ctx, postID)
+	if err != nil {
+		logging.FromContext(ctx).Error("find post", "post_id", postID,
+			"error", err)
+		return nil, errors.New("db error")
+	}
+	if !exists {
+		return nil, apperrors.NewNotFoundError("post", postID)
+	}
+	found, err := s.repo.FindViews(ctx, postID, r.From, r.To)
+	if err != nil {
+		logging.FromContext(ctx).Error("find post views", "post_id", postID,
+			"error", err)
+		return nil, errors.New("db error")
+	}
+
+	byDay := make(map[string]int64, len(found))
+	for _, v := range found {
+		byDay[v.Day.Format(dateLayout)] = v.Views
+	}
+	views := make([]*model.PostView, r.Days())
+	for i := range views {
+		d := r.From.AddDate(0, 0, i)
+		views[i] = &model.PostView{PostID: postID, Day: d,
+			Views: byDay[d.Format(dateLayout)]}
+	}
+	return views, nil
+}
+
+func (s *service) GetTopPosts(ctx context.Context, r DateRange,
+	limit int) ([]*TopPost, error) {
+	top, err := s.repo.FindTop(ctx, r.From, r.To, limit)
+	if err != nil {
+		logging.FromContext(ctx).Error("find top posts", "error", err)
+		return nil, errors.New("db error")
+	}
+	return top, nil
+}
+
+func NewService(r Repository) Service {
+	return &service{repo: r}
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package analytics is a generated GoMock package.
+package analytics
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	model "github.com/pandahawk/blog-api/internal/shared/model"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// GetPostViews mocks base method.
+func (m *MockService) GetPostViews(ctx context.Context, postID uuid.UUID, r DateRange) ([]*model.PostView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostViews", ctx, postID, r)
+	ret0, _ := ret[0].([]*model.PostView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostViews indicates an expected call of GetPostViews.
+func (mr *MockServiceMockRecorder) GetPostViews(ctx, postID, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostViews", reflect.TypeOf((*MockService)(nil).GetPostViews), ctx, postID, r)
+}
+
+// GetTopPosts mocks base method.
+func (m *MockService) GetTopPosts(ctx context.Context, r DateRange, limit int) ([]*TopPost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopPosts", ctx, r, limit)
+	ret0, _ := ret[0].([]*TopPost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopPosts indicates an expected call of GetTopPosts.
+func (mr *MockServiceMockRecorder) GetTopPosts(ctx, r, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopPosts", reflect.TypeOf((*MockService)(nil).GetTopPosts), ctx, r, limit)
+}
@@ -0,0 +1,146 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func setupMockRepoAndService(t *testing.T) (*MockRepository, Service) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockRepo := NewMockRepository(ctrl)
+	return mockRepo, NewService(mockRepo)
+}
+
+func TestService_GetPostViews(t *testing.T) {
+	postID := uuid.New()
+	r := DateRange{From: date("2025-07-01"), To: date("2025-07-03")}
+	tests := []struct {
+		name       string
+		expectMock func(mockRepo *MockRepository)
+		want       []int64
+		wantErr    string
+	}{
+		{
+			name: "days without views are zero",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().PostExists(gomock.Any(), postID).
+					Return(true, nil)
+				mockRepo.EXPECT().FindViews(gomock.Any(), postID, r.From, r.To).
+					Return([]*model.PostView{{PostID: postID,
+						Day: date("2025-07-02"), Views: 7}}, nil)
+			},
+			want: []int64{0, 7, 0},
+		},
+		{
+			name: "post not found",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().PostExists(gomock.Any(), postID).
+					Return(false, nil)
+			},
+			wantErr: apperrors.NewNotFoundError("post", postID).Error(),
+		},
+		{
+			name: "db error",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().PostExists(gomock.Any(), postID).
+					Return(true, nil)
+				mockRepo.EXPECT().FindViews(gomock.Any(), postID, r.From, r.To).
+					Return(nil, errors.New("connection refused"))
+			},
+			wantErr: "db error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setupMockRepoAndService(t)
+			test.expectMock(mockRepo)
+
+			views, err := service.GetPostViews(context.Background(), postID, r)
+
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			got := make([]int64, len(views))
+			for i, v := range views {
+				got[i] = v.Views
+				assert.Equal(t, r.From.AddDate(0, 0, i), v.Day)
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	now := time.Date(2025, 7, 31, 22, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		query   string
+		want    DateRange
+		wantErr string
+	}{
+		{
+			name: "last 30 days by default",
+			want: DateRange{From: date("2025-07-02"), To: date("2025-07-31")},
+		},
+		{
+			name:  "from and to",
+			query: "from=2025-01-01&to=2025-01-01",
+			want:  DateRange{From: date("2025-01-01"), To: date("2025-01-01")},
+		},
+		{
+			name:  "30 days up to to",
+			query: "to=2025-03-01",
+			want:  DateRange{From: date("2025-01-31"), To: date("2025-03-01")},
+		},
+		{
+			name:    "invalid date",
+			query:   "from=01.07.2025",
+			wantErr: "from must be a date such as 2025-07-01",
+		},
+		{
+			name:    "from after to",
+			query:   "from=2025-07-02&to=2025-07-01",
+			wantErr: "from must not be after to",
+		},
+		{
+			name:    "too long",
+			query:   "from=2024-01-01&to=2025-07-01",
+			wantErr: "the range must not be longer than 366 days",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(test.query)
+
+			got, err := ParseDateRange(values, now)
+
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
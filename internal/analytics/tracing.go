@@ -0,0 +1,49 @@
+package analytics
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("analytics")
+
+type tracedService struct {
+	next Service
+}
+
+// NewTracedService wraps a Service so every call runs in its own span.
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next}
+}
+
+func rangeAttributes(r DateRange) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("analytics.from", r.From.Format(dateLayout)),
+		attribute.String("analytics.to", r.To.Format(dateLayout)),
+	}
+}
+
+func (s *tracedService) GetPostViews(ctx context.Context, postID uuid.UUID,
+	r DateRange) ([]*model.PostView, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Service.GetPostViews",
+		trace.WithAttributes(attribute.String("post.id", postID.String())),
+		trace.WithAttributes(rangeAttributes(r)...))
+	views, err := s.next.GetPostViews(ctx, postID, r)
+	tracing.End(span, err)
+	return views, err
+}
+
+func (s *tracedService) GetTopPosts(ctx context.Context, r DateRange,
+	limit int) ([]*TopPost, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Service.GetTopPosts",
+		trace.WithAttributes(rangeAttributes(r)...),
+		trace.WithAttributes(attribute.Int("analytics.limit", limit)))
+	top, err := s.next.GetTopPosts(ctx, r, limit)
+	span.SetAttributes(attribute.Int("post.count", len(top)))
+	tracing.End(span, err)
+	return top, err
+}
@@ -0,0 +1,47 @@
+package apikey
+
+import (
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=repository.go -destination=repository_mock.go -package=apikey
+
+type Repository interface {
+	FindByID(id uuid.UUID) (*model.APIKey, error)
+	FindByHash(hash string) (*model.APIKey, error)
+	Create(key *model.APIKey) (*model.APIKey, error)
+	Update(key *model.APIKey) (*model.APIKey, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) FindByID(id uuid.UUID) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.First(&key, "id = ?", id).Error
+	return &key, err
+}
+
+func (r *repository) FindByHash(hash string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.Where("key_hash = ?", hash).First(&key).Error
+	return &key, err
+}
+
+func (r *repository) Create(key *model.APIKey) (*model.APIKey, error) {
+	err := r.db.Create(key).Error
+	return key, database.TranslateError(err)
+}
+
+func (r *repository) Update(key *model.APIKey) (*model.APIKey, error) {
+	err := r.db.Save(key).Error
+	return key, database.TranslateError(err)
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
@@ -0,0 +1,151 @@
+package apikey
+
+import (
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setup(t *testing.T) (*MockRepository, Service) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockRepo := NewMockRepository(ctrl)
+	return mockRepo, NewService(mockRepo)
+}
+
+func TestService_CreateKey(t *testing.T) {
+	mockRepo, service := setup(t)
+	mockRepo.EXPECT().Create(gomock.Any()).
+		DoAndReturn(func(key *model.APIKey) (*model.APIKey, error) {
+			return key, nil
+		})
+
+	userID := uuid.New()
+	key, plain, err := service.CreateKey("deploy", &userID)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plain, keyPrefix))
+	assert.True(t, strings.HasPrefix(plain, key.Prefix))
+	assert.Equal(t, hashKey(plain), key.KeyHash)
+	assert.NotContains(t, key.KeyHash, plain)
+	assert.Equal(t, &userID, key.UserID)
+}
+
+func TestService_CreateKeyBlankName(t *testing.T) {
+	_, service := setup(t)
+
+	_, _, err := service.CreateKey(" ", nil)
+
+	assert.ErrorContains(t, err, "name must not be blank")
+}
+
+func TestService_RevokeKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		expectMock func(repo *MockRepository)
+		wantErr    string
+	}{
+		{
+			name: "success",
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().FindByID(gomock.Any()).Return(&model.APIKey{}, nil)
+				repo.EXPECT().Update(gomock.Any()).
+					DoAndReturn(func(key *model.APIKey) (*model.APIKey, error) {
+						assert.NotNil(t, key.RevokedAt)
+						return key, nil
+					})
+			},
+		},
+		{
+			name: "already revoked",
+			expectMock: func(repo *MockRepository) {
+				now := time.Now()
+				repo.EXPECT().FindByID(gomock.Any()).
+					Return(&model.APIKey{RevokedAt: &now}, nil)
+			},
+		},
+		{
+			name: "not found",
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().FindByID(gomock.Any()).
+					Return(nil, errors.New("record not found"))
+			},
+			wantErr: "not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setup(t)
+			test.expectMock(mockRepo)
+
+			err := service.RevokeKey(uuid.New())
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_Authenticate(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		key        string
+		expectMock func(repo *MockRepository)
+		wantErr    error
+	}{
+		{
+			name: "valid key",
+			key:  keyPrefix + "abc",
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().FindByHash(hashKey(keyPrefix+"abc")).
+					Return(&model.APIKey{}, nil)
+			},
+		},
+		{
+			name:    "missing prefix",
+			key:     "abc",
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name: "unknown key",
+			key:  keyPrefix + "abc",
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().FindByHash(gomock.Any()).
+					Return(&model.APIKey{}, errors.New("record not found"))
+			},
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name: "revoked key",
+			key:  keyPrefix + "abc",
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().FindByHash(gomock.Any()).
+					Return(&model.APIKey{RevokedAt: &now}, nil)
+			},
+			wantErr: ErrInvalidKey,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setup(t)
+			if test.expectMock != nil {
+				test.expectMock(mockRepo)
+			}
+
+			_, err := service.Authenticate(test.key)
+
+			assert.Equal(t, test.wantErr, err)
+		})
+	}
+}
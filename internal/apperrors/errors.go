@@ -1,13 +1,17 @@
 package apperrors
 
 import (
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"net/http"
 )
 
 type NotFoundError struct {
 	Resource string
 	ID       uuid.UUID
+	// Name is set instead of ID when the resource was looked up by name.
+	Name string
 }
 
 type DuplicateError struct {
@@ -18,6 +22,18 @@ type InvalidInputError struct {
 	Message string
 }
 
+type ReferenceNotFoundError struct {
+	Field string
+}
+
+type ConflictError struct {
+	Message string
+}
+
+type ForbiddenError struct {
+	Message string
+}
+
 func (ie *InvalidInputError) Error() string {
 	return ie.Message
 }
@@ -26,7 +42,22 @@ func (de *DuplicateError) Error() string {
 	return fmt.Sprintf("%s already exists", de.Field)
 }
 
+func (re *ReferenceNotFoundError) Error() string {
+	return fmt.Sprintf("%s references a resource that does not exist", re.Field)
+}
+
+func (ce *ConflictError) Error() string {
+	return ce.Message
+}
+
+func (fe *ForbiddenError) Error() string {
+	return fe.Message
+}
+
 func (n *NotFoundError) Error() string {
+	if n.Name != "" {
+		return fmt.Sprintf("%s %q not found", n.Resource, n.Name)
+	}
 	return fmt.Sprintf("%s with ID %s not found", n.Resource, n.ID.String())
 }
 
@@ -34,6 +65,10 @@ func NewNotFoundError(resource string, id uuid.UUID) error {
 	return &NotFoundError{Resource: resource, ID: id}
 }
 
+func NewNotFoundByNameError(resource string, name string) error {
+	return &NotFoundError{Resource: resource, Name: name}
+}
+
 func NewDuplicateError(field string) error {
 	return &DuplicateError{Field: field}
 }
@@ -41,3 +76,43 @@ func NewDuplicateError(field string) error {
 func NewInvalidInputError(msg string) error {
 	return &InvalidInputError{Message: msg}
 }
+
+func NewReferenceNotFoundError(field string) error {
+	return &ReferenceNotFoundError{Field: field}
+}
+
+func NewConflictError(msg string) error {
+	return &ConflictError{Message: msg}
+}
+
+func NewForbiddenError(msg string) error {
+	return &ForbiddenError{Message: msg}
+}
+
+// StatusCode returns the HTTP status for err: 409 for duplicates and
+// conflicts, 404 for missing resources, 400 for invalid input, 403 for
+// requests that may not act, 422 for references to missing resources and
+// 500 for everything else.
+func StatusCode(err error) int {
+	var (
+		de *DuplicateError
+		ne *NotFoundError
+		ie *InvalidInputError
+		re *ReferenceNotFoundError
+		ce *ConflictError
+		fe *ForbiddenError
+	)
+	switch {
+	case errors.As(err, &de), errors.As(err, &ce):
+		return http.StatusConflict
+	case errors.As(err, &ne):
+		return http.StatusNotFound
+	case errors.As(err, &ie):
+		return http.StatusBadRequest
+	case errors.As(err, &fe):
+		return http.StatusForbidden
+	case errors.As(err, &re):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,43 @@
+// Package auth carries the user a request acts as, which is known when the
+// API key of the request is linked to a user.
+package auth
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+)
+
+type contextKey struct{}
+
+// WithUser returns a copy of ctx for requests made as the user with id.
+func WithUser(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// UserID returns the user the request acts as, if any.
+func UserID(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(contextKey{}).(uuid.UUID)
+	return id, ok
+}
+
+// RequireUser returns the user the request acts as or a ForbiddenError
+// when its API key is not linked to one.
+func RequireUser(ctx context.Context) (uuid.UUID, error) {
+	if id, ok := UserID(ctx); ok {
+		return id, nil
+	}
+	return uuid.Nil, apperrors.NewForbiddenError(
+		"the API key is not linked to a user")
+}
+
+// RequireOwner returns a ForbiddenError when the request acts as a user
+// other than owner. Requests whose API key is not linked to a user may act
+// on anything.
+func RequireOwner(ctx context.Context, owner uuid.UUID) error {
+	if id, ok := UserID(ctx); ok && id != owner {
+		return apperrors.NewForbiddenError(
+			"the API key may only change its own user's resources")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package batch
+
+import (
+	"context"
+	"errors"
+)
+
+// Modes of a batch.
+const (
+	// Atomic applies all operations in one transaction, so one failure
+	// rolls back the others.
+	Atomic = "atomic"
+	// BestEffort applies every operation on its own and keeps the ones
+	// that succeed.
+	BestEffort = "best_effort"
+)
+
+// Operations of a batch item.
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+const DefaultMaxItems = 100
+
+// ErrRolledBack is the result of every other operation when an atomic batch
+// fails.
+var ErrRolledBack = errors.New(
+	"not applied because another operation in the batch failed")
+
+// Run applies n operations in order with apply and returns the error of
+// each. In atomic mode apply is given the repository of a transaction
+// started with transaction and the first failure rolls back the whole
+// batch; the other operations then report ErrRolledBack. The returned error
+// is only set when the transaction itself fails.
+func Run[R any](ctx context.Context, repo R,
+	transaction func(context.Context, func(R) error) error, atomic bool,
+	n int, apply func(repo R, i int) error) ([]error, error) {
+	errs := make([]error, n)
+	if !atomic {
+		for i := range n {
+			errs[i] = apply(repo, i)
+		}
+		return errs, nil
+	}
+
+	failed := -1
+	err := transaction(ctx, func(tx R) error {
+		for i := range n {
+			if err := apply(tx, i); err != nil {
+				errs[i] = err
+				failed = i
+				return err
+			}
+		}
+		return nil
+	})
+	if failed >= 0 {
+		for i := range errs {
+			if i != failed {
+				errs[i] = ErrRolledBack
+			}
+		}
+		return errs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
@@ -0,0 +1,96 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// fakeRepo records the operations applied through it.
+type fakeRepo struct {
+	tx      bool
+	applied []int
+}
+
+func TestRun(t *testing.T) {
+	failAt := func(n int) func(repo *fakeRepo, i int) error {
+		return func(repo *fakeRepo, i int) error {
+			if i == n {
+				return errors.New("invalid")
+			}
+			repo.applied = append(repo.applied, i)
+			return nil
+		}
+	}
+	tests := []struct {
+		name        string
+		atomic      bool
+		apply       func(repo *fakeRepo, i int) error
+		txErr       error
+		wantErrs    []error
+		wantApplied []int
+		wantTx      bool
+		wantErr     string
+	}{
+		{
+			name:        "best effort keeps the others",
+			atomic:      false,
+			apply:       failAt(1),
+			wantErrs:    []error{nil, errors.New("invalid"), nil},
+			wantApplied: []int{0, 2},
+		},
+		{
+			name:        "atomic success",
+			atomic:      true,
+			apply:       failAt(-1),
+			wantErrs:    []error{nil, nil, nil},
+			wantApplied: []int{0, 1, 2},
+			wantTx:      true,
+		},
+		{
+			name:   "atomic failure rolls back the others",
+			atomic: true,
+			apply:  failAt(1),
+			wantErrs: []error{ErrRolledBack, errors.New("invalid"),
+				ErrRolledBack},
+			wantApplied: []int{0},
+			wantTx:      true,
+		},
+		{
+			name:    "commit fails",
+			atomic:  true,
+			apply:   failAt(-1),
+			txErr:   errors.New("commit failed"),
+			wantErr: "commit failed",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &fakeRepo{}
+			tx := &fakeRepo{tx: true}
+			transaction := func(ctx context.Context,
+				fn func(*fakeRepo) error) error {
+				if err := fn(tx); err != nil {
+					return err
+				}
+				return test.txErr
+			}
+
+			errs, err := Run(context.Background(), repo, transaction,
+				test.atomic, 3, test.apply)
+
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantErrs, errs)
+			used := repo
+			if test.wantTx {
+				used = tx
+			}
+			assert.Equal(t, test.wantApplied, used.applied)
+		})
+	}
+}
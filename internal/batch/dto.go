@@ -0,0 +1,131 @@
+package batch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"net/http"
+)
+
+type Request struct {
+	// Mode is atomic (the default) or best_effort.
+	Mode       string       `json:"mode" enums:"atomic,best_effort" example:"atomic"`
+	Operations []*Operation `json:"operations"`
+}
+
+// Operation creates a resource from Data, updates the resource with ID
+// from Data or deletes the resource with ID.
+type Operation struct {
+	Op   string          `json:"op" enums:"create,update,delete" example:"create"`
+	ID   uuid.UUID       `json:"id,omitempty" swaggertype:"string" format:"uuid"`
+	Data json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
+
+// Validate checks the mode and that there are between 1 and maxItems
+// operations.
+func (r *Request) Validate(maxItems int) error {
+	if r.Mode != "" && r.Mode != Atomic && r.Mode != BestEffort {
+		return apperrors.NewInvalidInputError(fmt.Sprintf(
+			"mode must be %q or %q", Atomic, BestEffort))
+	}
+	if len(r.Operations) == 0 || len(r.Operations) > maxItems {
+		return apperrors.NewInvalidInputError(fmt.Sprintf(
+			"a batch needs between 1 and %d operations", maxItems))
+	}
+	for i, op := range r.Operations {
+		if op == nil {
+			return apperrors.NewInvalidInputError(
+				fmt.Sprintf("operation %d is empty", i))
+		}
+	}
+	return nil
+}
+
+// Atomic reports whether the batch is all-or-nothing.
+func (r *Request) Atomic() bool {
+	return r.Mode != BestEffort
+}
+
+// Check returns an InvalidInputError unless op names a known operation and
+// carries an ID where one is needed.
+func (op *Operation) Check() error {
+	switch op.Op {
+	case OpCreate:
+		return nil
+	case OpUpdate, OpDelete:
+		if op.ID == uuid.Nil {
+			return apperrors.NewInvalidInputError(
+				fmt.Sprintf("%s needs an id", op.Op))
+		}
+		return nil
+	}
+	return apperrors.NewInvalidInputError(fmt.Sprintf(
+		"op must be %s, %s or %s, got %q", OpCreate, OpUpdate, OpDelete,
+		op.Op))
+}
+
+// Decode unmarshals the data of op into v and validates its binding tags.
+func (op *Operation) Decode(v any) error {
+	if len(op.Data) == 0 {
+		return apperrors.NewInvalidInputError(
+			fmt.Sprintf("%s needs data", op.Op))
+	}
+	if err := json.Unmarshal(op.Data, v); err != nil {
+		return apperrors.NewInvalidInputError("invalid data: " + err.Error())
+	}
+	if err := binding.Validator.ValidateStruct(v); err != nil {
+		return apperrors.NewInvalidInputError("invalid data: " + err.Error())
+	}
+	return nil
+}
+
+type Result struct {
+	Index  int    `json:"index"`
+	Status int    `json:"status" example:"201"`
+	Data   any    `json:"data,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Response struct {
+	Results   []*Result `json:"results"`
+	Succeeded int       `json:"succeeded"`
+	Failed    int       `json:"failed"`
+}
+
+// Add records the outcome of operation index: data on success or err,
+// with the status a single request for op would have had.
+func (r *Response) Add(index int, op string, data any, err error) *Result {
+	result := &Result{Index: index}
+	switch {
+	case errors.Is(err, ErrRolledBack):
+		result.Status = http.StatusFailedDependency
+	case err != nil:
+		result.Status = apperrors.StatusCode(err)
+	case op == OpCreate:
+		result.Status = http.StatusCreated
+	case op == OpDelete:
+		result.Status = http.StatusNoContent
+	default:
+		result.Status = http.StatusOK
+	}
+	if err != nil {
+		result.Error = err.Error()
+		r.Failed++
+	} else {
+		result.Data = data
+		r.Succeeded++
+	}
+	r.Results = append(r.Results, result)
+	return result
+}
+
+// StatusCode is 200 when every operation succeeded and 207 otherwise.
+func (r *Response) StatusCode() int {
+	if r.Failed > 0 {
+		return http.StatusMultiStatus
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,119 @@
+package batch
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestRequest_Validate(t *testing.T) {
+	op := &Operation{Op: OpCreate}
+	tests := []struct {
+		name    string
+		req     Request
+		wantErr string
+	}{
+		{name: "default mode", req: Request{Operations: []*Operation{op}}},
+		{name: "best effort", req: Request{Mode: BestEffort,
+			Operations: []*Operation{op}}},
+		{name: "unknown mode", req: Request{Mode: "partial",
+			Operations: []*Operation{op}},
+			wantErr: `mode must be "atomic" or "best_effort"`},
+		{name: "empty", req: Request{},
+			wantErr: "a batch needs between 1 and 2 operations"},
+		{name: "too many", req: Request{
+			Operations: []*Operation{op, op, op}},
+			wantErr: "a batch needs between 1 and 2 operations"},
+		{name: "null operation", req: Request{
+			Operations: []*Operation{op, nil}},
+			wantErr: "operation 1 is empty"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.req.Validate(2)
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.IsType(t, &apperrors.InvalidInputError{}, err)
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestRequest_Atomic(t *testing.T) {
+	assert.True(t, (&Request{}).Atomic())
+	assert.True(t, (&Request{Mode: Atomic}).Atomic())
+	assert.False(t, (&Request{Mode: BestEffort}).Atomic())
+}
+
+func TestOperation_Check(t *testing.T) {
+	assert.NoError(t, (&Operation{Op: OpCreate}).Check())
+	assert.NoError(t, (&Operation{Op: OpDelete, ID: uuid.New()}).Check())
+	assert.EqualError(t, (&Operation{Op: OpUpdate}).Check(),
+		"update needs an id")
+	assert.EqualError(t, (&Operation{Op: "upsert"}).Check(),
+		`op must be create, update or delete, got "upsert"`)
+}
+
+func TestOperation_Decode(t *testing.T) {
+	type data struct {
+		Name string `json:"name" binding:"required"`
+	}
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "valid", data: `{"name":"alice"}`},
+		{name: "missing", data: ``, wantErr: "create needs data"},
+		{name: "invalid json", data: `{"name":1}`, wantErr: "invalid data"},
+		{name: "failed binding", data: `{}`, wantErr: "invalid data"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			op := &Operation{Op: OpCreate, Data: json.RawMessage(test.data)}
+			var got data
+
+			err := op.Decode(&got)
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, "alice", got.Name)
+				return
+			}
+			assert.IsType(t, &apperrors.InvalidInputError{}, err)
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestResponse_Add(t *testing.T) {
+	resp := &Response{}
+
+	resp.Add(0, OpCreate, "created", nil)
+	resp.Add(1, OpUpdate, "updated", nil)
+	resp.Add(2, OpDelete, nil, nil)
+	resp.Add(3, OpUpdate, nil, apperrors.NewNotFoundError("post", uuid.Nil))
+	resp.Add(4, OpCreate, nil, ErrRolledBack)
+	resp.Add(5, OpCreate, nil, errors.New("db error"))
+
+	statuses := make([]int, len(resp.Results))
+	for i, r := range resp.Results {
+		statuses[i] = r.Status
+	}
+	assert.Equal(t, []int{http.StatusCreated, http.StatusOK,
+		http.StatusNoContent, http.StatusNotFound,
+		http.StatusFailedDependency, http.StatusInternalServerError}, statuses)
+	assert.Equal(t, "created", resp.Results[0].Data)
+	assert.Equal(t, ErrRolledBack.Error(), resp.Results[4].Error)
+	assert.Equal(t, 3, resp.Succeeded)
+	assert.Equal(t, 3, resp.Failed)
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode())
+	assert.Equal(t, http.StatusOK, (&Response{Succeeded: 1}).StatusCode())
+}
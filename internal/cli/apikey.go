@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apikey"
+)
+
+func runAPIKey(args []string, configPath string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("apikey needs a subcommand\n\n%w", errUsage)
+	}
+
+	switch args[0] {
+	case "create":
+		fs := newFlagSet("apikey create")
+		name := fs.String("name", "", "name describing who uses the key")
+		userStr := fs.String("user", "", "ID of the user acting with the key, such keys cannot use admin endpoints")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *name == "" {
+			return fmt.Errorf("apikey create needs -name")
+		}
+		var userID *uuid.UUID
+		if *userStr != "" {
+			id, err := uuid.Parse(*userStr)
+			if err != nil {
+				return fmt.Errorf("apikey create needs -user as a uuid")
+			}
+			userID = &id
+		}
+
+		_, db, err := connect(configPath)
+		if err != nil {
+			return err
+		}
+		service := apikey.NewService(apikey.NewRepository(db))
+		key, plain, err := service.CreateKey(*name, userID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("id:  %s\nkey: %s\n", key.ID, plain)
+		fmt.Println("store the key now, it cannot be shown again")
+		return nil
+	case "revoke":
+		fs := newFlagSet("apikey revoke")
+		idStr := fs.String("id", "", "ID of the key to revoke")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		id, err := uuid.Parse(*idStr)
+		if err != nil {
+			return fmt.Errorf("apikey revoke needs -id as a uuid")
+		}
+
+		_, db, err := connect(configPath)
+		if err != nil {
+			return err
+		}
+		service := apikey.NewService(apikey.NewRepository(db))
+		if err := service.RevokeKey(id); err != nil {
+			return err
+		}
+		fmt.Printf("revoked key %s\n", id)
+		return nil
+	}
+	return fmt.Errorf("unknown apikey subcommand %q\n\n%w", args[0], errUsage)
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"gorm.io/gorm"
+	"os"
+)
+
+const usage = `usage: blog-api [-config file] <command> [arguments]
+
+commands:
+  serve                        start the HTTP server (default)
+  migrate up                   apply all pending migrations
+  migrate down [-steps n]      roll back the last n migrations
+  migrate status               show the applied and latest schema version
+  migrate force <version>      mark the schema clean at a version
+  seed                         insert sample data into an empty database
+  user create -username -email create a user
+  apikey create -name [-user]  create an API key, optionally acting as a user
+  apikey revoke -id            revoke an API key
+  export [-o file]             write all content as NDJSON
+  import [-i file]             upsert content from an NDJSON export
+  wordpress import [-i file]   import authors and posts from a WXR export
+  markdown import -dir dir     upsert posts from Markdown files with front matter
+  markdown export -dir dir     write every post to a Markdown file
+  site build -o dir [-theme]   render all posts into a static HTML site
+
+Settings are read from the YAML file given by -config or CONFIG_FILE, then
+overridden by the environment and a .env file (see internal/config).`
+
+var errUsage = errors.New(usage)
+
+// Run executes the command named by the first argument. Without a command
+// it starts the server, so existing deployments keep working unchanged.
+func Run(args []string) error {
+	global := newFlagSet("blog-api")
+	configPath := global.String("config", os.Getenv("CONFIG_FILE"),
+		"YAML configuration file")
+	global.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	args = global.Args()
+
+	if len(args) == 0 {
+		return runServe(nil, *configPath)
+	}
+
+	command, rest := args[0], args[1:]
+	var err error
+	switch command {
+	case "serve":
+		err = runServe(rest, *configPath)
+	case "migrate":
+		err = runMigrate(rest, *configPath)
+	case "seed":
+		err = runSeed(rest, *configPath)
+	case "user":
+		err = runUser(rest, *configPath)
+	case "apikey":
+		err = runAPIKey(rest, *configPath)
+	case "export":
+		err = runExport(rest, *configPath)
+	case "import":
+		err = runImport(rest, *configPath)
+	case "wordpress":
+		err = runWordPress(rest, *configPath)
+	case "markdown":
+		err = runMarkdown(rest, *configPath)
+	case "site":
+		err = runSite(rest, *configPath)
+	case "help":
+		fmt.Println(usage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n\n%w", command, errUsage)
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
+
+// connect loads and validates the configuration, installs the configured
+// logger and opens the database.
+// Commands call it only after their own arguments have been checked.
+func connect(configPath string) (*config.Config, *gorm.DB, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	logging.Setup(cfg.Log)
+	return cfg, database.ConnectWithRetry(cfg.Database), nil
+}
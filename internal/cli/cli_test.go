@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRun_ArgumentErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "unknown command",
+			args:    []string{"frobnicate"},
+			wantErr: `unknown command "frobnicate"`,
+		},
+		{
+			name:    "migrate without subcommand",
+			args:    []string{"migrate"},
+			wantErr: "migrate needs a subcommand",
+		},
+		{
+			name:    "unknown migrate subcommand",
+			args:    []string{"migrate", "sideways"},
+			wantErr: `unknown migrate subcommand "sideways"`,
+		},
+		{
+			name:    "migrate down with zero steps",
+			args:    []string{"migrate", "down", "-steps", "0"},
+			wantErr: "steps must be at least 1",
+		},
+		{
+			name:    "migrate force without version",
+			args:    []string{"migrate", "force"},
+			wantErr: "needs exactly one version",
+		},
+		{
+			name:    "migrate force with invalid version",
+			args:    []string{"migrate", "force", "latest"},
+			wantErr: `invalid version "latest"`,
+		},
+		{
+			name:    "user without create",
+			args:    []string{"user", "delete"},
+			wantErr: "user needs the create subcommand",
+		},
+		{
+			name:    "user create without email",
+			args:    []string{"user", "create", "-username", "alice"},
+			wantErr: "needs -username and -email",
+		},
+		{
+			name:    "apikey create without name",
+			args:    []string{"apikey", "create"},
+			wantErr: "apikey create needs -name",
+		},
+		{
+			name:    "apikey create with invalid user",
+			args:    []string{"apikey", "create", "-name", "app", "-user", "abc"},
+			wantErr: "needs -user as a uuid",
+		},
+		{
+			name:    "apikey revoke with invalid id",
+			args:    []string{"apikey", "revoke", "-id", "abc"},
+			wantErr: "needs -id as a uuid",
+		},
+		{
+			name:    "wordpress without import",
+			args:    []string{"wordpress", "export"},
+			wantErr: "wordpress needs the import subcommand",
+		},
+		{
+			name:    "markdown without subcommand",
+			args:    []string{"markdown"},
+			wantErr: "markdown needs the import or export subcommand",
+		},
+		{
+			name:    "markdown export without dir",
+			args:    []string{"markdown", "export"},
+			wantErr: "markdown export needs -dir",
+		},
+		{
+			name:    "site build without output",
+			args:    []string{"site", "build"},
+			wantErr: "site build needs -o",
+		},
+		{
+			name:    "site build with unknown theme",
+			args:    []string{"site", "build", "-o", "out", "-theme", "neon"},
+			wantErr: `unknown theme "neon"`,
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"export", "-x"},
+			wantErr: "flag provided but not defined",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Run(test.args)
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestRun_Help(t *testing.T) {
+	assert.NoError(t, Run([]string{"help"}))
+	assert.NoError(t, Run([]string{"seed", "-h"}))
+}
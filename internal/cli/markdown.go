@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/markdown"
+	"os"
+)
+
+func runMarkdown(args []string, configPath string) error {
+	if len(args) == 0 || (args[0] != "import" && args[0] != "export") {
+		return fmt.Errorf("markdown needs the import or export subcommand\n\n%w",
+			errUsage)
+	}
+
+	fs := newFlagSet("markdown " + args[0])
+	dir := fs.String("dir", "", "directory of .md files with front matter")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return fmt.Errorf("markdown %s needs -dir", args[0])
+	}
+
+	_, db, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+	service := markdown.NewService(markdown.NewRepository(db))
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if args[0] == "export" {
+		report, err := service.Export(context.Background(), *dir)
+		if err != nil {
+			return err
+		}
+		return enc.Encode(report)
+	}
+
+	report, err := service.Import(context.Background(), os.DirFS(*dir))
+	if err != nil {
+		return err
+	}
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("%d file(s) failed to import", len(report.Errors))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/database"
+	"strconv"
+)
+
+func runMigrate(args []string, configPath string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("migrate needs a subcommand\n\n%w", errUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		if err := newFlagSet("migrate up").Parse(args[1:]); err != nil {
+			return err
+		}
+		_, db, err := connect(configPath)
+		if err != nil {
+			return err
+		}
+		if err := database.Migrate(db); err != nil {
+			return err
+		}
+		fmt.Println("migrations applied")
+		return nil
+	case "down":
+		fs := newFlagSet("migrate down")
+		steps := fs.Int("steps", 1, "number of migrations to roll back")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *steps < 1 {
+			return fmt.Errorf("steps must be at least 1, got %d", *steps)
+		}
+		_, db, err := connect(configPath)
+		if err != nil {
+			return err
+		}
+		if err := database.MigrateDown(db, *steps); err != nil {
+			return err
+		}
+		fmt.Printf("rolled back %d migration(s)\n", *steps)
+		return nil
+	case "status":
+		if err := newFlagSet("migrate status").Parse(args[1:]); err != nil {
+			return err
+		}
+		_, db, err := connect(configPath)
+		if err != nil {
+			return err
+		}
+		status, err := database.GetMigrationStatus(db)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version: %d\ndirty: %t\nlatest: %d\n",
+			status.Version, status.Dirty, status.Latest)
+		return nil
+	case "force":
+		if len(args) != 2 {
+			return fmt.Errorf("migrate force needs exactly one version")
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		_, db, err := connect(configPath)
+		if err != nil {
+			return err
+		}
+		if err := database.ForceMigrationVersion(db, version); err != nil {
+			return err
+		}
+		fmt.Printf("forced schema version %d\n", version)
+		return nil
+	}
+	return fmt.Errorf("unknown migrate subcommand %q\n\n%w", args[0], errUsage)
+}
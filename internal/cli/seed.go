@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"github.com/pandahawk/blog-api/internal/database"
+)
+
+func runSeed(args []string, configPath string) error {
+	if err := newFlagSet("seed").Parse(args); err != nil {
+		return err
+	}
+	_, db, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+	database.SeedDevData(db)
+	return nil
+}
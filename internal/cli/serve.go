@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/analytics"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/health"
+	"github.com/pandahawk/blog-api/internal/idempotency"
+	"github.com/pandahawk/blog-api/internal/metrics"
+	"github.com/pandahawk/blog-api/internal/server"
+	"github.com/pandahawk/blog-api/internal/tracing"
+	"github.com/pandahawk/blog-api/internal/trending"
+	"github.com/pandahawk/blog-api/middleware"
+	"github.com/pandahawk/blog-api/router"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"log/slog"
+	"time"
+)
+
+func runServe(args []string, configPath string) error {
+	fs := newFlagSet("serve")
+	port := fs.String("port", "", "port to listen on, overrides the configuration")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, db, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+	if *port != "" {
+		cfg.Server.Port = *port
+	}
+	if err := database.Migrate(db); err != nil {
+		return fmt.Errorf("migrations failed: %w", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("flush traces", "error", err)
+		}
+	}()
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		return fmt.Errorf("install query tracing: %w", err)
+	}
+
+	if err := db.Use(metrics.NewGormPlugin()); err != nil {
+		return fmt.Errorf("install query metrics: %w", err)
+	}
+	if err := metrics.RegisterDB(db); err != nil {
+		return fmt.Errorf("register pool metrics: %w", err)
+	}
+
+	monitor := health.NewMonitor()
+
+	if cfg.IsProduction() {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	r := gin.New()
+	r.Use(tracing.Middleware())
+	r.Use(middleware.RequestLogger(slog.Default()))
+	r.Use(middleware.Recovery())
+	r.Use(cors.Default())
+	r.Use(metrics.Middleware())
+
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	var views *analytics.Recorder
+	if cfg.Analytics.Enabled {
+		views = analytics.NewRecorder(analytics.NewRepository(db),
+			cfg.Analytics)
+	}
+	router.SetupRoutes(r, db, cfg, monitor, views)
+	if cfg.Frontend.Enabled {
+		if err := router.SetupFrontend(r, db, cfg.Frontend, views); err != nil {
+			return fmt.Errorf("set up frontend: %w", err)
+		}
+	}
+
+	srv := server.New(cfg.Server, r, db, monitor)
+	srv.AddWorker("idempotency-cleanup", idempotency.CleanupWorker(
+		idempotency.NewService(idempotency.NewRepository(db), cfg.Idempotency),
+		time.Hour))
+	srv.AddWorker("trending-refresh", trending.RefreshWorker(
+		trending.NewTracedService(trending.NewService(
+			trending.NewRepository(db), cfg.Trending)),
+		cfg.Trending.RefreshInterval))
+	if views != nil {
+		srv.AddWorker("view-flush", analytics.FlushWorker(views,
+			cfg.Analytics.FlushInterval))
+	}
+	return srv.Run(context.Background())
+}
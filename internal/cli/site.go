@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/post"
+	"github.com/pandahawk/blog-api/internal/site"
+	"os"
+)
+
+func runSite(args []string, configPath string) error {
+	if len(args) == 0 || args[0] != "build" {
+		return fmt.Errorf("site needs the build subcommand\n\n%w", errUsage)
+	}
+
+	fs := newFlagSet("site build")
+	out := fs.String("o", "", "directory to write the site to")
+	theme := fs.String("theme", site.DefaultTheme, "theme shipped with the binary")
+	title := fs.String("title", "Blog", "title of the site")
+	baseURL := fs.String("base-url", "/",
+		"URL the site is hosted at, absolute for working feed links")
+	perPage := fs.Int("per-page", site.DefaultPerPage, "posts per list page")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *out == "" {
+		return fmt.Errorf("site build needs -o")
+	}
+	if *perPage < 1 {
+		return fmt.Errorf("per-page must be at least 1")
+	}
+	files, err := site.EmbeddedTheme(*theme)
+	if err != nil {
+		return err
+	}
+	t, err := site.LoadTheme(files)
+	if err != nil {
+		return err
+	}
+
+	_, db, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+	generator := site.NewGenerator(post.NewService(post.NewRepository(db)), t,
+		&site.Site{Title: *title, BaseURL: *baseURL})
+	generator.PerPage = *perPage
+	report, err := generator.Build(context.Background(), *out)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
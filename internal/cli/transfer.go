@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/transfer"
+	"io"
+	"os"
+)
+
+func runExport(args []string, configPath string) error {
+	fs := newFlagSet("export")
+	out := fs.String("o", "-", "file to write, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	_, db, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	service := transfer.NewService(transfer.NewRepository(db))
+	return service.Export(context.Background(), w)
+}
+
+func runImport(args []string, configPath string) error {
+	fs := newFlagSet("import")
+	in := fs.String("i", "-", "file to read, - for stdin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	_, db, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	service := transfer.NewService(transfer.NewRepository(db))
+	report, err := service.Import(context.Background(), r)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("%d record(s) failed to import", len(report.Errors))
+	}
+	return nil
+}
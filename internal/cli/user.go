@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/user"
+)
+
+func runUser(args []string, configPath string) error {
+	if len(args) == 0 || args[0] != "create" {
+		return fmt.Errorf("user needs the create subcommand\n\n%w", errUsage)
+	}
+
+	fs := newFlagSet("user create")
+	username := fs.String("username", "", "username of the new user")
+	email := fs.String("email", "", "email of the new user")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *username == "" || *email == "" {
+		return fmt.Errorf("user create needs -username and -email")
+	}
+
+	_, db, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+	service := user.NewService(user.NewRepository(db))
+	u, err := service.CreateUser(context.Background(),
+		&user.CreateUserRequest{Username: *username, Email: *email})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created user %s (%s)\n", u.Username, u.ID)
+	return nil
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/wordpress"
+	"io"
+	"os"
+)
+
+func runWordPress(args []string, configPath string) error {
+	if len(args) == 0 || args[0] != "import" {
+		return fmt.Errorf("wordpress needs the import subcommand\n\n%w",
+			errUsage)
+	}
+
+	fs := newFlagSet("wordpress import")
+	in := fs.String("i", "-", "WXR file to read, - for stdin")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	_, db, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	service := wordpress.NewService(wordpress.NewRepository(db))
+	report, err := service.Import(context.Background(), r)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("%d item(s) failed to import", len(report.Errors))
+	}
+	return nil
+}
@@ -0,0 +1,576 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+type Config struct {
+	Env         string            `yaml:"env"`
+	Server      ServerConfig      `yaml:"server"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Log         LogConfig         `yaml:"log"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Batch       BatchConfig       `yaml:"batch"`
+	Frontend    FrontendConfig    `yaml:"frontend"`
+	Analytics   AnalyticsConfig   `yaml:"analytics"`
+	Trending    TrendingConfig    `yaml:"trending"`
+}
+
+type ServerConfig struct {
+	Port         string        `yaml:"port"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// ShutdownDelay is how long the server keeps serving after reporting
+	// itself not ready, giving load balancers time to stop routing to it.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+	// ShutdownTimeout bounds how long in-flight requests and background
+	// workers may take to finish once shutdown has started.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// HealthCheckTimeout bounds the dependency checks behind /readyz.
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout"`
+}
+
+type DatabaseConfig struct {
+	Host            string        `yaml:"host"`
+	Port            string        `yaml:"port"`
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	Name            string        `yaml:"name"`
+	SSLMode         string        `yaml:"sslmode"`
+	ConnectAttempts int           `yaml:"connect_attempts"`
+	ConnectDelay    time.Duration `yaml:"connect_delay"`
+}
+
+type AuthConfig struct {
+	APIKey string `yaml:"api_key"`
+}
+
+// Log formats.
+const (
+	LogJSON = "json"
+	LogText = "text"
+)
+
+type LogConfig struct {
+	// Level is one of debug, info, warn or error.
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// Rate limit stores.
+const (
+	RateLimitMemory   = "memory"
+	RateLimitPostgres = "postgres"
+)
+
+// RateLimitConfig holds the token bucket budgets applied per client. Read
+// covers GET, HEAD and OPTIONS requests and Write everything else; Groups
+// overrides either budget for a route group such as "posts".
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Store is memory for a single replica or postgres to share budgets
+	// between replicas.
+	Store  string                    `yaml:"store"`
+	Read   RateLimitPolicy           `yaml:"read"`
+	Write  RateLimitPolicy           `yaml:"write"`
+	Groups map[string]RateLimitGroup `yaml:"groups"`
+}
+
+// RateLimitPolicy allows Requests per Per on average with bursts of up to
+// Burst requests. A zero Burst means Requests.
+type RateLimitPolicy struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+}
+
+type RateLimitGroup struct {
+	Read  *RateLimitPolicy `yaml:"read"`
+	Write *RateLimitPolicy `yaml:"write"`
+}
+
+// Policies returns the read and write budgets for a route group.
+func (c RateLimitConfig) Policies(group string) (RateLimitPolicy, RateLimitPolicy) {
+	read, write := c.Read, c.Write
+	if g, ok := c.Groups[group]; ok {
+		if g.Read != nil {
+			read = *g.Read
+		}
+		if g.Write != nil {
+			write = *g.Write
+		}
+	}
+	return read, write
+}
+
+func (p RateLimitPolicy) validate(name string) error {
+	if p.Requests < 1 || p.Per <= 0 || p.Burst < 0 {
+		return fmt.Errorf("rate limit %s needs at least 1 request per "+
+			"positive period and a burst that is not negative", name)
+	}
+	return nil
+}
+
+type IdempotencyConfig struct {
+	// TTL is how long the response to a request with an Idempotency-Key is
+	// kept for replay.
+	TTL time.Duration `yaml:"ttl"`
+	// WaitTimeout bounds how long a retry waits for the first request with
+	// the same key to finish before it is rejected with 409.
+	WaitTimeout time.Duration `yaml:"wait_timeout"`
+	// LockTimeout is how long a key may stay in progress before it is
+	// considered abandoned, e.g. by a crashed replica, and can be reused.
+	LockTimeout time.Duration `yaml:"lock_timeout"`
+}
+
+type BatchConfig struct {
+	// MaxItems is the most operations a batch request may hold.
+	MaxItems int `yaml:"max_items"`
+}
+
+// FrontendConfig controls the public HTML pages served next to the API.
+type FrontendConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Path is where the pages are mounted, "/" or a prefix such as "/blog".
+	Path  string `yaml:"path"`
+	Title string `yaml:"title"`
+	// BaseURL is prepended to links, Path when empty. Feeds need an
+	// absolute URL.
+	BaseURL string `yaml:"base_url"`
+	// Theme names a theme shipped with the binary and ThemeDir a directory
+	// whose files replace those of the theme.
+	Theme    string `yaml:"theme"`
+	ThemeDir string `yaml:"theme_dir"`
+	PerPage  int    `yaml:"per_page"`
+	// CacheMaxAge is how long clients and proxies may cache a page.
+	CacheMaxAge time.Duration `yaml:"cache_max_age"`
+}
+
+// AnalyticsConfig controls how the views of posts are counted. The
+// analytics endpoints answer either way.
+type AnalyticsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// DedupeWindow is how long repeated views of a post by one visitor
+	// count once.
+	DedupeWindow time.Duration `yaml:"dedupe_window"`
+	// FlushInterval is how often the buffered views are written to the
+	// database.
+	FlushInterval time.Duration `yaml:"flush_interval"`
+	// BotUserAgents lists user agent substrings, matched ignoring case,
+	// whose views are not counted in addition to the built-in ones.
+	BotUserAgents []string `yaml:"bot_user_agents"`
+}
+
+// TrendingConfig controls the ranking of trending posts, which scores each
+// view and reaction less the older it is.
+type TrendingConfig struct {
+	// Window is how far back views and reactions count.
+	Window time.Duration `yaml:"window"`
+	// HalfLife is the age at which a view or reaction counts half.
+	HalfLife time.Duration `yaml:"half_life"`
+	// RefreshInterval is how often the ranking is recomputed.
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+}
+
+// Tracing exporters.
+const (
+	TracingNone   = "none"
+	TracingOTLP   = "otlp"
+	TracingStdout = "stdout"
+	TracingFile   = "file"
+)
+
+// TracingConfig selects where spans are exported. The OTLP exporter reads
+// its endpoint, headers and protocol options from the standard
+// OTEL_EXPORTER_OTLP_* variables.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`
+	File        string  `yaml:"file"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+func defaults() *Config {
+	return &Config{
+		Env: EnvDevelopment,
+		Server: ServerConfig{
+			Port:               "8080",
+			ReadTimeout:        15 * time.Second,
+			WriteTimeout:       30 * time.Second,
+			IdleTimeout:        60 * time.Second,
+			ShutdownTimeout:    20 * time.Second,
+			HealthCheckTimeout: 2 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            "5432",
+			SSLMode:         "disable",
+			ConnectAttempts: 5,
+			ConnectDelay:    5 * time.Second,
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingNone,
+			ServiceName: "blog-api",
+			SampleRatio: 1,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: LogJSON,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   RateLimitMemory,
+			Read:    RateLimitPolicy{Requests: 300, Per: time.Minute},
+			Write:   RateLimitPolicy{Requests: 60, Per: time.Minute},
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:         24 * time.Hour,
+			WaitTimeout: 5 * time.Second,
+			LockTimeout: time.Minute,
+		},
+		Batch: BatchConfig{MaxItems: 100},
+		Frontend: FrontendConfig{
+			Path:        "/",
+			Title:       "Blog",
+			Theme:       "default",
+			PerPage:     10,
+			CacheMaxAge: 5 * time.Minute,
+		},
+		Analytics: AnalyticsConfig{
+			Enabled:       true,
+			DedupeWindow:  30 * time.Minute,
+			FlushInterval: 10 * time.Second,
+		},
+		Trending: TrendingConfig{
+			Window:          7 * 24 * time.Hour,
+			HalfLife:        24 * time.Hour,
+			RefreshInterval: 15 * time.Minute,
+		},
+	}
+}
+
+// Load builds the configuration from, in increasing precedence, built-in
+// defaults, the YAML file at path (skipped when path is empty) and the
+// environment. A .env file in the working directory is loaded into the
+// environment first without overriding variables that are already set.
+func Load(path string) (*Config, error) {
+	_ = godotenv.Load()
+
+	cfg := defaults()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) applyEnv() error {
+	setString(&c.Env, "APP_ENV")
+	setString(&c.Server.Port, "PORT")
+	setString(&c.Database.Host, "DB_HOST")
+	setString(&c.Database.Port, "DB_PORT")
+	setString(&c.Database.User, "DB_USER")
+	setString(&c.Database.Password, "DB_PASSWORD")
+	setString(&c.Database.Name, "DB_NAME")
+	setString(&c.Database.SSLMode, "DB_SSLMODE")
+	setString(&c.Auth.APIKey, "API_KEY")
+	setString(&c.Tracing.Exporter, "TRACING_EXPORTER")
+	setString(&c.Tracing.File, "TRACING_FILE")
+	setString(&c.Tracing.ServiceName, "OTEL_SERVICE_NAME")
+	setString(&c.Log.Level, "LOG_LEVEL")
+	setString(&c.Log.Format, "LOG_FORMAT")
+	setString(&c.RateLimit.Store, "RATE_LIMIT_STORE")
+	setString(&c.Frontend.Path, "FRONTEND_PATH")
+	setString(&c.Frontend.Title, "FRONTEND_TITLE")
+	setString(&c.Frontend.BaseURL, "FRONTEND_BASE_URL")
+	setString(&c.Frontend.Theme, "FRONTEND_THEME")
+	setString(&c.Frontend.ThemeDir, "FRONTEND_THEME_DIR")
+	setStrings(&c.Analytics.BotUserAgents, "ANALYTICS_BOT_USER_AGENTS")
+
+	return errors.Join(
+		setDuration(&c.Server.ReadTimeout, "SERVER_READ_TIMEOUT"),
+		setDuration(&c.Server.WriteTimeout, "SERVER_WRITE_TIMEOUT"),
+		setDuration(&c.Server.IdleTimeout, "SERVER_IDLE_TIMEOUT"),
+		setDuration(&c.Server.ShutdownDelay, "SERVER_SHUTDOWN_DELAY"),
+		setDuration(&c.Server.ShutdownTimeout, "SERVER_SHUTDOWN_TIMEOUT"),
+		setDuration(&c.Server.HealthCheckTimeout, "SERVER_HEALTH_CHECK_TIMEOUT"),
+		setInt(&c.Database.ConnectAttempts, "DB_CONNECT_ATTEMPTS"),
+		setDuration(&c.Database.ConnectDelay, "DB_CONNECT_DELAY"),
+		setFloat(&c.Tracing.SampleRatio, "TRACING_SAMPLE_RATIO"),
+		setBool(&c.RateLimit.Enabled, "RATE_LIMIT_ENABLED"),
+		setInt(&c.RateLimit.Read.Requests, "RATE_LIMIT_READ_REQUESTS"),
+		setDuration(&c.RateLimit.Read.Per, "RATE_LIMIT_READ_PER"),
+		setInt(&c.RateLimit.Read.Burst, "RATE_LIMIT_READ_BURST"),
+		setInt(&c.RateLimit.Write.Requests, "RATE_LIMIT_WRITE_REQUESTS"),
+		setDuration(&c.RateLimit.Write.Per, "RATE_LIMIT_WRITE_PER"),
+		setInt(&c.RateLimit.Write.Burst, "RATE_LIMIT_WRITE_BURST"),
+		setDuration(&c.Idempotency.TTL, "IDEMPOTENCY_TTL"),
+		setDuration(&c.Idempotency.WaitTimeout, "IDEMPOTENCY_WAIT_TIMEOUT"),
+		setDuration(&c.Idempotency.LockTimeout, "IDEMPOTENCY_LOCK_TIMEOUT"),
+		setInt(&c.Batch.MaxItems, "BATCH_MAX_ITEMS"),
+		setBool(&c.Frontend.Enabled, "FRONTEND_ENABLED"),
+		setInt(&c.Frontend.PerPage, "FRONTEND_PER_PAGE"),
+		setDuration(&c.Frontend.CacheMaxAge, "FRONTEND_CACHE_MAX_AGE"),
+		setBool(&c.Analytics.Enabled, "ANALYTICS_ENABLED"),
+		setDuration(&c.Analytics.DedupeWindow, "ANALYTICS_DEDUPE_WINDOW"),
+		setDuration(&c.Analytics.FlushInterval, "ANALYTICS_FLUSH_INTERVAL"),
+		setDuration(&c.Trending.Window, "TRENDING_WINDOW"),
+		setDuration(&c.Trending.HalfLife, "TRENDING_HALF_LIFE"),
+		setDuration(&c.Trending.RefreshInterval, "TRENDING_REFRESH_INTERVAL"),
+	)
+}
+
+func setString(dst *string, key string) {
+	if v, ok := os.LookupEnv(key); ok {
+		*dst = v
+	}
+}
+
+// setStrings reads a comma separated list, leaving out blank entries.
+func setStrings(dst *[]string, key string) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	*dst = nil
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*dst = append(*dst, s)
+		}
+	}
+}
+
+func setInt(dst *int, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%s must be an integer, got %q", key, v)
+	}
+	*dst = n
+	return nil
+}
+
+func setBool(dst *bool, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("%s must be true or false, got %q", key, v)
+	}
+	*dst = b
+	return nil
+}
+
+func setFloat(dst *float64, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("%s must be a number, got %q", key, v)
+	}
+	*dst = f
+	return nil
+}
+
+func setDuration(dst *time.Duration, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s must be a duration such as 5s, got %q", key, v)
+	}
+	*dst = d
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		errs = append(errs, fmt.Errorf("env must be %q or %q, got %q",
+			EnvDevelopment, EnvProduction, c.Env))
+	}
+	if err := validatePort("server port", c.Server.Port); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Server.ReadTimeout <= 0 || c.Server.WriteTimeout <= 0 ||
+		c.Server.IdleTimeout <= 0 || c.Server.ShutdownTimeout <= 0 ||
+		c.Server.HealthCheckTimeout <= 0 {
+		errs = append(errs, errors.New("server read, write, idle, shutdown "+
+			"and health check timeouts must be positive"))
+	}
+	if c.Server.ShutdownDelay < 0 {
+		errs = append(errs, errors.New("server shutdown delay must not be negative"))
+	}
+	if err := validatePort("database port", c.Database.Port); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Database.Host == "" {
+		errs = append(errs, errors.New("database host is required"))
+	}
+	if c.Database.User == "" {
+		errs = append(errs, errors.New("database user is required"))
+	}
+	if c.Database.Name == "" {
+		errs = append(errs, errors.New("database name is required"))
+	}
+	if c.Database.ConnectAttempts < 1 {
+		errs = append(errs, errors.New("database connect attempts must be at least 1"))
+	}
+	if c.Database.ConnectDelay < 0 {
+		errs = append(errs, errors.New("database connect delay must not be negative"))
+	}
+	if c.Env == EnvProduction && c.Auth.APIKey == "" {
+		errs = append(errs, errors.New("an API key is required in production"))
+	}
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingOTLP, TracingStdout:
+	case TracingFile:
+		if c.Tracing.File == "" {
+			errs = append(errs, errors.New("tracing file is required "+
+				"for the file exporter"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing exporter must be one of "+
+			"%s, %s, %s or %s, got %q", TracingNone, TracingOTLP,
+			TracingStdout, TracingFile, c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, errors.New("tracing sample ratio must be "+
+			"between 0 and 1"))
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("log level must be debug, info, "+
+			"warn or error, got %q", c.Log.Level))
+	}
+	if c.Log.Format != LogJSON && c.Log.Format != LogText {
+		errs = append(errs, fmt.Errorf("log format must be %q or %q, got %q",
+			LogJSON, LogText, c.Log.Format))
+	}
+	if c.Idempotency.TTL <= 0 || c.Idempotency.WaitTimeout < 0 ||
+		c.Idempotency.LockTimeout <= 0 {
+		errs = append(errs, errors.New("idempotency ttl and lock timeout "+
+			"must be positive and the wait timeout must not be negative"))
+	}
+	if c.Batch.MaxItems < 1 {
+		errs = append(errs, errors.New("batch max items must be at least 1"))
+	}
+	if c.RateLimit.Enabled {
+		errs = append(errs, c.RateLimit.validate())
+	}
+	if c.Frontend.Enabled {
+		errs = append(errs, c.Frontend.validate())
+	}
+	if c.Analytics.Enabled && (c.Analytics.DedupeWindow < 0 ||
+		c.Analytics.FlushInterval <= 0) {
+		errs = append(errs, errors.New("analytics flush interval must be "+
+			"positive and the dedupe window must not be negative"))
+	}
+	if c.Trending.Window <= 0 || c.Trending.HalfLife <= 0 ||
+		c.Trending.RefreshInterval <= 0 {
+		errs = append(errs, errors.New("trending window, half life and "+
+			"refresh interval must be positive"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return nil
+}
+
+func (c RateLimitConfig) validate() error {
+	var errs []error
+	if c.Store != RateLimitMemory && c.Store != RateLimitPostgres {
+		errs = append(errs, fmt.Errorf("rate limit store must be %q or %q, "+
+			"got %q", RateLimitMemory, RateLimitPostgres, c.Store))
+	}
+	errs = append(errs, c.Read.validate("read"), c.Write.validate("write"))
+	for name, g := range c.Groups {
+		if g.Read != nil {
+			errs = append(errs, g.Read.validate(name+" read"))
+		}
+		if g.Write != nil {
+			errs = append(errs, g.Write.validate(name+" write"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c FrontendConfig) validate() error {
+	var errs []error
+	if !strings.HasPrefix(c.Path, "/") {
+		errs = append(errs, fmt.Errorf("frontend path must start with /, "+
+			"got %q", c.Path))
+	}
+	if c.Path == "/api" || strings.HasPrefix(c.Path, "/api/") {
+		errs = append(errs, errors.New("frontend path must not be below /api"))
+	}
+	if c.Theme == "" {
+		errs = append(errs, errors.New("frontend theme is required"))
+	}
+	if c.PerPage < 1 {
+		errs = append(errs, errors.New("frontend per page must be at least 1"))
+	}
+	if c.CacheMaxAge < 0 {
+		errs = append(errs, errors.New("frontend cache max age must not "+
+			"be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func validatePort(name string, port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%s must be a number between 1 and 65535, got %q",
+			name, port)
+	}
+	return nil
+}
+
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		d.Host, d.User, d.Password, d.Name, d.Port, d.SSLMode,
+	)
+}
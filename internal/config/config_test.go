@@ -0,0 +1,285 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setRequiredEnv clears every variable Load reads, so the developer's shell
+// does not leak into the test, and sets the ones without a default.
+func setRequiredEnv(t *testing.T) {
+	for _, key := range []string{"APP_ENV", "PORT", "DB_HOST", "DB_PORT",
+		"DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE", "API_KEY",
+		"DB_CONNECT_ATTEMPTS", "DB_CONNECT_DELAY", "SERVER_READ_TIMEOUT",
+		"SERVER_WRITE_TIMEOUT", "SERVER_IDLE_TIMEOUT", "SERVER_SHUTDOWN_DELAY",
+		"SERVER_SHUTDOWN_TIMEOUT", "SERVER_HEALTH_CHECK_TIMEOUT",
+		"TRACING_EXPORTER", "TRACING_FILE", "OTEL_SERVICE_NAME",
+		"TRACING_SAMPLE_RATIO", "LOG_LEVEL", "LOG_FORMAT", "RATE_LIMIT_ENABLED",
+		"RATE_LIMIT_STORE", "RATE_LIMIT_READ_REQUESTS", "RATE_LIMIT_READ_PER",
+		"RATE_LIMIT_READ_BURST", "RATE_LIMIT_WRITE_REQUESTS",
+		"RATE_LIMIT_WRITE_PER", "RATE_LIMIT_WRITE_BURST", "IDEMPOTENCY_TTL",
+		"IDEMPOTENCY_WAIT_TIMEOUT", "IDEMPOTENCY_LOCK_TIMEOUT",
+		"BATCH_MAX_ITEMS", "FRONTEND_ENABLED", "FRONTEND_PATH",
+		"FRONTEND_TITLE", "FRONTEND_BASE_URL", "FRONTEND_THEME",
+		"FRONTEND_THEME_DIR", "FRONTEND_PER_PAGE", "FRONTEND_CACHE_MAX_AGE",
+		"ANALYTICS_ENABLED", "ANALYTICS_DEDUPE_WINDOW",
+		"ANALYTICS_FLUSH_INTERVAL", "ANALYTICS_BOT_USER_AGENTS",
+		"TRENDING_WINDOW", "TRENDING_HALF_LIFE", "TRENDING_REFRESH_INTERVAL"} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+	t.Setenv("DB_USER", "admin")
+	t.Setenv("DB_NAME", "blog")
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	setRequiredEnv(t)
+
+	cfg, err := Load("")
+
+	require.NoError(t, err)
+	assert.Equal(t, EnvDevelopment, cfg.Env)
+	assert.Equal(t, "8080", cfg.Server.Port)
+	assert.Equal(t, 20*time.Second, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, "localhost", cfg.Database.Host)
+	assert.Equal(t, 5, cfg.Database.ConnectAttempts)
+	assert.Equal(t, 5*time.Second, cfg.Database.ConnectDelay)
+	assert.Equal(t, TracingNone, cfg.Tracing.Exporter)
+	assert.Equal(t, 1.0, cfg.Tracing.SampleRatio)
+	assert.Equal(t, "info", cfg.Log.Level)
+	assert.Equal(t, LogJSON, cfg.Log.Format)
+	assert.Equal(t, 24*time.Hour, cfg.Idempotency.TTL)
+	assert.Equal(t, 100, cfg.Batch.MaxItems)
+	assert.False(t, cfg.Frontend.Enabled)
+	assert.Equal(t, "/", cfg.Frontend.Path)
+	assert.Equal(t, 5*time.Minute, cfg.Frontend.CacheMaxAge)
+	assert.True(t, cfg.Analytics.Enabled)
+	assert.Equal(t, 30*time.Minute, cfg.Analytics.DedupeWindow)
+	assert.Empty(t, cfg.Analytics.BotUserAgents)
+	assert.Equal(t, 7*24*time.Hour, cfg.Trending.Window)
+	assert.Equal(t, 24*time.Hour, cfg.Trending.HalfLife)
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	path := writeFile(t, `
+env: production
+server:
+  port: "9000"
+database:
+  host: db.internal
+  user: fromfile
+  name: blog
+  connect_delay: 2s
+auth:
+  api_key: file-key
+`)
+	setRequiredEnv(t)
+	require.NoError(t, os.Unsetenv("DB_NAME"))
+	t.Setenv("DB_USER", "fromenv")
+	t.Setenv("API_KEY", "env-key")
+
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.True(t, cfg.IsProduction())
+	assert.Equal(t, "9000", cfg.Server.Port)
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, "fromenv", cfg.Database.User)
+	assert.Equal(t, 2*time.Second, cfg.Database.ConnectDelay)
+	assert.Equal(t, "env-key", cfg.Auth.APIKey)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		file    string
+		wantErr []string
+	}{
+		{
+			name:    "production without api key",
+			env:     map[string]string{"APP_ENV": "production"},
+			wantErr: []string{"an API key is required in production"},
+		},
+		{
+			name: "invalid values are reported together",
+			env: map[string]string{
+				"APP_ENV": "staging",
+				"PORT":    "http",
+				"DB_NAME": "",
+			},
+			wantErr: []string{
+				`env must be "development" or "production", got "staging"`,
+				`server port must be a number between 1 and 65535, got "http"`,
+				"database name is required",
+			},
+		},
+		{
+			name:    "invalid duration",
+			env:     map[string]string{"DB_CONNECT_DELAY": "5"},
+			wantErr: []string{"DB_CONNECT_DELAY must be a duration"},
+		},
+		{
+			name:    "zero timeout",
+			env:     map[string]string{"SERVER_WRITE_TIMEOUT": "0s"},
+			wantErr: []string{"health check timeouts must be positive"},
+		},
+		{
+			name:    "file exporter without file",
+			env:     map[string]string{"TRACING_EXPORTER": "file"},
+			wantErr: []string{"tracing file is required"},
+		},
+		{
+			name: "invalid tracing settings",
+			env: map[string]string{
+				"TRACING_EXPORTER":     "jaeger",
+				"TRACING_SAMPLE_RATIO": "2",
+			},
+			wantErr: []string{
+				`tracing exporter must be one of none, otlp, stdout or file, got "jaeger"`,
+				"tracing sample ratio must be between 0 and 1",
+			},
+		},
+		{
+			name: "invalid log settings",
+			env:  map[string]string{"LOG_LEVEL": "verbose", "LOG_FORMAT": "xml"},
+			wantErr: []string{
+				`log level must be debug, info, warn or error, got "verbose"`,
+				`log format must be "json" or "text", got "xml"`,
+			},
+		},
+		{
+			name: "invalid rate limits",
+			env: map[string]string{
+				"RATE_LIMIT_STORE":          "redis",
+				"RATE_LIMIT_WRITE_REQUESTS": "0",
+			},
+			file: `
+rate_limit:
+  groups:
+    posts:
+      read: {requests: 10, per: 0s}
+`,
+			wantErr: []string{
+				`rate limit store must be "memory" or "postgres", got "redis"`,
+				"rate limit write needs at least 1 request",
+				"rate limit posts read needs at least 1 request",
+			},
+		},
+		{
+			name:    "zero idempotency ttl",
+			env:     map[string]string{"IDEMPOTENCY_TTL": "0s"},
+			wantErr: []string{"idempotency ttl and lock timeout must be positive"},
+		},
+		{
+			name:    "zero batch max items",
+			env:     map[string]string{"BATCH_MAX_ITEMS": "0"},
+			wantErr: []string{"batch max items must be at least 1"},
+		},
+		{
+			name: "invalid frontend",
+			env: map[string]string{"FRONTEND_ENABLED": "true",
+				"FRONTEND_PATH": "/api/pages", "FRONTEND_PER_PAGE": "0"},
+			wantErr: []string{"frontend path must not be below /api",
+				"frontend per page must be at least 1"},
+		},
+		{
+			name:    "zero analytics flush interval",
+			env:     map[string]string{"ANALYTICS_FLUSH_INTERVAL": "0s"},
+			wantErr: []string{"analytics flush interval must be positive"},
+		},
+		{
+			name:    "zero trending half life",
+			env:     map[string]string{"TRENDING_HALF_LIFE": "0s"},
+			wantErr: []string{"trending window, half life and refresh interval must be positive"},
+		},
+		{
+			name:    "invalid boolean",
+			env:     map[string]string{"RATE_LIMIT_ENABLED": "sometimes"},
+			wantErr: []string{"RATE_LIMIT_ENABLED must be true or false"},
+		},
+		{
+			name:    "invalid yaml",
+			file:    "server: [",
+			wantErr: []string{"parse config file"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+			path := ""
+			if test.file != "" {
+				path = writeFile(t, test.file)
+			}
+
+			_, err := Load(path)
+
+			require.Error(t, err)
+			for _, want := range test.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestLoad_BotUserAgents(t *testing.T) {
+	path := writeFile(t, `
+analytics:
+  bot_user_agents: [uptime]
+`)
+	setRequiredEnv(t)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"uptime"}, cfg.Analytics.BotUserAgents)
+
+	t.Setenv("ANALYTICS_BOT_USER_AGENTS", " Pingdom, ,statuscake")
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Pingdom", "statuscake"},
+		cfg.Analytics.BotUserAgents)
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	setRequiredEnv(t)
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+
+	assert.ErrorContains(t, err, "read config file")
+}
+
+func TestRateLimitConfig_Policies(t *testing.T) {
+	path := writeFile(t, `
+rate_limit:
+  write: {requests: 30, per: 1m}
+  groups:
+    posts:
+      write: {requests: 10, per: 1m, burst: 2}
+`)
+	setRequiredEnv(t)
+	t.Setenv("RATE_LIMIT_READ_REQUESTS", "100")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	read, write := cfg.RateLimit.Policies("users")
+	assert.Equal(t, RateLimitPolicy{Requests: 100, Per: time.Minute}, read)
+	assert.Equal(t, RateLimitPolicy{Requests: 30, Per: time.Minute}, write)
+
+	read, write = cfg.RateLimit.Policies("posts")
+	assert.Equal(t, RateLimitPolicy{Requests: 100, Per: time.Minute}, read)
+	assert.Equal(t, RateLimitPolicy{Requests: 10, Per: time.Minute, Burst: 2}, write)
+}
@@ -1,44 +1,31 @@
 package database
 
 import (
-	"fmt"
-	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"log"
+	"log/slog"
 	"os"
 	"time"
 )
 
-func ConnectWithRetry(maxAttempts int, delay time.Duration) *gorm.DB {
+func ConnectWithRetry(cfg config.DatabaseConfig) *gorm.DB {
 	var db *gorm.DB
 	var err error
 
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	dbname := os.Getenv("DB_NAME")
-
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		host, user, password, dbname, port,
-	)
-
-	for i := 0; i < maxAttempts; i++ {
-		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	for i := 0; i < cfg.ConnectAttempts; i++ {
+		db, err = gorm.Open(postgres.Open(cfg.DSN()),
+			&gorm.Config{Logger: newGormLogger()})
 		if err == nil {
-			log.Println("successfully connected to database")
-			if err := db.AutoMigrate(&model.User{}, &model.Post{}); err != nil {
-				log.Fatalf("AutoMigrate failed: %v", err)
-			}
-			log.Println("AutoMigrate successful")
-			SeedDevData(db)
+			slog.Info("connected to database", "host", cfg.Host, "name", cfg.Name)
 			return db
 		}
-		log.Printf("Failed to connect to DB (attempt %d/%d): %v", i+1, maxAttempts, err)
-		time.Sleep(delay)
+		slog.Warn("failed to connect to database", "attempt", i+1,
+			"attempts", cfg.ConnectAttempts, "error", err)
+		time.Sleep(cfg.ConnectDelay)
 	}
-	log.Fatalf("Could not connect to database after %d attempts: %v", maxAttempts, err)
+	slog.Error("could not connect to database", "attempts",
+		cfg.ConnectAttempts, "error", err)
+	os.Exit(1)
 	return nil
 }
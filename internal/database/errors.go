@@ -0,0 +1,71 @@
+package database
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"regexp"
+)
+
+// SQLSTATE codes of the constraint violations translated by TranslateError.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+	notNullViolation    = "23502"
+	stringDataTooLong   = "22001"
+)
+
+// constraintFields maps constraint names to the request field they guard.
+// Both the names created by the migrations and the ones gorm generates are
+// listed so the mapping holds regardless of how the schema was created.
+var constraintFields = map[string]string{
+	"users_username_key":         "username",
+	"uni_users_username":         "username",
+	"users_email_key":            "email",
+	"uni_users_email":            "email",
+	"fk_users_posts":             "author_id",
+	"fk_posts_user":              "author_id",
+	"fk_api_keys_user":           "user",
+	"fk_reading_list_items_post": "post_id",
+}
+
+var detailKeyPattern = regexp.MustCompile(`Key \(([^)]+)\)=`)
+
+// TranslateError converts Postgres constraint violations into the matching
+// apperrors type. Errors that are not a *pgconn.PgError, or carry a code we
+// do not handle, are returned unchanged.
+func TranslateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case uniqueViolation:
+		return apperrors.NewDuplicateError(violatedField(pgErr))
+	case foreignKeyViolation:
+		return apperrors.NewReferenceNotFoundError(violatedField(pgErr))
+	case notNullViolation:
+		return apperrors.NewInvalidInputError(
+			violatedField(pgErr) + " must not be empty")
+	case stringDataTooLong:
+		return apperrors.NewInvalidInputError("value too long")
+	}
+	return err
+}
+
+func violatedField(pgErr *pgconn.PgError) string {
+	if field, ok := constraintFields[pgErr.ConstraintName]; ok {
+		return field
+	}
+	if pgErr.ColumnName != "" {
+		return pgErr.ColumnName
+	}
+	if m := detailKeyPattern.FindStringSubmatch(pgErr.Detail); m != nil {
+		return m[1]
+	}
+	if pgErr.ConstraintName != "" {
+		return pgErr.ConstraintName
+	}
+	return "value"
+}
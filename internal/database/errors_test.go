@@ -0,0 +1,74 @@
+package database
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTranslateError(t *testing.T) {
+	plain := errors.New("connection refused")
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{
+			name: "unique violation by constraint name",
+			err: &pgconn.PgError{Code: "23505",
+				ConstraintName: "users_username_key"},
+			wantErr: apperrors.NewDuplicateError("username"),
+		},
+		{
+			name: "unique violation by gorm constraint name",
+			err: &pgconn.PgError{Code: "23505",
+				ConstraintName: "uni_users_email"},
+			wantErr: apperrors.NewDuplicateError("email"),
+		},
+		{
+			name: "unique violation falls back to detail",
+			err: &pgconn.PgError{Code: "23505",
+				ConstraintName: "posts_slug_key",
+				Detail:         "Key (slug)=(hello) already exists."},
+			wantErr: apperrors.NewDuplicateError("slug"),
+		},
+		{
+			name: "foreign key violation",
+			err: &pgconn.PgError{Code: "23503",
+				ConstraintName: "fk_users_posts"},
+			wantErr: apperrors.NewReferenceNotFoundError("author_id"),
+		},
+		{
+			name:    "not null violation",
+			err:     &pgconn.PgError{Code: "23502", ColumnName: "title"},
+			wantErr: apperrors.NewInvalidInputError("title must not be empty"),
+		},
+		{
+			name:    "value too long",
+			err:     &pgconn.PgError{Code: "22001"},
+			wantErr: apperrors.NewInvalidInputError("value too long"),
+		},
+		{
+			name:    "unhandled code is passed through",
+			err:     &pgconn.PgError{Code: "40001"},
+			wantErr: &pgconn.PgError{Code: "40001"},
+		},
+		{
+			name:    "non postgres error is passed through",
+			err:     plain,
+			wantErr: plain,
+		},
+		{
+			name:    "nil",
+			err:     nil,
+			wantErr: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.wantErr, TranslateError(test.err))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/tracing"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"log/slog"
+	"time"
+)
+
+const slowQueryThreshold = 200 * time.Millisecond
+
+// gormLogger sends gorm's output to the request-scoped slog logger. Failed
+// and slow queries are logged with literals stripped from the SQL, since
+// gorm renders statements with their bound values inlined.
+type gormLogger struct {
+	level logger.LogLevel
+}
+
+func newGormLogger() logger.Interface {
+	return &gormLogger{level: logger.Warn}
+}
+
+func (l *gormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &gormLogger{level: level}
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, args ...any) {
+	if l.level >= logger.Info {
+		logging.FromContext(ctx).InfoContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, args ...any) {
+	if l.level >= logger.Warn {
+		logging.FromContext(ctx).WarnContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, args ...any) {
+	if l.level >= logger.Error {
+		logging.FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time,
+	fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	log := logging.FromContext(ctx)
+	attrs := func() []slog.Attr {
+		sql, rows := fc()
+		return []slog.Attr{
+			slog.String("sql", tracing.SanitizeSQL(sql)),
+			slog.Int64("rows", rows),
+			slog.Float64("elapsed_ms", float64(elapsed.Microseconds())/1000),
+		}
+	}
+
+	switch {
+	case err != nil && l.level >= logger.Error &&
+		!errors.Is(err, gorm.ErrRecordNotFound):
+		log.LogAttrs(ctx, slog.LevelError, "query failed",
+			append(attrs(), slog.String("error", err.Error()))...)
+	case elapsed > slowQueryThreshold && l.level >= logger.Warn:
+		log.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs()...)
+	case l.level >= logger.Info:
+		log.LogAttrs(ctx, slog.LevelDebug, "query", attrs()...)
+	}
+}
@@ -0,0 +1,186 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	migratepg "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"gorm.io/gorm"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// newMigrator builds a migrate instance on a dedicated connection taken from
+// the gorm pool, so closing it does not close the pool itself. It also
+// returns the newest migration version embedded in the binary.
+func newMigrator(db *gorm.DB) (*migrate.Migrate, uint, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, 0, err
+	}
+	conn, err := sqlDB.Conn(context.Background())
+	if err != nil {
+		return nil, 0, err
+	}
+	driver, err := migratepg.WithConnection(
+		context.Background(), conn, &migratepg.Config{})
+	if err != nil {
+		_ = conn.Close()
+		return nil, 0, err
+	}
+
+	src, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		_ = driver.Close()
+		return nil, 0, err
+	}
+	latest, err := latestVersion(src)
+	if err != nil {
+		_ = driver.Close()
+		return nil, 0, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		_ = driver.Close()
+		return nil, 0, err
+	}
+	return m, latest, nil
+}
+
+func latestVersion(src source.Driver) (uint, error) {
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("no embedded migrations: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+// Migrate applies all embedded migrations that have not been applied yet.
+// The postgres driver holds an advisory lock while migrating, so replicas
+// starting at the same time apply each migration only once. It refuses to
+// continue if the schema is dirty or newer than the migrations in this binary.
+func Migrate(db *gorm.DB) error {
+	m, latest, err := newMigrator(db)
+	if err != nil {
+		return fmt.Errorf("init migrations: %w", err)
+	}
+	defer m.Close()
+
+	version, _, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if version > latest {
+		return fmt.Errorf("database schema version %d is ahead of "+
+			"this binary (latest migration %d)", version, latest)
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		var dirty migrate.ErrDirty
+		if errors.As(err, &dirty) {
+			return fmt.Errorf("database schema is dirty at version %d: "+
+				"repair it and force the version before starting",
+				dirty.Version)
+		}
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+	return nil
+}
+
+// LatestMigrationVersion returns the newest migration version embedded in
+// the binary.
+func LatestMigrationVersion() (uint, error) {
+	src, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+	return latestVersion(src)
+}
+
+// SchemaVersion reads the applied version straight from the migrations
+// table. Unlike GetMigrationStatus it does not take a dedicated connection,
+// which makes it cheap enough for health checks.
+func SchemaVersion(ctx context.Context, db *gorm.DB) (uint, bool, error) {
+	var row struct {
+		Version int64
+		Dirty   bool
+	}
+	err := db.WithContext(ctx).
+		Raw("SELECT version, dirty FROM " + migratepg.DefaultMigrationsTable +
+			" LIMIT 1").Scan(&row).Error
+	if err != nil {
+		return 0, false, err
+	}
+	return uint(row.Version), row.Dirty, nil
+}
+
+type MigrationStatus struct {
+	Version uint `json:"version"`
+	Dirty   bool `json:"dirty"`
+	Latest  uint `json:"latest"`
+}
+
+// GetMigrationStatus reports the applied schema version next to the newest
+// version embedded in the binary.
+func GetMigrationStatus(db *gorm.DB) (*MigrationStatus, error) {
+	m, latest, err := newMigrator(db)
+	if err != nil {
+		return nil, fmt.Errorf("init migrations: %w", err)
+	}
+	defer m.Close()
+
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("read schema version: %w", err)
+	}
+	return &MigrationStatus{Version: version, Dirty: dirty, Latest: latest}, nil
+}
+
+// MigrateDown rolls back the given number of migrations.
+func MigrateDown(db *gorm.DB, steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("steps must be at least 1, got %d", steps)
+	}
+	m, _, err := newMigrator(db)
+	if err != nil {
+		return fmt.Errorf("init migrations: %w", err)
+	}
+	defer m.Close()
+
+	if err := m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("roll back migrations: %w", err)
+	}
+	return nil
+}
+
+// ForceMigrationVersion marks the schema as clean at the given version
+// without running any migration, to recover from a failed one.
+func ForceMigrationVersion(db *gorm.DB, version int) error {
+	m, _, err := newMigrator(db)
+	if err != nil {
+		return fmt.Errorf("init migrations: %w", err)
+	}
+	defer m.Close()
+
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("force version %d: %w", version, err)
+	}
+	return nil
+}
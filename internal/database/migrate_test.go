@@ -0,0 +1,28 @@
+package database
+
+import (
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLatestVersion(t *testing.T) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	require.NoError(t, err)
+	var want uint64
+	for _, e := range entries {
+		v, err := strconv.ParseUint(strings.SplitN(e.Name(), "_", 2)[0], 10, 64)
+		require.NoError(t, err)
+		want = max(want, v)
+	}
+	src, err := iofs.New(migrationFiles, "migrations")
+	require.NoError(t, err)
+
+	got, err := latestVersion(src)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(want), got)
+}
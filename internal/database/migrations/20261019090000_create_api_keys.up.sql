@@ -0,0 +1,9 @@
+CREATE TABLE api_keys
+(
+    id         CHAR(36) PRIMARY KEY,
+    name       TEXT      NOT NULL,
+    prefix     TEXT      NOT NULL,
+    key_hash   TEXT      NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
@@ -0,0 +1,12 @@
+CREATE TABLE idempotency_keys
+(
+    key          TEXT PRIMARY KEY,
+    fingerprint  TEXT        NOT NULL,
+    status_code  INTEGER     NOT NULL DEFAULT 0,
+    content_type TEXT        NOT NULL DEFAULT '',
+    body         BYTEA,
+    created_at   TIMESTAMPTZ NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS wordpress_mappings;
@@ -0,0 +1,12 @@
+CREATE TABLE wordpress_mappings
+(
+    site         TEXT        NOT NULL,
+    kind         TEXT        NOT NULL,
+    wordpress_id BIGINT      NOT NULL,
+    url          TEXT        NOT NULL DEFAULT '',
+    target_id    CHAR(36)    NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (site, kind, wordpress_id)
+);
+
+CREATE INDEX idx_wordpress_mappings_url ON wordpress_mappings (url);
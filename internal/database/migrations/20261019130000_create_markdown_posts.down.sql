@@ -0,0 +1 @@
+DROP TABLE IF EXISTS markdown_posts;
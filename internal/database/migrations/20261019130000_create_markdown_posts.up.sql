@@ -0,0 +1,11 @@
+CREATE TABLE markdown_posts
+(
+    slug       TEXT PRIMARY KEY,
+    post_id    CHAR(36)    NOT NULL UNIQUE,
+    tags       JSONB       NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_markdown_posts_post
+        FOREIGN KEY (post_id)
+            REFERENCES posts (id)
+            ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+ALTER TABLE api_keys
+    ADD COLUMN user_id CHAR(36),
+    ADD CONSTRAINT fk_api_keys_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_posts_user_created;
+DROP TABLE IF EXISTS follows;
@@ -0,0 +1,21 @@
+CREATE TABLE follows
+(
+    follower_id CHAR(36)    NOT NULL,
+    followee_id CHAR(36)    NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (follower_id, followee_id),
+    CONSTRAINT fk_follows_follower
+        FOREIGN KEY (follower_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE,
+    CONSTRAINT fk_follows_followee
+        FOREIGN KEY (followee_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE,
+    CONSTRAINT chk_follows_self CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX idx_follows_followee ON follows (followee_id, created_at);
+
+-- The feed reads the newest posts of each followee.
+CREATE INDEX idx_posts_user_created ON posts (user_id, created_at DESC, id DESC);
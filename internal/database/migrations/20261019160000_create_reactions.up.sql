@@ -0,0 +1,56 @@
+CREATE TABLE reactions
+(
+    post_id    CHAR(36)    NOT NULL,
+    user_id    CHAR(36)    NOT NULL,
+    kind       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id, user_id, kind),
+    CONSTRAINT fk_reactions_post
+        FOREIGN KEY (post_id)
+            REFERENCES posts (id)
+            ON DELETE CASCADE,
+    CONSTRAINT fk_reactions_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reactions_post_kind ON reactions (post_id, kind, created_at);
+
+CREATE TABLE post_reaction_counts
+(
+    post_id CHAR(36) NOT NULL,
+    kind    TEXT     NOT NULL,
+    count   BIGINT   NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, kind),
+    CONSTRAINT fk_post_reaction_counts_post
+        FOREIGN KEY (post_id)
+            REFERENCES posts (id)
+            ON DELETE CASCADE
+);
+
+-- The counts follow every insert and delete on reactions, including the
+-- ones cascading from a deleted user, so they never need a recount.
+CREATE FUNCTION count_post_reactions() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO post_reaction_counts (post_id, kind, count)
+        VALUES (NEW.post_id, NEW.kind, 1)
+        ON CONFLICT (post_id, kind)
+            DO UPDATE SET count = post_reaction_counts.count + 1;
+    ELSE
+        UPDATE post_reaction_counts
+        SET count = count - 1
+        WHERE post_id = OLD.post_id
+          AND kind = OLD.kind;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_reactions_count
+    AFTER INSERT OR DELETE
+    ON reactions
+    FOR EACH ROW
+EXECUTE FUNCTION count_post_reactions();
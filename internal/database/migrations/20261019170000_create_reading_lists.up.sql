@@ -0,0 +1,38 @@
+CREATE TABLE reading_lists
+(
+    id          CHAR(36)    NOT NULL PRIMARY KEY,
+    user_id     CHAR(36)    NOT NULL,
+    name        TEXT        NOT NULL,
+    is_default  BOOLEAN     NOT NULL DEFAULT FALSE,
+    share_token TEXT,
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_reading_lists_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE,
+    CONSTRAINT uq_reading_lists_share_token UNIQUE (share_token)
+);
+
+-- Every user has at most one default list, created on first use.
+CREATE UNIQUE INDEX idx_reading_lists_default ON reading_lists (user_id)
+    WHERE is_default;
+
+CREATE TABLE reading_list_items
+(
+    list_id    CHAR(36)    NOT NULL,
+    post_id    CHAR(36)    NOT NULL,
+    position   INTEGER     NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (list_id, post_id),
+    CONSTRAINT fk_reading_list_items_list
+        FOREIGN KEY (list_id)
+            REFERENCES reading_lists (id)
+            ON DELETE CASCADE,
+    CONSTRAINT fk_reading_list_items_post
+        FOREIGN KEY (post_id)
+            REFERENCES posts (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reading_list_items_position ON reading_list_items (list_id, position);
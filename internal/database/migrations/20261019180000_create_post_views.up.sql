@@ -0,0 +1,15 @@
+-- Views are counted in memory and added here in batches, one row per post
+-- and day.
+CREATE TABLE post_views
+(
+    post_id CHAR(36) NOT NULL,
+    day     DATE     NOT NULL,
+    views   BIGINT   NOT NULL,
+    PRIMARY KEY (post_id, day),
+    CONSTRAINT fk_post_views_post
+        FOREIGN KEY (post_id)
+            REFERENCES posts (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_views_day ON post_views (day);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reactions_created;
+DROP TABLE IF EXISTS trending_posts;
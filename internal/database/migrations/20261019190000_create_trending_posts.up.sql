@@ -0,0 +1,17 @@
+-- The trending ranking as of the last refresh. Requests read it instead
+-- of scoring every post.
+CREATE TABLE trending_posts
+(
+    post_id     CHAR(36)         NOT NULL PRIMARY KEY,
+    score       DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMPTZ      NOT NULL,
+    CONSTRAINT fk_trending_posts_post
+        FOREIGN KEY (post_id)
+            REFERENCES posts (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_trending_posts_score ON trending_posts (score DESC, post_id);
+
+-- The refresh scores the reactions of the window.
+CREATE INDEX idx_reactions_created ON reactions (created_at);
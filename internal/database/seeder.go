@@ -1,11 +1,10 @@
 package database
 
 import (
-	"fmt"
 	"github.com/pandahawk/blog-api/internal/shared/model"
 	"github.com/pandahawk/blog-api/internal/shared/testdata"
 	"gorm.io/gorm"
-	"log"
+	"log/slog"
 )
 
 func SeedDevData(db *gorm.DB) {
@@ -13,10 +12,10 @@ func SeedDevData(db *gorm.DB) {
 	var count int64
 	db.Model(&model.User{}).Count(&count)
 	if count > 0 {
-		fmt.Println("Skipping seed: users already exist")
+		slog.Info("skipping seed: users already exist")
 		return
 	}
-	log.Println("Seeding DevData...")
+	slog.Info("seeding dev data")
 
 	db.Create(&testdata.SampleUsers)
 	db.Create(&testdata.SamplePosts)
@@ -4,16 +4,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/docker/go-connections/nat"
-	"github.com/golang-migrate/migrate/v4"
-	migratepg "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"log"
-	"os"
 	"testing"
 	"time"
 )
@@ -63,26 +58,7 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	require.NoError(t, err)
 
-	applyMigrations(db)
+	require.NoError(t, Migrate(db))
 	SeedDevData(db)
-	require.NoError(t, err)
 	return db
 }
-
-func applyMigrations(db *gorm.DB) {
-	wd, err := os.Getwd()
-	if err != nil {
-		panic(err)
-	}
-	log.Println(wd)
-	sqlDB, _ := db.DB()
-	driver, _ := migratepg.WithInstance(sqlDB, &migratepg.Config{})
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://../database/migrations",
-		"postgres", driver,
-	)
-	if err != nil {
-		panic(err)
-	}
-	_ = m.Up()
-}
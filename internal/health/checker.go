@@ -0,0 +1,167 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/database"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+type CheckResult struct {
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+	Latency string `json:"latency,omitempty"`
+}
+
+type MigrationResult struct {
+	CheckResult
+	Version uint `json:"version"`
+	Latest  uint `json:"latest"`
+	Dirty   bool `json:"dirty"`
+}
+
+type WorkersResult struct {
+	CheckResult
+	Workers map[string]WorkerState `json:"workers"`
+}
+
+type PoolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+}
+
+type Report struct {
+	Status     string           `json:"status"`
+	Serving    CheckResult      `json:"serving"`
+	Database   CheckResult      `json:"database"`
+	Migrations *MigrationResult `json:"migrations,omitempty"`
+	Workers    WorkersResult    `json:"workers"`
+	Pool       *PoolStats       `json:"pool,omitempty"`
+}
+
+type Checker struct {
+	db      *gorm.DB
+	monitor *Monitor
+	timeout time.Duration
+}
+
+func NewChecker(db *gorm.DB, monitor *Monitor, timeout time.Duration) *Checker {
+	return &Checker{db: db, monitor: monitor, timeout: timeout}
+}
+
+func ok() CheckResult {
+	return CheckResult{Status: StatusOK}
+}
+
+func failed(format string, args ...any) CheckResult {
+	return CheckResult{Status: StatusUnavailable, Error: fmt.Sprintf(format, args...)}
+}
+
+// Check reports on every dependency the API needs to serve requests. The
+// report status is unavailable if any of them is.
+func (c *Checker) Check(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := &Report{
+		Serving:  ok(),
+		Database: ok(),
+		Workers:  WorkersResult{CheckResult: ok(), Workers: map[string]WorkerState{}},
+	}
+
+	if c.monitor != nil {
+		if !c.monitor.Ready() {
+			report.Serving = failed("server is not accepting traffic")
+		}
+		report.Workers.Workers = c.monitor.Workers()
+		for name, w := range report.Workers.Workers {
+			if !w.Running {
+				report.Workers.CheckResult = failed("worker %s is not running", name)
+			}
+		}
+	}
+
+	if c.db == nil {
+		report.Database = failed("database is not configured")
+	} else {
+		report.Database, report.Pool = c.checkDatabase(ctx)
+		if report.Database.Status == StatusOK {
+			report.Migrations = c.checkMigrations(ctx)
+		}
+	}
+
+	report.Status = StatusOK
+	for _, status := range []string{report.Serving.Status,
+		report.Database.Status, report.Workers.Status} {
+		if status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+	if report.Migrations != nil && report.Migrations.Status != StatusOK {
+		report.Status = StatusUnavailable
+	}
+	return report
+}
+
+func (c *Checker) checkDatabase(ctx context.Context) (CheckResult, *PoolStats) {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return failed("%v", err), nil
+	}
+
+	stats := sqlDB.Stats()
+	pool := &PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+	}
+
+	start := time.Now()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return failed("ping failed: %v", err), pool
+	}
+	result := ok()
+	result.Latency = time.Since(start).String()
+	return result, pool
+}
+
+func (c *Checker) checkMigrations(ctx context.Context) *MigrationResult {
+	result := &MigrationResult{CheckResult: ok()}
+
+	latest, err := database.LatestMigrationVersion()
+	if err != nil {
+		result.CheckResult = failed("read embedded migrations: %v", err)
+		return result
+	}
+	result.Latest = latest
+
+	version, dirty, err := database.SchemaVersion(ctx, c.db)
+	if err != nil {
+		result.CheckResult = failed("read schema version: %v", err)
+		return result
+	}
+	result.Version = version
+	result.Dirty = dirty
+
+	switch {
+	case dirty:
+		result.CheckResult = failed("schema is dirty at version %d", version)
+	case version != latest:
+		result.CheckResult = failed("schema version %d does not match %d",
+			version, latest)
+	}
+	return result
+}
@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestChecker_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(m *Monitor)
+		wantServing string
+		wantWorkers string
+	}{
+		{
+			name:        "not ready before the server listens",
+			setup:       func(m *Monitor) {},
+			wantServing: StatusUnavailable,
+			wantWorkers: StatusOK,
+		},
+		{
+			name: "ready with running workers",
+			setup: func(m *Monitor) {
+				m.SetReady(true)
+				m.WorkerStarted("flush")
+			},
+			wantServing: StatusOK,
+			wantWorkers: StatusOK,
+		},
+		{
+			name: "stopped worker",
+			setup: func(m *Monitor) {
+				m.SetReady(true)
+				m.WorkerStarted("flush")
+				m.WorkerStopped("flush")
+			},
+			wantServing: StatusOK,
+			wantWorkers: StatusUnavailable,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			monitor := NewMonitor()
+			test.setup(monitor)
+			checker := NewChecker(nil, monitor, time.Second)
+
+			report := checker.Check(context.Background())
+
+			assert.Equal(t, test.wantServing, report.Serving.Status)
+			assert.Equal(t, test.wantWorkers, report.Workers.Status)
+			assert.Equal(t, StatusUnavailable, report.Database.Status)
+			assert.Equal(t, StatusUnavailable, report.Status)
+		})
+	}
+}
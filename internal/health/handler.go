@@ -0,0 +1,45 @@
+package health
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type Handler struct {
+	Checker *Checker
+}
+
+func NewHandler(checker *Checker) *Handler {
+	return &Handler{Checker: checker}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRoutes) {
+	r.GET("/livez", h.livez)
+	r.GET("/readyz", h.readyz)
+}
+
+// @Summary Liveness probe
+// @Description Reports that the process is running, without checking dependencies
+// @Tags health
+// @Produce json
+// @Success 200
+// @Router /livez [get]
+func (h *Handler) livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": StatusOK})
+}
+
+// @Summary Readiness probe
+// @Description Checks the database, schema version and background workers
+// @Tags health
+// @Produce json
+// @Success 200 {object} Report
+// @Failure 503 {object} Report
+// @Router /readyz [get]
+func (h *Handler) readyz(c *gin.Context) {
+	report := h.Checker.Check(c.Request.Context())
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
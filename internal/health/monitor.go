@@ -0,0 +1,62 @@
+package health
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type WorkerState struct {
+	Running   bool       `json:"running"`
+	StartedAt time.Time  `json:"started_at"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+}
+
+// Monitor records the process state the readiness probe reports on: whether
+// the server is accepting traffic and which background workers are running.
+// It starts out not ready; the server marks it ready once it is listening
+// and not ready again as soon as shutdown begins.
+type Monitor struct {
+	ready   atomic.Bool
+	mu      sync.Mutex
+	workers map[string]*WorkerState
+}
+
+func NewMonitor() *Monitor {
+	return &Monitor{workers: map[string]*WorkerState{}}
+}
+
+func (m *Monitor) SetReady(ready bool) {
+	m.ready.Store(ready)
+}
+
+func (m *Monitor) Ready() bool {
+	return m.ready.Load()
+}
+
+func (m *Monitor) WorkerStarted(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.workers[name] = &WorkerState{Running: true, StartedAt: time.Now()}
+}
+
+func (m *Monitor) WorkerStopped(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if w, ok := m.workers[name]; ok {
+		now := time.Now()
+		w.Running = false
+		w.StoppedAt = &now
+	}
+}
+
+// Workers returns a copy of the worker states keyed by worker name.
+func (m *Monitor) Workers() map[string]WorkerState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	workers := make(map[string]WorkerState, len(m.workers))
+	for name, w := range m.workers {
+		workers[name] = *w
+	}
+	return workers
+}
@@ -0,0 +1,73 @@
+package idempotency
+
+import (
+	"context"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+//go:generate mockgen -source=repository.go -destination=repository_mock.go -package=idempotency
+
+type Repository interface {
+	// Insert stores key unless a key with the same name exists and reports
+	// whether it was stored.
+	Insert(ctx context.Context, key *model.IdempotencyKey) (bool, error)
+	FindByKey(ctx context.Context, key string) (*model.IdempotencyKey, error)
+	Complete(ctx context.Context, key string, status int, contentType string,
+		body []byte) error
+	Delete(ctx context.Context, key string) error
+	// DeleteStale removes key if it is still in progress and was created
+	// before the given time.
+	DeleteStale(ctx context.Context, key string, createdBefore time.Time) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) Insert(ctx context.Context, key *model.IdempotencyKey) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *repository) FindByKey(ctx context.Context, key string) (*model.IdempotencyKey, error) {
+	var found model.IdempotencyKey
+	err := r.db.WithContext(ctx).First(&found, "key = ?", key).Error
+	return &found, err
+}
+
+func (r *repository) Complete(ctx context.Context, key string, status int,
+	contentType string, body []byte) error {
+	return r.db.WithContext(ctx).Model(&model.IdempotencyKey{}).
+		Where("key = ?", key).
+		Updates(map[string]any{
+			"status_code":  status,
+			"content_type": contentType,
+			"body":         body,
+		}).Error
+}
+
+func (r *repository) Delete(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).
+		Delete(&model.IdempotencyKey{}, "key = ?", key).Error
+}
+
+func (r *repository) DeleteStale(ctx context.Context, key string, createdBefore time.Time) error {
+	return r.db.WithContext(ctx).
+		Where("key = ? AND status_code = 0 AND created_at < ?", key, createdBefore).
+		Delete(&model.IdempotencyKey{}).Error
+}
+
+func (r *repository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", now).Delete(&model.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
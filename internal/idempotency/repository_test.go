@@ -0,0 +1,55 @@
+package idempotency
+
+import (
+	"context"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRepository_Lifecycle(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	expires := time.Now().Add(time.Hour)
+
+	claimed, err := repo.Insert(ctx, model.NewIdempotencyKey("k", "fp", expires))
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = repo.Insert(ctx, model.NewIdempotencyKey("k", "other", expires))
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	require.NoError(t, repo.Complete(ctx, "k", http.StatusCreated,
+		"application/json", []byte(`{"id":1}`)))
+	got, err := repo.FindByKey(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, "fp", got.Fingerprint)
+	assert.True(t, got.Completed())
+	assert.Equal(t, []byte(`{"id":1}`), got.Body)
+
+	deleted, err := repo.DeleteExpired(ctx, expires.Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
+
+func TestRepository_DeleteStale(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	_, err := repo.Insert(ctx, model.NewIdempotencyKey("k", "fp", time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteStale(ctx, "k", time.Now().Add(-time.Minute)))
+	_, err = repo.FindByKey(ctx, "k")
+	assert.NoError(t, err, "a fresh key is not stale")
+
+	require.NoError(t, repo.DeleteStale(ctx, "k", time.Now().Add(time.Minute)))
+	_, err = repo.FindByKey(ctx, "k")
+	assert.Error(t, err)
+}
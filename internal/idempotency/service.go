@@ -0,0 +1,140 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock.go -package=idempotency
+
+// pollInterval is how often a retry checks whether the first request with
+// the same key has finished.
+const pollInterval = 100 * time.Millisecond
+
+var (
+	ErrInProgress = errors.New(
+		"a request with this Idempotency-Key is still being processed")
+	ErrMismatch = errors.New(
+		"this Idempotency-Key was already used for a different request")
+)
+
+type Service interface {
+	// Begin claims key for a request with the given fingerprint. It returns
+	// nil when the caller should process the request and complete or
+	// release the key afterwards, or the stored key when a finished
+	// response should be replayed. If the first request is still running
+	// it waits for it up to the configured timeout before returning
+	// ErrInProgress.
+	Begin(ctx context.Context, key string, fingerprint string) (*model.IdempotencyKey, error)
+	// Complete stores the response for replay.
+	Complete(ctx context.Context, key string, status int, contentType string,
+		body []byte) error
+	// Release forgets key so the request can be retried.
+	Release(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type service struct {
+	repo Repository
+	cfg  config.IdempotencyConfig
+	now  func() time.Time
+}
+
+func (s *service) Begin(ctx context.Context, key string, fingerprint string) (*model.IdempotencyKey, error) {
+	deadline := s.now().Add(s.cfg.WaitTimeout)
+	for {
+		now := s.now()
+		claimed, err := s.repo.Insert(ctx,
+			model.NewIdempotencyKey(key, fingerprint, now.Add(s.cfg.TTL)))
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		existing, err := s.repo.FindByKey(ctx, key)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Released or expired in the meantime, claim it again.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if existing.ExpiresAt.Before(now) {
+			if _, err := s.repo.DeleteExpired(ctx, now); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if existing.Fingerprint != fingerprint {
+			return nil, ErrMismatch
+		}
+		if existing.Completed() {
+			return existing, nil
+		}
+		if existing.CreatedAt.Before(now.Add(-s.cfg.LockTimeout)) {
+			if err := s.repo.DeleteStale(ctx, key,
+				now.Add(-s.cfg.LockTimeout)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !now.Before(deadline) {
+			return nil, ErrInProgress
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (s *service) Complete(ctx context.Context, key string, status int,
+	contentType string, body []byte) error {
+	return s.repo.Complete(ctx, key, status, contentType, body)
+}
+
+func (s *service) Release(ctx context.Context, key string) error {
+	return s.repo.Delete(ctx, key)
+}
+
+func (s *service) DeleteExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, s.now())
+}
+
+func NewService(repo Repository, cfg config.IdempotencyConfig) Service {
+	return &service{repo: repo, cfg: cfg, now: time.Now}
+}
+
+// CleanupWorker deletes expired keys every interval until ctx is cancelled.
+func CleanupWorker(s Service, interval time.Duration) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				n, err := s.DeleteExpired(ctx)
+				if err != nil {
+					logging.FromContext(ctx).Error(
+						"delete expired idempotency keys", "error", err)
+					continue
+				}
+				if n > 0 {
+					logging.FromContext(ctx).Info(
+						"deleted expired idempotency keys", "count", n)
+				}
+			}
+		}
+	}
+}
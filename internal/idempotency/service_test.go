@@ -0,0 +1,161 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+func setup(t *testing.T, cfg config.IdempotencyConfig) (*MockRepository, Service) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockRepo := NewMockRepository(ctrl)
+	return mockRepo, &service{repo: mockRepo, cfg: cfg,
+		now: func() time.Time { return now }}
+}
+
+func TestService_Begin(t *testing.T) {
+	cfg := config.IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Minute}
+	completed := &model.IdempotencyKey{Key: "k", Fingerprint: "fp",
+		StatusCode: http.StatusCreated, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	running := &model.IdempotencyKey{Key: "k", Fingerprint: "fp",
+		CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	tests := []struct {
+		name       string
+		expectMock func(repo *MockRepository)
+		want       *model.IdempotencyKey
+		wantErr    error
+	}{
+		{
+			name: "claims a new key",
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().Insert(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, key *model.IdempotencyKey) (bool, error) {
+						assert.Equal(t, "fp", key.Fingerprint)
+						assert.Equal(t, now.Add(time.Hour), key.ExpiresAt)
+						return true, nil
+					})
+			},
+		},
+		{
+			name: "replays a completed request",
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(false, nil)
+				repo.EXPECT().FindByKey(gomock.Any(), "k").Return(completed, nil)
+			},
+			want: completed,
+		},
+		{
+			name: "rejects a different request",
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(false, nil)
+				repo.EXPECT().FindByKey(gomock.Any(), "k").
+					Return(&model.IdempotencyKey{Fingerprint: "other",
+						ExpiresAt: now.Add(time.Hour)}, nil)
+			},
+			wantErr: ErrMismatch,
+		},
+		{
+			name: "rejects while the first request is running",
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(false, nil)
+				repo.EXPECT().FindByKey(gomock.Any(), "k").Return(running, nil)
+			},
+			wantErr: ErrInProgress,
+		},
+		{
+			name: "reclaims an expired key",
+			expectMock: func(repo *MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(false, nil),
+					repo.EXPECT().FindByKey(gomock.Any(), "k").
+						Return(&model.IdempotencyKey{Fingerprint: "other",
+							ExpiresAt: now.Add(-time.Second)}, nil),
+					repo.EXPECT().DeleteExpired(gomock.Any(), now).Return(int64(1), nil),
+					repo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(true, nil),
+				)
+			},
+		},
+		{
+			name: "reclaims an abandoned key",
+			expectMock: func(repo *MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(false, nil),
+					repo.EXPECT().FindByKey(gomock.Any(), "k").
+						Return(&model.IdempotencyKey{Fingerprint: "fp",
+							CreatedAt: now.Add(-time.Hour),
+							ExpiresAt: now.Add(time.Hour)}, nil),
+					repo.EXPECT().DeleteStale(gomock.Any(), "k", now.Add(-time.Minute)).
+						Return(nil),
+					repo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(true, nil),
+				)
+			},
+		},
+		{
+			name: "claims a key released in the meantime",
+			expectMock: func(repo *MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(false, nil),
+					repo.EXPECT().FindByKey(gomock.Any(), "k").
+						Return(nil, gorm.ErrRecordNotFound),
+					repo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(true, nil),
+				)
+			},
+		},
+		{
+			name: "db error",
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().Insert(gomock.Any(), gomock.Any()).
+					Return(false, errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setup(t, cfg)
+			test.expectMock(mockRepo)
+
+			got, err := service.Begin(context.Background(), "k", "fp")
+
+			if test.wantErr != nil {
+				assert.EqualError(t, err, test.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestService_BeginWaitsForRunningRequest(t *testing.T) {
+	cfg := config.IdempotencyConfig{TTL: time.Hour, WaitTimeout: time.Second,
+		LockTimeout: time.Minute}
+	mockRepo, svc := setup(t, cfg)
+	svc.(*service).now = time.Now
+	running := &model.IdempotencyKey{Fingerprint: "fp",
+		CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+	completed := *running
+	completed.StatusCode = http.StatusCreated
+	gomock.InOrder(
+		mockRepo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(false, nil),
+		mockRepo.EXPECT().FindByKey(gomock.Any(), "k").Return(running, nil),
+		mockRepo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(false, nil),
+		mockRepo.EXPECT().FindByKey(gomock.Any(), "k").Return(&completed, nil),
+	)
+
+	got, err := svc.Begin(context.Background(), "k", "fp")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &completed, got)
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"context"
+	"github.com/pandahawk/blog-api/internal/config"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+)
+
+type contextKey struct{}
+
+// New returns a logger writing to w in the configured format. The level is
+// expected to have been validated by config.Load and falls back to info.
+func New(w io.Writer, cfg config.LogConfig) *slog.Logger {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(cfg.Level))
+	opts := &slog.HandlerOptions{Level: level}
+	if cfg.Format == config.LogText {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+// Setup makes a logger for cfg writing to stderr the process default. The
+// standard log package is routed through it as well.
+func Setup(cfg config.LogConfig) *slog.Logger {
+	logger := New(os.Stderr, cfg)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger stored in ctx, or the
+// default logger outside of a request.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are never written to the log, in canonical form.
+var sensitiveHeaders = map[string]bool{
+	"X-Api-Key":           true,
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// Headers returns h as a log attribute with the values of credential
+// carrying headers replaced.
+func Headers(key string, h http.Header) slog.Attr {
+	attrs := make([]any, 0, len(h))
+	for name, values := range h {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			attrs = append(attrs, slog.String(name, redacted))
+			continue
+		}
+		if len(values) == 1 {
+			attrs = append(attrs, slog.String(name, values[0]))
+			continue
+		}
+		attrs = append(attrs, slog.Any(name, values))
+	}
+	return slog.Group(key, attrs...)
+}
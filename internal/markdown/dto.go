@@ -0,0 +1,17 @@
+package markdown
+
+type ImportReport struct {
+	Created   int          `json:"created"`
+	Updated   int          `json:"updated"`
+	Unchanged int          `json:"unchanged"`
+	Errors    []*FileError `json:"errors"`
+}
+
+type FileError struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+type ExportReport struct {
+	Files int `json:"files"`
+}
@@ -0,0 +1,102 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+	"strings"
+	"time"
+)
+
+const delimiter = "---"
+
+// dateLayouts are the date formats Hugo and Jekyll write into front matter.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// FrontMatter is the YAML header of a post file. ID is written on export so
+// a file keeps its post when it is imported into another database.
+type FrontMatter struct {
+	ID      *uuid.UUID `yaml:"id,omitempty"`
+	Title   string     `yaml:"title"`
+	Author  string     `yaml:"author"`
+	Date    string     `yaml:"date"`
+	LastMod string     `yaml:"lastmod,omitempty"`
+	Slug    string     `yaml:"slug,omitempty"`
+	Tags    []string   `yaml:"tags,omitempty"`
+}
+
+// Document is a parsed post file.
+type Document struct {
+	FrontMatter
+	Body string
+}
+
+// Parse splits data into its front matter, enclosed in "---" lines, and
+// the Markdown body after it.
+func Parse(data []byte) (*Document, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	rest, ok := strings.CutPrefix(text, delimiter+"\n")
+	if !ok {
+		return nil, fmt.Errorf("missing front matter")
+	}
+	header, body, ok := strings.Cut(rest, "\n"+delimiter+"\n")
+	if !ok {
+		header, ok = strings.CutSuffix(rest, "\n"+delimiter)
+		if !ok {
+			return nil, fmt.Errorf("front matter is not closed")
+		}
+	}
+
+	var doc Document
+	if err := yaml.Unmarshal([]byte(header), &doc.FrontMatter); err != nil {
+		return nil, fmt.Errorf("invalid front matter: %w", err)
+	}
+	doc.Body = strings.TrimPrefix(body, "\n")
+	return &doc, nil
+}
+
+// Render writes doc back in the form Parse reads.
+func Render(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(delimiter + "\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc.FrontMatter); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString(delimiter + "\n\n")
+	buf.WriteString(doc.Body)
+	if !strings.HasSuffix(doc.Body, "\n") {
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// parseDate reads a front matter date. Dates without a zone are UTC.
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// formatDate writes t with all its digits, so an exported file imports
+// back to the same timestamp.
+func formatDate(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,111 @@
+package markdown
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *Document
+		wantErr string
+	}{
+		{
+			name: "hugo",
+			data: "---\ntitle: Hello\nauthor: alice\ndate: 2024-03-01\n" +
+				"tags: [go, api]\nslug: hello\n---\n\n# Hello\n",
+			want: &Document{
+				FrontMatter: FrontMatter{Title: "Hello", Author: "alice",
+					Date: "2024-03-01", Slug: "hello",
+					Tags: []string{"go", "api"}},
+				Body: "# Hello\n",
+			},
+		},
+		{
+			name: "crlf and empty body",
+			data: "---\r\ntitle: Hello\r\n---",
+			want: &Document{FrontMatter: FrontMatter{Title: "Hello"}},
+		},
+		{
+			name:    "no front matter",
+			data:    "# Hello\n",
+			wantErr: "missing front matter",
+		},
+		{
+			name:    "unclosed front matter",
+			data:    "---\ntitle: Hello\n# Hello\n",
+			wantErr: "front matter is not closed",
+		},
+		{
+			name:    "invalid yaml",
+			data:    "---\ntitle: [Hello\n---\n",
+			wantErr: "invalid front matter",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Parse([]byte(test.data))
+
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestRender_RoundTrip(t *testing.T) {
+	id := uuid.New()
+	doc := &Document{
+		FrontMatter: FrontMatter{ID: &id, Title: "Colons: everywhere",
+			Author: "alice", Date: "2024-03-01T10:00:00Z", Slug: "colons",
+			Tags: []string{"go"}},
+		Body: "---\nnot front matter\n",
+	}
+
+	data, err := Render(doc)
+	require.NoError(t, err)
+	got, err := Parse(data)
+
+	require.NoError(t, err)
+	assert.Equal(t, doc, got)
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{in: "2024-03-01", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{in: "2024-03-01T10:00:00.5+02:00",
+			want: time.Date(2024, 3, 1, 8, 0, 0, 5e8, time.UTC)},
+		{in: "2024-03-01 10:00:00 +0200",
+			want: time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)},
+		{in: "2024-03-01 10:00",
+			want: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got, err := parseDate(test.in)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	_, err := parseDate("yesterday")
+	assert.ErrorContains(t, err, `invalid date "yesterday"`)
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "hello-world-2024", slugify("  Hello, World! 2024 "))
+	assert.Equal(t, "caf-au-lait", slugify("Café au lait"))
+	assert.Equal(t, "", slugify("¿?"))
+}
@@ -0,0 +1,118 @@
+package markdown
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockgen -source=repository.go -destination=repository_mock.go -package=markdown
+
+type Repository interface {
+	FindMapping(ctx context.Context, slug string) (*model.MarkdownPost, error)
+	// FindMappings returns the mappings of the given posts by post ID.
+	FindMappings(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID]*model.MarkdownPost, error)
+	SaveMapping(ctx context.Context, mapping *model.MarkdownPost) error
+	FindPostByID(ctx context.Context, id uuid.UUID) (*model.Post, error)
+	// FindPostsAfter returns up to limit posts with an ID after cursor,
+	// ordered by ID and with their author loaded.
+	FindPostsAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.Post, error)
+	FindUserByUsername(ctx context.Context, username string) (*model.User, error)
+	CreatePost(ctx context.Context, post *model.Post) error
+	UpdatePost(ctx context.Context, post *model.Post) error
+	// Transaction runs fn with a repository whose queries share one
+	// transaction, committed if fn returns nil and rolled back otherwise.
+	Transaction(ctx context.Context, fn func(repo Repository) error) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) FindMapping(ctx context.Context, slug string) (*model.MarkdownPost, error) {
+	var mapping model.MarkdownPost
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&mapping).Error
+	return &mapping, err
+}
+
+func (r *repository) FindMappings(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID]*model.MarkdownPost, error) {
+	var mappings []*model.MarkdownPost
+	err := r.db.WithContext(ctx).Where("post_id IN ?", postIDs).
+		Find(&mappings).Error
+	byPost := make(map[uuid.UUID]*model.MarkdownPost, len(mappings))
+	for _, m := range mappings {
+		byPost[m.PostID] = m
+	}
+	return byPost, err
+}
+
+// SaveMapping stores the mapping under its slug. A post that was synced
+// under another slug before is moved to the new one.
+func (r *repository) SaveMapping(ctx context.Context, mapping *model.MarkdownPost) error {
+	db := r.db.WithContext(ctx)
+	err := db.Where("post_id = ? AND slug <> ?", mapping.PostID, mapping.Slug).
+		Delete(&model.MarkdownPost{}).Error
+	if err != nil {
+		return err
+	}
+	err = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns(
+			[]string{"post_id", "tags", "updated_at"}),
+	}).Create(mapping).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) FindPostByID(ctx context.Context, id uuid.UUID) (*model.Post, error) {
+	var post model.Post
+	err := r.db.WithContext(ctx).First(&post, "id = ?", id).Error
+	return &post, err
+}
+
+func (r *repository) FindPostsAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.Post, error) {
+	var posts []*model.Post
+	err := r.db.WithContext(ctx).Preload("User").Where("id > ?", cursor).
+		Order("id").Limit(limit).Find(&posts).Error
+	return posts, err
+}
+
+func (r *repository) FindUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Where("username = ?", username).
+		First(&user).Error
+	return &user, err
+}
+
+// CreatePost inserts the post with the ID and timestamps it already
+// carries, GORM only fills them in when they are zero.
+func (r *repository) CreatePost(ctx context.Context, post *model.Post) error {
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Create(post).Error
+	return database.TranslateError(err)
+}
+
+// UpdatePost saves the post with the UpdatedAt it carries instead of the
+// current time, so the file's lastmod survives a round trip.
+func (r *repository) UpdatePost(ctx context.Context, post *model.Post) error {
+	err := r.db.WithContext(ctx).Model(post).Omit(clause.Associations).
+		UpdateColumns(map[string]any{
+			"title":      post.Title,
+			"content":    post.Content,
+			"user_id":    post.UserID,
+			"created_at": post.CreatedAt,
+			"updated_at": post.UpdatedAt,
+		}).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) Transaction(ctx context.Context, fn func(repo Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repository{db: tx})
+	})
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
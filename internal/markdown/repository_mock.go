@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package markdown is a generated GoMock package.
+package markdown
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	model "github.com/pandahawk/blog-api/internal/shared/model"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreatePost mocks base method.
+func (m *MockRepository) CreatePost(ctx context.Context, post *model.Post) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePost", ctx, post)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePost indicates an expected call of CreatePost.
+func (mr *MockRepositoryMockRecorder) CreatePost(ctx, post interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockRepository)(nil).CreatePost), ctx, post)
+}
+
+// FindMapping mocks base method.
+func (m *MockRepository) FindMapping(ctx context.Context, slug string) (*model.MarkdownPost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMapping", ctx, slug)
+	ret0, _ := ret[0].(*model.MarkdownPost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMapping indicates an expected call of FindMapping.
+func (mr *MockRepositoryMockRecorder) FindMapping(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMapping", reflect.TypeOf((*MockRepository)(nil).FindMapping), ctx, slug)
+}
+
+// FindMappings mocks base method.
+func (m *MockRepository) FindMappings(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID]*model.MarkdownPost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMappings", ctx, postIDs)
+	ret0, _ := ret[0].(map[uuid.UUID]*model.MarkdownPost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMappings indicates an expected call of FindMappings.
+func (mr *MockRepositoryMockRecorder) FindMappings(ctx, postIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMappings", reflect.TypeOf((*MockRepository)(nil).FindMappings), ctx, postIDs)
+}
+
+// FindPostByID mocks base method.
+func (m *MockRepository) FindPostByID(ctx context.Context, id uuid.UUID) (*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPostByID", ctx, id)
+	ret0, _ := ret[0].(*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPostByID indicates an expected call of FindPostByID.
+func (mr *MockRepositoryMockRecorder) FindPostByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPostByID", reflect.TypeOf((*MockRepository)(nil).FindPostByID), ctx, id)
+}
+
+// FindPostsAfter mocks base method.
+func (m *MockRepository) FindPostsAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPostsAfter", ctx, cursor, limit)
+	ret0, _ := ret[0].([]*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPostsAfter indicates an expected call of FindPostsAfter.
+func (mr *MockRepositoryMockRecorder) FindPostsAfter(ctx, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPostsAfter", reflect.TypeOf((*MockRepository)(nil).FindPostsAfter), ctx, cursor, limit)
+}
+
+// FindUserByUsername mocks base method.
+func (m *MockRepository) FindUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserByUsername", ctx, username)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserByUsername indicates an expected call of FindUserByUsername.
+func (mr *MockRepositoryMockRecorder) FindUserByUsername(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByUsername", reflect.TypeOf((*MockRepository)(nil).FindUserByUsername), ctx, username)
+}
+
+// SaveMapping mocks base method.
+func (m *MockRepository) SaveMapping(ctx context.Context, mapping *model.MarkdownPost) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMapping", ctx, mapping)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMapping indicates an expected call of SaveMapping.
+func (mr *MockRepositoryMockRecorder) SaveMapping(ctx, mapping interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMapping", reflect.TypeOf((*MockRepository)(nil).SaveMapping), ctx, mapping)
+}
+
+// Transaction mocks base method.
+func (m *MockRepository) Transaction(ctx context.Context, fn func(Repository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockRepositoryMockRecorder) Transaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockRepository)(nil).Transaction), ctx, fn)
+}
+
+// UpdatePost mocks base method.
+func (m *MockRepository) UpdatePost(ctx context.Context, post *model.Post) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePost", ctx, post)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePost indicates an expected call of UpdatePost.
+func (mr *MockRepositoryMockRecorder) UpdatePost(ctx, post interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockRepository)(nil).UpdatePost), ctx, post)
+}
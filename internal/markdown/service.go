@@ -0,0 +1,330 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/metrics"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock.go -package=markdown
+
+const (
+	batchSize = 500
+	extension = ".md"
+	// sectionFile holds the front matter of a Hugo section, not a post.
+	sectionFile = "_index.md"
+)
+
+var (
+	validSlug = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	// jekyllDate is the date prefix of Jekyll post file names.
+	jekyllDate = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}-`)
+)
+
+type Service interface {
+	// Import upserts a post for every .md file below dir. A file is matched
+	// to its post by slug, or by the id in its front matter the first time
+	// it is seen; other files create new posts.
+	Import(ctx context.Context, dir fs.FS) (*ImportReport, error)
+	// Export writes every post to dir as <slug>.md.
+	Export(ctx context.Context, dir string) (*ExportReport, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func (s *service) Import(ctx context.Context, dir fs.FS) (*ImportReport, error) {
+	report := &ImportReport{Errors: []*FileError{}}
+	slugs := map[string]string{}
+	err := fs.WalkDir(dir, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(name) != extension ||
+			path.Base(name) == sectionFile {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		data, err := fs.ReadFile(dir, name)
+		if err != nil {
+			return err
+		}
+		result, err := s.importFile(ctx, name, data, slugs)
+		if err != nil {
+			report.Errors = append(report.Errors,
+				&FileError{File: name, Error: err.Error()})
+			return nil
+		}
+		switch result {
+		case resultCreated:
+			report.Created++
+		case resultUpdated:
+			report.Updated++
+		default:
+			report.Unchanged++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+type result int
+
+const (
+	resultUnchanged result = iota
+	resultCreated
+	resultUpdated
+)
+
+// importFile upserts the post of one file. slugs maps the slugs seen so far
+// to their file, so two files cannot write the same post.
+func (s *service) importFile(ctx context.Context, name string, data []byte,
+	slugs map[string]string) (result, error) {
+	doc, err := Parse(data)
+	if err != nil {
+		return 0, err
+	}
+
+	slug := doc.Slug
+	if slug == "" {
+		slug = jekyllDate.ReplaceAllString(
+			strings.TrimSuffix(path.Base(name), extension), "")
+	}
+	if !validSlug.MatchString(slug) {
+		return 0, fmt.Errorf("invalid slug %q", slug)
+	}
+	if other, ok := slugs[slug]; ok {
+		return 0, fmt.Errorf("slug %q is already used by %s", slug, other)
+	}
+	slugs[slug] = name
+
+	title := strings.TrimSpace(doc.Title)
+	if title == "" {
+		return 0, fmt.Errorf("front matter needs a title")
+	}
+	if doc.Author == "" {
+		return 0, fmt.Errorf("front matter needs an author")
+	}
+	created, err := parseDate(doc.Date)
+	if err != nil {
+		return 0, err
+	}
+	updated := created
+	if doc.LastMod != "" {
+		if updated, err = parseDate(doc.LastMod); err != nil {
+			return 0, err
+		}
+	}
+	tags := doc.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	author, err := s.repo.FindUserByUsername(ctx, doc.Author)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("unknown author %q", doc.Author)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	res := resultUnchanged
+	err = s.repo.Transaction(ctx, func(repo Repository) error {
+		mapping, err := repo.FindMapping(ctx, slug)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		id := uuid.Nil
+		if doc.ID != nil {
+			id = *doc.ID
+		}
+		if err == nil {
+			id = mapping.PostID
+		} else {
+			mapping = nil
+		}
+
+		var post *model.Post
+		if id != uuid.Nil {
+			post, err = repo.FindPostByID(ctx, id)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err != nil {
+				post = nil
+			}
+		}
+
+		switch {
+		case post == nil:
+			post = model.NewPost(title, doc.Body, author.ID)
+			post.ID = id
+			post.CreatedAt = created
+			post.UpdatedAt = updated
+			if err := repo.CreatePost(ctx, post); err != nil {
+				return err
+			}
+			res = resultCreated
+		case post.Title != title || post.Content != doc.Body ||
+			post.UserID != author.ID || !post.CreatedAt.Equal(created) ||
+			!post.UpdatedAt.Equal(updated):
+			post.Title = title
+			post.Content = doc.Body
+			post.UserID = author.ID
+			post.CreatedAt = created
+			post.UpdatedAt = updated
+			if err := repo.UpdatePost(ctx, post); err != nil {
+				return err
+			}
+			res = resultUpdated
+		case mapping != nil && slices.Equal(mapping.Tags, tags):
+			return nil
+		case mapping != nil:
+			res = resultUpdated
+		}
+
+		return repo.SaveMapping(ctx, &model.MarkdownPost{
+			Slug:      slug,
+			PostID:    post.ID,
+			Tags:      tags,
+			UpdatedAt: time.Now().UTC(),
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	if res == resultCreated {
+		metrics.PostsPublished.Inc()
+	}
+	return res, nil
+}
+
+func (s *service) Export(ctx context.Context, dir string) (*ExportReport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	report := &ExportReport{}
+	used := map[string]bool{}
+	for cursor := uuid.Nil; ; {
+		posts, err := s.repo.FindPostsAfter(ctx, cursor, batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("export posts: %w", err)
+		}
+		ids := make([]uuid.UUID, len(posts))
+		for i, p := range posts {
+			ids[i] = p.ID
+		}
+		mappings, err := s.repo.FindMappings(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("export posts: %w", err)
+		}
+
+		for _, p := range posts {
+			doc := &Document{
+				FrontMatter: FrontMatter{
+					ID:    &p.ID,
+					Title: p.Title,
+					Date:  formatDate(p.CreatedAt),
+				},
+				Body: p.Content,
+			}
+			if p.User != nil {
+				doc.Author = p.User.Username
+			}
+			if !p.UpdatedAt.Equal(p.CreatedAt) {
+				doc.LastMod = formatDate(p.UpdatedAt)
+			}
+			if m, ok := mappings[p.ID]; ok {
+				doc.Slug = m.Slug
+				doc.Tags = m.Tags
+			} else if doc.Slug, err = s.freeSlug(ctx, p, used); err != nil {
+				return nil, err
+			}
+			used[doc.Slug] = true
+
+			data, err := Render(doc)
+			if err != nil {
+				return nil, err
+			}
+			file := filepath.Join(dir, doc.Slug+extension)
+			if err := os.WriteFile(file, data, 0o644); err != nil {
+				return nil, err
+			}
+			report.Files++
+		}
+		if len(posts) < batchSize {
+			break
+		}
+		cursor = posts[len(posts)-1].ID
+	}
+	return report, nil
+}
+
+// freeSlug derives a slug from the title of a post that was never synced,
+// numbered when another post already has it.
+func (s *service) freeSlug(ctx context.Context, p *model.Post,
+	used map[string]bool) (string, error) {
+	base := slugify(p.Title)
+	if base == "" {
+		base = "post-" + p.ID.String()[:8]
+	}
+	for n := 1; ; n++ {
+		slug := base
+		if n > 1 {
+			slug += "-" + strconv.Itoa(n)
+		}
+		if used[slug] {
+			continue
+		}
+		_, err := s.repo.FindMapping(ctx, slug)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return slug, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// slugify lowercases title and joins its ASCII letters and digits with
+// dashes.
+func slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+func NewService(r Repository) Service {
+	return &service{repo: r}
+}
@@ -0,0 +1,177 @@
+package markdown
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var alice = &model.User{ID: uuid.New(), Username: "alice"}
+
+func setup(t *testing.T) (*MockRepository, Service) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context,
+			fn func(repo Repository) error) error {
+			return fn(mockRepo)
+		}).AnyTimes()
+	return mockRepo, NewService(mockRepo)
+}
+
+func file(frontMatter string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte("---\n" + frontMatter + "\n---\nBody\n")}
+}
+
+func TestService_Import(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	existing := &model.Post{ID: uuid.New(), Title: "Old", Content: "Body\n",
+		UserID: alice.ID, CreatedAt: date, UpdatedAt: date}
+	unchanged := &model.Post{ID: uuid.New(), Title: "Same",
+		Content: "Body\n", UserID: alice.ID, CreatedAt: date, UpdatedAt: date}
+	dir := fstest.MapFS{
+		"posts/2024-03-01-new.md": file(
+			"title: New\nauthor: alice\ndate: 2024-03-01\ntags: [go]"),
+		"posts/old.md": file(
+			"title: Renamed\nauthor: alice\ndate: 2024-03-01"),
+		"posts/same.md": file(
+			"title: Same\nauthor: alice\ndate: 2024-03-01"),
+		"posts/_index.md": file("title: Posts"),
+		"posts/notes.txt": &fstest.MapFile{Data: []byte("ignored")},
+		"posts/ghost.md": file(
+			"title: Ghost\nauthor: nobody\ndate: 2024-03-01"),
+		"posts/undated.md": file("title: Undated\nauthor: alice"),
+	}
+
+	mockRepo, service := setup(t)
+	mockRepo.EXPECT().FindUserByUsername(gomock.Any(), "alice").
+		Return(alice, nil).Times(3)
+	mockRepo.EXPECT().FindUserByUsername(gomock.Any(), "nobody").
+		Return(nil, gorm.ErrRecordNotFound)
+
+	mockRepo.EXPECT().FindMapping(gomock.Any(), "new").
+		Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().CreatePost(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, p *model.Post) error {
+			assert.Equal(t, "New", p.Title)
+			assert.Equal(t, "Body\n", p.Content)
+			assert.Equal(t, date, p.CreatedAt)
+			p.ID = uuid.New()
+			return nil
+		})
+
+	mockRepo.EXPECT().FindMapping(gomock.Any(), "old").
+		Return(&model.MarkdownPost{Slug: "old", PostID: existing.ID,
+			Tags: []string{}}, nil)
+	mockRepo.EXPECT().FindPostByID(gomock.Any(), existing.ID).
+		Return(existing, nil)
+	mockRepo.EXPECT().UpdatePost(gomock.Any(), existing).Return(nil)
+
+	mockRepo.EXPECT().FindMapping(gomock.Any(), "same").
+		Return(&model.MarkdownPost{Slug: "same", PostID: unchanged.ID,
+			Tags: []string{}}, nil)
+	mockRepo.EXPECT().FindPostByID(gomock.Any(), unchanged.ID).
+		Return(unchanged, nil)
+
+	var saved []*model.MarkdownPost
+	mockRepo.EXPECT().SaveMapping(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, m *model.MarkdownPost) error {
+			saved = append(saved, m)
+			return nil
+		}).Times(2)
+
+	report, err := service.Import(context.Background(), dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.Unchanged)
+	assert.Equal(t, []*FileError{
+		{File: "posts/ghost.md", Error: `unknown author "nobody"`},
+		{File: "posts/undated.md", Error: `invalid date ""`},
+	}, report.Errors)
+	assert.Equal(t, "Renamed", existing.Title)
+	require.Len(t, saved, 2)
+	assert.Equal(t, "new", saved[0].Slug)
+	assert.Equal(t, []string{"go"}, saved[0].Tags)
+	assert.Equal(t, existing.ID, saved[1].PostID)
+}
+
+func TestService_Import_ByFrontMatterID(t *testing.T) {
+	id := uuid.New()
+	dir := fstest.MapFS{
+		"a.md": file("id: " + id.String() +
+			"\ntitle: Moved\nauthor: alice\ndate: 2024-03-01\nslug: moved"),
+		"b.md": file("title: Clash\nauthor: alice\ndate: 2024-03-01\nslug: moved"),
+	}
+	mockRepo, service := setup(t)
+	mockRepo.EXPECT().FindUserByUsername(gomock.Any(), "alice").
+		Return(alice, nil)
+	mockRepo.EXPECT().FindMapping(gomock.Any(), "moved").
+		Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().FindPostByID(gomock.Any(), id).
+		Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().CreatePost(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, p *model.Post) error {
+			assert.Equal(t, id, p.ID)
+			return nil
+		})
+	mockRepo.EXPECT().SaveMapping(gomock.Any(), gomock.Any()).Return(nil)
+
+	report, err := service.Import(context.Background(), dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, []*FileError{{File: "b.md",
+		Error: `slug "moved" is already used by a.md`}}, report.Errors)
+}
+
+func TestService_Export(t *testing.T) {
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	synced := &model.Post{ID: uuid.New(), Title: "Synced", Content: "One",
+		CreatedAt: created, UpdatedAt: created, User: alice}
+	fresh := &model.Post{ID: uuid.New(), Title: "Hello World",
+		Content: "Two\n", CreatedAt: created,
+		UpdatedAt: created.Add(time.Hour), User: alice}
+
+	mockRepo, service := setup(t)
+	mockRepo.EXPECT().FindPostsAfter(gomock.Any(), uuid.Nil, batchSize).
+		Return([]*model.Post{synced, fresh}, nil)
+	mockRepo.EXPECT().FindMappings(gomock.Any(),
+		[]uuid.UUID{synced.ID, fresh.ID}).
+		Return(map[uuid.UUID]*model.MarkdownPost{synced.ID: {
+			Slug: "synced", PostID: synced.ID, Tags: []string{"go"}}}, nil)
+	mockRepo.EXPECT().FindMapping(gomock.Any(), "hello-world").
+		Return(&model.MarkdownPost{PostID: uuid.New()}, nil)
+	mockRepo.EXPECT().FindMapping(gomock.Any(), "hello-world-2").
+		Return(nil, gorm.ErrRecordNotFound)
+	dir := t.TempDir()
+
+	report, err := service.Export(context.Background(), dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Files)
+	data, err := os.ReadFile(filepath.Join(dir, "synced.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "---\nid: "+synced.ID.String()+"\ntitle: Synced\n"+
+		"author: alice\ndate: \"2024-03-01T10:00:00Z\"\nslug: synced\n"+
+		"tags:\n  - go\n---\n\nOne\n", string(data))
+
+	data, err = os.ReadFile(filepath.Join(dir, "hello-world-2.md"))
+	require.NoError(t, err)
+	doc, err := Parse(data)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-01T11:00:00Z", doc.LastMod)
+	assert.Equal(t, "Two\n", doc.Body)
+}
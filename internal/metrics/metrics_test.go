@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware_LabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	before := testutil.ToFloat64(
+		httpRequests.WithLabelValues("GET", "/users/:id", "200"))
+	for _, id := range []string{"a", "b", "c"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+id, nil)
+		router.ServeHTTP(w, req)
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/nope", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, before+3, testutil.ToFloat64(
+		httpRequests.WithLabelValues("GET", "/users/:id", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(
+		httpRequests.WithLabelValues("GET", "unmatched", "404")))
+}
+
+func TestGormPlugin_ObservesQueries(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewGormPlugin()))
+
+	var users []*model.User
+	db.Find(&users)
+
+	assert.Equal(t, 1, testutil.CollectAndCount(dbQueryDuration))
+}
+
+func TestHandler_ExposesDomainCounters(t *testing.T) {
+	UsersCreated.Inc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+
+	Handler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), "blog_users_created_total"))
+}
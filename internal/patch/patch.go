@@ -0,0 +1,115 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"slices"
+	"sort"
+)
+
+// Media types of the patch documents accepted by PATCH endpoints.
+const (
+	MergePatch = "application/merge-patch+json"
+	JSONPatch  = "application/json-patch+json"
+)
+
+// IsPatch reports whether contentType names one of the patch formats.
+func IsPatch(contentType string) bool {
+	return contentType == MergePatch || contentType == JSONPatch
+}
+
+// Apply applies a merge patch (RFC 7396) or JSON patch (RFC 6902) document
+// to the JSON representation of current and decodes the result into
+// target. Members not listed in writable may be tested but not changed.
+// Malformed documents are reported as InvalidInputError and failed test
+// operations as ConflictError.
+func Apply(contentType string, current any, doc []byte,
+	writable []string, target any) error {
+	original, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch contentType {
+	case MergePatch:
+		if !json.Valid(doc) || !bytes.HasPrefix(bytes.TrimSpace(doc), []byte("{")) {
+			return apperrors.NewInvalidInputError(
+				"merge patch must be a JSON object")
+		}
+		patched, err = jsonpatch.MergePatch(original, doc)
+	case JSONPatch:
+		var ops jsonpatch.Patch
+		ops, err = jsonpatch.DecodePatch(doc)
+		if err != nil {
+			return apperrors.NewInvalidInputError(
+				"invalid JSON patch: " + err.Error())
+		}
+		patched, err = ops.Apply(original)
+	default:
+		return fmt.Errorf("unsupported patch type %q", contentType)
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return apperrors.NewConflictError(err.Error())
+	}
+	if err != nil {
+		return apperrors.NewInvalidInputError("patch cannot be applied: " +
+			err.Error())
+	}
+
+	if err := checkReadOnly(original, patched, writable); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(patched, target); err != nil {
+		return apperrors.NewInvalidInputError(
+			"patched document is invalid: " + err.Error())
+	}
+	return nil
+}
+
+func checkReadOnly(original, patched []byte, writable []string) error {
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(original, &before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return apperrors.NewInvalidInputError(
+			"patched document must be a JSON object")
+	}
+
+	var changed []string
+	for _, m := range []map[string]json.RawMessage{before, after} {
+		for member := range m {
+			if slices.Contains(writable, member) ||
+				slices.Contains(changed, member) {
+				continue
+			}
+			if !jsonEqual(before[member], after[member]) {
+				changed = append(changed, member)
+			}
+		}
+	}
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		return apperrors.NewInvalidInputError(
+			fmt.Sprintf("read-only fields cannot be changed: %v", changed))
+	}
+	return nil
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return bytes.Equal(ja, jb)
+}
@@ -0,0 +1,131 @@
+package patch
+
+import (
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type resource struct {
+	ID    int      `json:"id"`
+	Title string   `json:"title"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+type target struct {
+	Title *string  `json:"title"`
+	Tags  []string `json:"tags"`
+}
+
+func TestApply(t *testing.T) {
+	current := resource{ID: 1, Title: "old", Tags: []string{"go"}}
+	writable := []string{"title", "tags"}
+	tests := []struct {
+		name        string
+		contentType string
+		doc         string
+		wantTitle   *string
+		wantTags    []string
+		wantErr     error
+		wantErrMsg  string
+	}{
+		{
+			name:        "merge patch",
+			contentType: MergePatch,
+			doc:         `{"title":"new"}`,
+			wantTitle:   ptr("new"),
+			wantTags:    []string{"go"},
+		},
+		{
+			name:        "merge patch null removes member",
+			contentType: MergePatch,
+			doc:         `{"title":null}`,
+			wantTitle:   nil,
+			wantTags:    []string{"go"},
+		},
+		{
+			name:        "merge patch must be an object",
+			contentType: MergePatch,
+			doc:         `["title"]`,
+			wantErr:     &apperrors.InvalidInputError{},
+			wantErrMsg:  "merge patch must be a JSON object",
+		},
+		{
+			name:        "json patch",
+			contentType: JSONPatch,
+			doc: `[{"op":"test","path":"/title","value":"old"},
+				{"op":"replace","path":"/title","value":"new"},
+				{"op":"add","path":"/tags/-","value":"api"}]`,
+			wantTitle: ptr("new"),
+			wantTags:  []string{"go", "api"},
+		},
+		{
+			name:        "json patch may test read-only fields",
+			contentType: JSONPatch,
+			doc: `[{"op":"test","path":"/id","value":1},
+				{"op":"replace","path":"/title","value":"new"}]`,
+			wantTitle: ptr("new"),
+			wantTags:  []string{"go"},
+		},
+		{
+			name:        "failed test operation",
+			contentType: JSONPatch,
+			doc:         `[{"op":"test","path":"/title","value":"other"}]`,
+			wantErr:     &apperrors.ConflictError{},
+		},
+		{
+			name:        "invalid json patch",
+			contentType: JSONPatch,
+			doc:         `{"op":"replace"}`,
+			wantErr:     &apperrors.InvalidInputError{},
+			wantErrMsg:  "invalid JSON patch",
+		},
+		{
+			name:        "missing path",
+			contentType: JSONPatch,
+			doc:         `[{"op":"remove","path":"/missing"}]`,
+			wantErr:     &apperrors.InvalidInputError{},
+			wantErrMsg:  "patch cannot be applied",
+		},
+		{
+			name:        "read-only field",
+			contentType: MergePatch,
+			doc:         `{"id":2,"title":"new"}`,
+			wantErr:     &apperrors.InvalidInputError{},
+			wantErrMsg:  "read-only fields cannot be changed: [id]",
+		},
+		{
+			name:        "unknown field",
+			contentType: JSONPatch,
+			doc:         `[{"op":"add","path":"/extra","value":true}]`,
+			wantErr:     &apperrors.InvalidInputError{},
+			wantErrMsg:  "read-only fields cannot be changed: [extra]",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got target
+			err := Apply(test.contentType, current, []byte(test.doc),
+				writable, &got)
+
+			if test.wantErr != nil {
+				assert.IsType(t, test.wantErr, err)
+				assert.ErrorContains(t, err, test.wantErrMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantTitle, got.Title)
+			assert.Equal(t, test.wantTags, got.Tags)
+		})
+	}
+}
+
+func TestIsPatch(t *testing.T) {
+	assert.True(t, IsPatch(MergePatch))
+	assert.True(t, IsPatch(JSONPatch))
+	assert.False(t, IsPatch("application/json"))
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -0,0 +1,82 @@
+package post
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/batch"
+	"github.com/pandahawk/blog-api/internal/metrics"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+)
+
+// BatchOperation is one decoded operation of a batch. Invalid is set when
+// the operation could not be decoded and becomes its result.
+type BatchOperation struct {
+	Op      string
+	ID      uuid.UUID
+	Create  *CreatePostRequest
+	Update  *UpdatePostRequest
+	Invalid error
+}
+
+// BatchResult is the outcome of one operation. Post is nil for deletes and
+// failures.
+type BatchResult struct {
+	Post *model.Post
+	Err  error
+}
+
+func newBatchOperation(op *batch.Operation) *BatchOperation {
+	bop := &BatchOperation{Op: op.Op, ID: op.ID, Invalid: op.Check()}
+	if bop.Invalid != nil {
+		return bop
+	}
+	switch op.Op {
+	case batch.OpCreate:
+		bop.Create = &CreatePostRequest{}
+		bop.Invalid = op.Decode(bop.Create)
+	case batch.OpUpdate:
+		bop.Update = &UpdatePostRequest{}
+		bop.Invalid = op.Decode(bop.Update)
+	}
+	return bop
+}
+
+func (s service) ApplyBatch(ctx context.Context, ops []*BatchOperation,
+	atomic bool) ([]*BatchResult, error) {
+	posts := make([]*model.Post, len(ops))
+	errs, err := batch.Run(ctx, s.repo, s.repo.Transaction, atomic, len(ops),
+		func(repo Repository, i int) error {
+			var err error
+			posts[i], err = service{repo: repo}.apply(ctx, ops[i])
+			return err
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*BatchResult, len(ops))
+	for i, err := range errs {
+		results[i] = &BatchResult{Err: err}
+		if err == nil {
+			results[i].Post = posts[i]
+			if ops[i].Op == batch.OpCreate {
+				metrics.PostsPublished.Inc()
+			}
+		}
+	}
+	return results, nil
+}
+
+// apply runs op through the same validation as a single request.
+func (s service) apply(ctx context.Context, op *BatchOperation) (*model.Post, error) {
+	if op.Invalid != nil {
+		return nil, op.Invalid
+	}
+	switch op.Op {
+	case batch.OpCreate:
+		return s.createPost(ctx, op.Create)
+	case batch.OpUpdate:
+		return s.UpdatePost(ctx, op.ID, op.Update)
+	}
+	return nil, s.DeletePost(ctx, op.ID)
+}
@@ -2,9 +2,42 @@ package post
 
 import (
 	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/query"
 	"time"
 )
 
+// ExpandAuthor is the name of a post's author in expand=.
+const ExpandAuthor = "author"
+
+var (
+	responseFields = []string{
+		"post_id", "title", "content", "created_at", "updated_at", "reactions"}
+	responseRelations = map[string][]string{
+		ExpandAuthor: {"user_id", "username", "email"},
+	}
+
+	// responseSpec lists what fields= and expand= may name for Response.
+	responseSpec = query.Spec{
+		Fields:        responseFields,
+		Relations:     responseRelations,
+		DefaultExpand: []string{ExpandAuthor},
+	}
+	// listSpec is responseSpec for the post list, which leaves out the
+	// content unless it is asked for.
+	listSpec = query.Spec{
+		Fields:        responseFields,
+		Relations:     responseRelations,
+		DefaultExpand: []string{ExpandAuthor},
+		DefaultFields: []string{"post_id", "title", "created_at", "updated_at",
+			"reactions"},
+	}
+
+	// sortable lists the columns the posts of a user can be sorted by.
+	sortable = []string{"created_at", "updated_at", "title"}
+	// reactionSortable lists what sort= may name in reaction lists.
+	reactionSortable = []string{"reacted_at"}
+)
+
 type CreatePostRequest struct {
 	Title    string    `json:"title" binding:"required"`
 	Content  string    `json:"content" binding:"required"`
@@ -17,12 +50,30 @@ type UpdatePostRequest struct {
 }
 
 type Response struct {
-	PostID    uuid.UUID           `json:"post_id"`
-	Title     string              `json:"title"`
-	Content   string              `json:"content,omitempty"`
-	CreatedAt time.Time           `json:"created_at"`
-	UpdatedAt time.Time           `json:"updated_at"`
-	Author    UserSummaryResponse `json:"author"`
+	PostID    uuid.UUID `json:"post_id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Reactions counts the reactions to the post by kind.
+	Reactions map[string]int64     `json:"reactions" example:"like:3,love:1"`
+	Author    *UserSummaryResponse `json:"author,omitempty"`
+}
+
+// ReactionResponse is one user's reaction to a post.
+type ReactionResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username" example:"mike"`
+	Kind      string    `json:"kind" example:"like"`
+	ReactedAt time.Time `json:"reacted_at" example:"2025-07-18T15:04:05Z"`
+}
+
+// ReactionPageResponse is one page of the reactions to a post.
+type ReactionPageResponse struct {
+	Items   []*ReactionResponse `json:"items"`
+	Page    int                 `json:"page" example:"1"`
+	PerPage int                 `json:"per_page" example:"20"`
+	Total   int64               `json:"total" example:"42"`
 }
 
 type UserSummaryResponse struct {
@@ -30,3 +81,31 @@ type UserSummaryResponse struct {
 	Username string    `json:"username"`
 	Email    string    `json:"email"`
 }
+
+// PageResponse is one page of a list. Items holds the rendered entries.
+type PageResponse struct {
+	Items   any   `json:"items"`
+	Page    int   `json:"page" example:"1"`
+	PerPage int   `json:"per_page" example:"20"`
+	Total   int64 `json:"total" example:"42"`
+}
+
+// FeedResponse is one page of the feed. NextCursor is left out on the last
+// page.
+type FeedResponse struct {
+	Items      any    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty" example:"MjAyNS0wNy0xOFQxNTowNDowNVp8NGU3NmIzMjAtZDViNy00YTBhLWJiMGYtMjA0OWZlNmE5MWE3"`
+}
+
+type StatsResponse struct {
+	PostCount   int64                  `json:"post_count" example:"2"`
+	TotalWords  int64                  `json:"total_words" example:"26"`
+	FirstPostAt *time.Time             `json:"first_post_at"`
+	LastPostAt  *time.Time             `json:"last_post_at"`
+	Monthly     []MonthlyCountResponse `json:"monthly"`
+}
+
+type MonthlyCountResponse struct {
+	Month string `json:"month" example:"2025-08"`
+	Posts int64  `json:"posts" example:"2"`
+}
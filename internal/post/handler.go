@@ -1,70 +1,113 @@
 package post
 
 import (
-	"errors"
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/batch"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/patch"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
 	"net/http"
 )
 
+// ViewRecorder counts the views of posts, see analytics.Recorder.
+type ViewRecorder interface {
+	RecordView(ctx context.Context, postID uuid.UUID, clientIP string,
+		userAgent string)
+}
+
+// RecordView tells views that the request in c read the post with id. Only
+// GET requests count, so HEAD checks from caches and link checkers do not
+// inflate the figures. views may be nil.
+func RecordView(c *gin.Context, views ViewRecorder, id uuid.UUID) {
+	if views == nil || c.Request.Method != http.MethodGet {
+		return
+	}
+	views.RecordView(c.Request.Context(), id, c.ClientIP(),
+		c.Request.UserAgent())
+}
+
 type Handler struct {
 	Service Service
+	// MaxBatchSize is the most operations a batch request may hold.
+	MaxBatchSize int
+	// Views counts the reads of single posts unless it is nil.
+	Views ViewRecorder
 }
 
 func NewHandler(service Service) *Handler {
-	return &Handler{Service: service}
+	return &Handler{Service: service, MaxBatchSize: batch.DefaultMaxItems}
 }
 
-// todo check if this has to be extracted from handlers
 func handleError(c *gin.Context, err error) {
-	var de *apperrors.DuplicateError
-	if errors.As(err, &de) {
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-		return
-	}
-	var ne *apperrors.NotFoundError
-	if errors.As(err, &ne) {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		return
+	status := apperrors.StatusCode(err)
+	if status == http.StatusInternalServerError {
+		logging.FromContext(c.Request.Context()).Error("request failed",
+			"error", err)
 	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
 
-	var ie *apperrors.InvalidInputError
-	if errors.As(err, &ie) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// render writes resp trimmed to the fields and relations in opts.
+func render(c *gin.Context, status int, resp any, spec query.Spec,
+	opts query.Options) {
+	body, err := spec.Render(resp, opts)
+	if err != nil {
+		handleError(c, err)
 		return
 	}
+	c.JSON(status, body)
 }
 
-func buildPostResponse(p *model.Post, content bool) *Response {
-
-	if content {
-		return &Response{
-			PostID:    p.ID,
-			Title:     p.Title,
-			Content:   p.Content,
-			CreatedAt: p.CreatedAt,
-			UpdatedAt: p.UpdatedAt,
-			Author: UserSummaryResponse{
-				UserID:   p.User.ID,
-				Username: p.User.Username,
-				Email:    p.User.Email,
-			},
-		}
+func buildStatsResponse(s *UserStats) *StatsResponse {
+	monthly := make([]MonthlyCountResponse, len(s.Monthly))
+	for i, m := range s.Monthly {
+		monthly[i] = MonthlyCountResponse{Month: m.Month, Posts: m.Posts}
 	}
+	return &StatsResponse{
+		PostCount:   s.PostCount,
+		TotalWords:  s.TotalWords,
+		FirstPostAt: s.FirstPostAt,
+		LastPostAt:  s.LastPostAt,
+		Monthly:     monthly,
+	}
+}
 
-	return &Response{
+func buildPostResponse(p *model.Post) *Response {
+	resp := &Response{
 		PostID:    p.ID,
 		Title:     p.Title,
+		Content:   p.Content,
 		CreatedAt: p.CreatedAt,
 		UpdatedAt: p.UpdatedAt,
-		Author: UserSummaryResponse{
+		Reactions: make(map[string]int64, len(model.ReactionKinds)),
+	}
+	for _, kind := range model.ReactionKinds {
+		resp.Reactions[kind] = 0
+	}
+	for _, c := range p.ReactionCounts {
+		resp.Reactions[c.Kind] = c.Count
+	}
+	if p.User != nil {
+		resp.Author = &UserSummaryResponse{
 			UserID:   p.User.ID,
 			Username: p.User.Username,
 			Email:    p.User.Email,
-		},
+		}
 	}
+	return resp
+}
+
+// NewSummaryResponse returns the response for p without its content, for
+// other resources that list posts.
+func NewSummaryResponse(p *model.Post) *Response {
+	resp := buildPostResponse(p)
+	resp.Content = ""
+	return resp
 }
 
 func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
@@ -73,33 +116,60 @@ func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 	r.POST("", h.createPost)
 	r.PATCH("/:id", h.updatePost)
 	r.DELETE("/:id", h.deletePost)
+	r.GET("/:id/reactions", h.getReactions)
+	r.PUT("/:id/reactions/:kind", h.react)
+	r.DELETE("/:id/reactions/:kind", h.unreact)
+}
+
+// RegisterUserRoutes adds the routes for the posts of a user to the users
+// group.
+func (h *Handler) RegisterUserRoutes(r *gin.RouterGroup) {
+	r.GET("/:id/posts", h.getUserPosts)
+	r.GET("/:id/stats", h.getUserStats)
+}
+
+// RegisterFeedRoutes adds the feed of the calling user to r.
+func (h *Handler) RegisterFeedRoutes(r *gin.RouterGroup) {
+	r.GET("", h.getFeed)
 }
 
 // @Summary Get all posts
-// @Description Get all posts in the system
+// @Description Get all posts in the system. The content is left out unless
+// @Description fields asks for it.
 // @Tags posts
 // @Produce json
-// @Success 200 {array} model.Post
+// @Param fields query string false "Comma separated attributes to return, author.<attr> for author attributes"
+// @Param expand query string false "Comma separated relations to load: author (default)"
+// @Success 200 {array} Response
+// @Failure 400 {object} apperrors.InvalidInputError
 // @Router /posts [get]
 // @Security ApiKeyAuth
 func (h *Handler) getPosts(c *gin.Context) {
-	posts, err := h.Service.GetPosts()
+	opts, err := listSpec.Parse(c.Request.URL.Query())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	posts, err := h.Service.GetPosts(c.Request.Context(), opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	resp := make([]*Response, len(posts))
 	for i, p := range posts {
-		resp[i] = buildPostResponse(p, false)
+		resp[i] = buildPostResponse(p)
 	}
-	c.JSON(http.StatusOK, resp)
+	render(c, http.StatusOK, resp, listSpec, opts)
 }
 
 // @Summary Get post by ID
-// @Description Get the post with the specified ID
+// @Description Get the post with the specified ID. The read counts as a view
+// @Description of the post, see /posts/{id}/analytics.
 // @Tags posts
 // @Produce json
 // @Param id path string true "Post ID" format:"uuid"
+// @Param fields query string false "Comma separated attributes to return, author.<attr> for author attributes"
+// @Param expand query string false "Comma separated relations to load: author (default)"
 // @Success 200 {object} Response
 // @Failure 404 {object} apperrors.NotFoundError
 // @Failure 400 {object} apperrors.InvalidInputError
@@ -112,14 +182,19 @@ func (h *Handler) getPost(c *gin.Context) {
 		handleError(c, apperrors.NewInvalidInputError("ID must be a uuid"))
 		return
 	}
-	p, err := h.Service.GetPost(id)
+	opts, err := responseSpec.Parse(c.Request.URL.Query())
 	if err != nil {
 		handleError(c, err)
 		return
 	}
-	resp := buildPostResponse(p, true)
-	c.JSON(http.StatusOK, resp)
-
+	p, err := h.Service.GetPost(c.Request.Context(), id, opts)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	RecordView(c, h.Views, id)
+	resp := buildPostResponse(p)
+	render(c, http.StatusOK, resp, responseSpec, opts)
 }
 
 // @Summary Create a new post
@@ -128,9 +203,11 @@ func (h *Handler) getPost(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param post body post.CreatePostRequest true "Post data"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
 // @Success 201 {object} Response
 // @Failure 400 {object} apperrors.InvalidInputError
 // @Failure 409 {object} apperrors.DuplicateError
+// @Failure 422 {object} apperrors.ReferenceNotFoundError
 // @Router /posts [post]
 // @Security ApiKeyAuth
 func (h *Handler) createPost(c *gin.Context) {
@@ -139,19 +216,21 @@ func (h *Handler) createPost(c *gin.Context) {
 		handleError(c, apperrors.NewInvalidInputError("Invalid json body"))
 		return
 	}
-	post, err := h.Service.CreatePost(&req)
+	post, err := h.Service.CreatePost(c.Request.Context(), &req)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
-	resp := buildPostResponse(post, true)
+	resp := buildPostResponse(post)
 	c.JSON(http.StatusCreated, resp)
 }
 
 // @Summary Update post by ID
-// @Description Updates an existing post and returns the updated resource
+// @Description Updates an existing post and returns the updated resource.
+// @Description Besides the partial update below, the body may be a JSON merge
+// @Description patch or JSON patch document against the post representation.
 // @Tags posts
-// @Accept json
+// @Accept json,application/merge-patch+json,application/json-patch+json
 // @Produce json
 // @Param id path string true "Post ID" format(uuid)
 // @Param post body post.UpdatePostRequest true "Post update data"
@@ -159,6 +238,8 @@ func (h *Handler) createPost(c *gin.Context) {
 // @Failure 400 {object} apperrors.InvalidInputError
 // @Failure 404 {object} apperrors.NotFoundError
 // @Failure 400 {object} apperrors.DuplicateError
+// @Failure 409 {object} apperrors.ConflictError
+// @Failure 403 {object} apperrors.ForbiddenError
 // @Router /posts/{id} [patch]
 // @Security ApiKeyAuth
 func (h *Handler) updatePost(c *gin.Context) {
@@ -169,19 +250,60 @@ func (h *Handler) updatePost(c *gin.Context) {
 		return
 	}
 	var req UpdatePostRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if patch.IsPatch(c.ContentType()) {
+		if err := h.bindPatch(c, id, &req); err != nil {
+			handleError(c, err)
+			return
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
 		handleError(c, apperrors.NewInvalidInputError("invalid json body"))
 		return
 	}
-	post, err := h.Service.UpdatePost(id, &req)
+	post, err := h.Service.UpdatePost(c.Request.Context(), id, &req)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
-	resp := buildPostResponse(post, true)
+	resp := buildPostResponse(post)
 	c.JSON(http.StatusOK, resp)
 }
 
+// bindPatch applies the patch document in the request body to the current
+// post and fills req with the fields that changed. Removed or nulled fields
+// become empty strings, which validation then rejects.
+func (h *Handler) bindPatch(c *gin.Context, id uuid.UUID,
+	req *UpdatePostRequest) error {
+	body, err := c.GetRawData()
+	if err != nil {
+		return apperrors.NewInvalidInputError("invalid request body")
+	}
+	current, err := h.Service.GetPost(c.Request.Context(), id,
+		query.Options{Expand: responseSpec.DefaultExpand})
+	if err != nil {
+		return err
+	}
+
+	var patched UpdatePostRequest
+	if err := patch.Apply(c.ContentType(), buildPostResponse(current),
+		body, []string{"title", "content"}, &patched); err != nil {
+		return err
+	}
+	if title := deref(patched.Title); title != current.Title {
+		req.Title = &title
+	}
+	if content := deref(patched.Content); content != current.Content {
+		req.Content = &content
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // @Summary Delete post by ID
 // @Description Deletes an existing post
 // @Tags posts
@@ -191,6 +313,7 @@ func (h *Handler) updatePost(c *gin.Context) {
 // @Success 204
 // @Failure 404 {object} apperrors.NotFoundError
 // @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
 // @Router /posts/{id} [delete]
 // @Security ApiKeyAuth
 func (h *Handler) deletePost(c *gin.Context) {
@@ -200,10 +323,306 @@ func (h *Handler) deletePost(c *gin.Context) {
 		handleError(c, apperrors.NewInvalidInputError("ID must be a uuid"))
 		return
 	}
-	err = h.Service.DeletePost(id)
+	err = h.Service.DeletePost(c.Request.Context(), id)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// @Summary Get the posts of a user
+// @Description Get one page of the posts of a user. The content is left out
+// @Description unless fields asks for it.
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param per_page query int false "Posts per page, at most 100" default(20)
+// @Param sort query string false "created_at, updated_at or title, prefixed with - for descending order" default(-created_at)
+// @Param fields query string false "Comma separated attributes to return, author.<attr> for author attributes"
+// @Param expand query string false "Comma separated relations to load: author (default)"
+// @Success 200 {object} PageResponse{items=[]Response}
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /users/{id}/posts [get]
+// @Security ApiKeyAuth
+func (h *Handler) getUserPosts(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		handleError(c, apperrors.NewInvalidInputError("ID must be a uuid"))
+		return
+	}
+	values := c.Request.URL.Query()
+	page, err := query.ParsePage(values, sortable, "-created_at")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	opts, err := listSpec.Parse(values)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	posts, total, err := h.Service.GetUserPosts(c.Request.Context(), id,
+		page, opts)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := make([]*Response, len(posts))
+	for i, p := range posts {
+		resp[i] = buildPostResponse(p)
+	}
+	items, err := listSpec.Render(resp, opts)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, &PageResponse{
+		Items:   items,
+		Page:    page.Number,
+		PerPage: page.PerPage,
+		Total:   total,
+	})
+}
+
+// @Summary Get the post statistics of a user
+// @Description Get the number of posts, total words, first and last post
+// @Description dates and the number of posts per month of a user
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Success 200 {object} StatsResponse
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /users/{id}/stats [get]
+// @Security ApiKeyAuth
+func (h *Handler) getUserStats(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		handleError(c, apperrors.NewInvalidInputError("ID must be a uuid"))
+		return
+	}
+	stats, err := h.Service.GetUserStats(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, buildStatsResponse(stats))
+}
+
+// Batch serves POST /posts:batch. It is exported because the route is
+// registered by the router rather than by RegisterRoutes.
+//
+// @Summary Create, update and delete posts in one request
+// @Description Applies up to the configured number of operations in order.
+// @Description In atomic mode (the default) one failure rolls back the whole
+// @Description batch and the other operations report 424; in best_effort
+// @Description mode every operation stands on its own. Each result carries
+// @Description the status a single request would have returned.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param batch body batch.Request true "Operations"
+// @Success 200 {object} batch.Response{results=[]batch.Result{data=Response}}
+// @Success 207 {object} batch.Response{results=[]batch.Result{data=Response}}
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Router /posts:batch [post]
+// @Security ApiKeyAuth
+func (h *Handler) Batch(c *gin.Context) {
+	var req batch.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewInvalidInputError("invalid request body"))
+		return
+	}
+	if err := req.Validate(h.MaxBatchSize); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	ops := make([]*BatchOperation, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i] = newBatchOperation(op)
+	}
+	ctx := c.Request.Context()
+	results, err := h.Service.ApplyBatch(ctx, ops, req.Atomic())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := &batch.Response{Results: make([]*batch.Result, 0, len(results))}
+	for i, r := range results {
+		var data any
+		if r.Post != nil {
+			data = buildPostResponse(r.Post)
+		}
+		result := resp.Add(i, ops[i].Op, data, r.Err)
+		if result.Status == http.StatusInternalServerError {
+			logging.FromContext(ctx).Error("batch operation failed",
+				"index", i, "error", r.Err)
+		}
+	}
+	c.JSON(resp.StatusCode(), resp)
+}
+
+// @Summary Get the feed
+// @Description Get the posts of the users the user linked to the API key
+// @Description follows, newest first. Pass next_cursor as cursor to get the
+// @Description following page. The content is left out unless fields names it.
+// @Tags posts
+// @Produce json
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param limit query int false "Posts per page" minimum(1) maximum(100) default(20)
+// @Param fields query string false "Comma separated attributes to return, author.<attr> for author attributes"
+// @Param expand query string false "Comma separated relations to load: author (default)"
+// @Success 200 {object} FeedResponse{items=[]Response}
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Router /feed [get]
+// @Security ApiKeyAuth
+func (h *Handler) getFeed(c *gin.Context) {
+	userID, err := auth.RequireUser(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	values := c.Request.URL.Query()
+	page, err := query.ParseCursorPage(values)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	opts, err := listSpec.Parse(values)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	posts, next, err := h.Service.GetFeed(c.Request.Context(), userID, page,
+		opts)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := make([]*Response, len(posts))
+	for i, p := range posts {
+		resp[i] = buildPostResponse(p)
+	}
+	items, err := listSpec.Render(resp, opts)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	feed := &FeedResponse{Items: items}
+	if next != nil {
+		feed.NextCursor = next.String()
+	}
+	c.JSON(http.StatusOK, feed)
+}
+
+// @Summary React to a post
+// @Description The user the API key is linked to reacts to the post with
+// @Description one of like, love, laugh, wow, sad or celebrate. Each user
+// @Description reacts at most once per kind, reacting again is not an error.
+// @Tags posts
+// @Param id path string true "Post ID" Format(uuid)
+// @Param kind path string true "Reaction" Enums(like, love, laugh, wow, sad, celebrate)
+// @Success 204
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /posts/{id}/reactions/{kind} [put]
+// @Security ApiKeyAuth
+func (h *Handler) react(c *gin.Context) {
+	h.changeReaction(c, h.Service.React)
+}
+
+// @Summary Remove a reaction to a post
+// @Description Removes the reaction of the user the API key is linked to.
+// @Tags posts
+// @Param id path string true "Post ID" Format(uuid)
+// @Param kind path string true "Reaction" Enums(like, love, laugh, wow, sad, celebrate)
+// @Success 204
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /posts/{id}/reactions/{kind} [delete]
+// @Security ApiKeyAuth
+func (h *Handler) unreact(c *gin.Context) {
+	h.changeReaction(c, h.Service.Unreact)
+}
+
+func (h *Handler) changeReaction(c *gin.Context, change func(ctx context.Context,
+	postID uuid.UUID, userID uuid.UUID, kind string) error) {
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleError(c, apperrors.NewInvalidInputError("ID must be a uuid"))
+		return
+	}
+	userID, err := auth.RequireUser(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	err = change(c.Request.Context(), postID, userID, c.Param("kind"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get the reactions to a post
+// @Description Get one page of the users who reacted to the post, most
+// @Description recent first
+// @Tags posts
+// @Produce json
+// @Param id path string true "Post ID" Format(uuid)
+// @Param kind query string false "Only reactions of this kind" Enums(like, love, laugh, wow, sad, celebrate)
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param per_page query int false "Reactions per page" minimum(1) maximum(100) default(20)
+// @Param sort query string false "reacted_at, - prefix for descending" default(-reacted_at)
+// @Success 200 {object} ReactionPageResponse
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /posts/{id}/reactions [get]
+// @Security ApiKeyAuth
+func (h *Handler) getReactions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleError(c, apperrors.NewInvalidInputError("ID must be a uuid"))
+		return
+	}
+	values := c.Request.URL.Query()
+	page, err := query.ParsePage(values, reactionSortable, "-reacted_at")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	reactions, total, err := h.Service.GetReactions(c.Request.Context(), id,
+		values.Get("kind"), page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	items := make([]*ReactionResponse, len(reactions))
+	for i, r := range reactions {
+		items[i] = &ReactionResponse{UserID: r.UserID, Kind: r.Kind,
+			ReactedAt: r.CreatedAt}
+		if r.User != nil {
+			items[i].Username = r.User.Username
+		}
+	}
+	c.JSON(http.StatusOK, &ReactionPageResponse{
+		Items:   items,
+		Page:    page.Number,
+		PerPage: page.PerPage,
+		Total:   total,
+	})
+}
@@ -1,18 +1,23 @@
 package post
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/batch"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func setupTestRouterWithMockService(t *testing.T) (*gin.Engine, *MockService) {
@@ -62,7 +67,7 @@ func TestHandler_GetPosts(t *testing.T) {
 				{
 					Title:   "title1",
 					Content: "content1",
-					Author: UserSummaryResponse{
+					Author: &UserSummaryResponse{
 						UserID:   uuid.Nil,
 						Username: "user1",
 					},
@@ -70,14 +75,14 @@ func TestHandler_GetPosts(t *testing.T) {
 				{
 					Title:   "title2",
 					Content: "content2",
-					Author: UserSummaryResponse{
+					Author: &UserSummaryResponse{
 						UserID:   uuid.Nil,
 						Username: "user2",
 					},
 				},
 			},
 			mockbehaviour: func(service *MockService, posts []*model.Post) {
-				service.EXPECT().GetPosts().Return(posts, nil)
+				service.EXPECT().GetPosts(gomock.Any(), gomock.Any()).Return(posts, nil)
 			},
 			wantStatus: 200,
 			wantErr:    "",
@@ -87,7 +92,7 @@ func TestHandler_GetPosts(t *testing.T) {
 			wantPosts: nil,
 			want:      nil,
 			mockbehaviour: func(service *MockService, posts []*model.Post) {
-				service.EXPECT().GetPosts().Return(nil, errors.New("failed to get posts"))
+				service.EXPECT().GetPosts(gomock.Any(), gomock.Any()).Return(nil, errors.New("failed to get posts"))
 			},
 			wantStatus: 500,
 			wantErr:    "failed to get posts",
@@ -121,6 +126,85 @@ func TestHandler_GetPosts(t *testing.T) {
 	}
 }
 
+func TestHandler_GetPosts_FieldsAndExpand(t *testing.T) {
+	posts := []*model.Post{{
+		ID:      uuid.Nil,
+		Title:   "title",
+		Content: "content",
+		User:    &model.User{ID: uuid.Nil, Username: "user1"},
+		ReactionCounts: []*model.ReactionCount{
+			{Kind: model.ReactionLike, Count: 2}},
+	}}
+	tests := []struct {
+		name       string
+		query      string
+		wantOpts   *query.Options
+		wantStatus int
+		wantBody   string
+		notInBody  string
+	}{
+		{
+			name:  "defaults leave out content",
+			query: "",
+			wantOpts: &query.Options{
+				Fields: []string{"post_id", "title", "created_at", "updated_at",
+					"reactions"},
+				Expand: []string{"author"},
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `"reactions":{"celebrate":0,"laugh":0,"like":2,` +
+				`"love":0,"sad":0,"wow":0}`,
+			notInBody: `"content"`,
+		},
+		{
+			name:  "content and author name",
+			query: "?fields=content,author.username",
+			wantOpts: &query.Options{
+				Fields: []string{"content", "author.username"},
+				Expand: []string{"author"},
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"author":{"username":"user1"},"content":"content"}]`,
+		},
+		{
+			name:  "without author",
+			query: "?expand=&fields=title",
+			wantOpts: &query.Options{
+				Fields: []string{"title"},
+				Expand: []string{},
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"title":"title"}]`,
+		},
+		{
+			name:       "author field without author",
+			query:      "?expand=&fields=author.email",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `requires expand=author`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupTestRouterWithMockService(t)
+			if test.wantOpts != nil {
+				mockService.EXPECT().
+					GetPosts(gomock.Any(), gomock.Eq(*test.wantOpts)).
+					Return(posts, nil)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/posts"+test.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantBody)
+			if test.notInBody != "" {
+				assert.NotContains(t, w.Body.String(), test.notInBody)
+			}
+		})
+	}
+}
+
 func TestHandler_GetPost(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -145,7 +229,7 @@ func TestHandler_GetPost(t *testing.T) {
 				},
 			},
 			mockbehaviour: func(service *MockService, id string, post *model.Post) {
-				service.EXPECT().GetPost(gomock.Any()).Return(post, nil)
+				service.EXPECT().GetPost(gomock.Any(), gomock.Any(), gomock.Any()).Return(post, nil)
 			},
 			wantStatus: 200,
 			wantErr:    "",
@@ -155,7 +239,7 @@ func TestHandler_GetPost(t *testing.T) {
 			id:       uuid.Nil.String(),
 			wantPost: nil,
 			mockbehaviour: func(service *MockService, id string, post *model.Post) {
-				service.EXPECT().GetPost(gomock.Any()).
+				service.EXPECT().GetPost(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, apperrors.NewNotFoundError("post", uuid.Nil))
 			},
 			wantStatus: 404,
@@ -223,7 +307,7 @@ func TestHandler_CreatePost(t *testing.T) {
 					"3c9d5f8d-91c6-4e3e-9f76-046b7e9b6c1a")},
 			},
 			mockBehaviour: func(service *MockService, post *model.Post) {
-				service.EXPECT().CreatePost(gomock.Any()).Return(post, nil)
+				service.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(post, nil)
 			},
 			wantStatus: 201,
 			wantErr:    "",
@@ -235,11 +319,24 @@ func TestHandler_CreatePost(t *testing.T) {
 				"author_id":"3c9d5f8d-91c6-4e3e-9f76-046b7e9b6c1a"}`,
 			wantPost: nil,
 			mockBehaviour: func(service *MockService, post *model.Post) {
-				service.EXPECT().CreatePost(gomock.Any()).Return(nil, apperrors.NewDuplicateError("username"))
+				service.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(nil, apperrors.NewDuplicateError("username"))
 			},
 			wantStatus: 409,
 			wantErr:    "duplicate username",
 		},
+		{
+			name: "author not found",
+			rawBody: `{"title":"title",
+				"content":"content",
+				"author_id":"3c9d5f8d-91c6-4e3e-9f76-046b7e9b6c1a"}`,
+			wantPost: nil,
+			mockBehaviour: func(service *MockService, post *model.Post) {
+				service.EXPECT().CreatePost(gomock.Any(), gomock.Any()).
+					Return(nil, apperrors.NewReferenceNotFoundError("author_id"))
+			},
+			wantStatus: 422,
+			wantErr:    "author_id references a resource that does not exist",
+		},
 		{
 			name: "invalid json body",
 			rawBody: `
@@ -261,7 +358,7 @@ func TestHandler_CreatePost(t *testing.T) {
 				`,
 			wantPost: nil,
 			mockBehaviour: func(service *MockService, post *model.Post) {
-				service.EXPECT().CreatePost(gomock.Any()).
+				service.EXPECT().CreatePost(gomock.Any(), gomock.Any()).
 					Return(nil, apperrors.NewInvalidInputError(
 						"invalid title"))
 			},
@@ -322,7 +419,7 @@ func TestHandler_UpdatePost(t *testing.T) {
 					"3c9d5f8d-91c6-4e3e-9f76-046b7e9b6c1a")},
 			},
 			mockBehaviour: func(service *MockService, id uuid.UUID, post *model.Post) {
-				service.EXPECT().UpdatePost(gomock.Any(), gomock.Any()).Return(post, nil)
+				service.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), gomock.Any()).Return(post, nil)
 			},
 			wantStatus: 200,
 			wantErr:    "",
@@ -370,7 +467,7 @@ func TestHandler_UpdatePost(t *testing.T) {
 					"3c9d5f8d-91c6-4e3e-9f76-046b7e9b6c1a")},
 			},
 			mockBehaviour: func(service *MockService, id uuid.UUID, post *model.Post) {
-				service.EXPECT().UpdatePost(gomock.Any(), gomock.Any()).
+				service.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, apperrors.NewInvalidInputError("content must not be blank"))
 			},
 			wantStatus: 400,
@@ -405,6 +502,82 @@ func TestHandler_UpdatePost(t *testing.T) {
 	}
 }
 
+func TestHandler_PatchPost(t *testing.T) {
+	id := uuid.MustParse("3c9a5f8d-91c6-4e3e-9f76-046b7e9b6c1a")
+	current := &model.Post{
+		ID:      id,
+		Title:   "title",
+		Content: "content",
+		UserID:  uuid.Nil,
+		User:    &model.User{ID: uuid.Nil, Username: "testuser01"},
+	}
+	tests := []struct {
+		name        string
+		contentType string
+		rawBody     string
+		wantReq     *UpdatePostRequest
+		wantStatus  int
+		wantErr     string
+	}{
+		{
+			name:        "merge patch",
+			contentType: "application/merge-patch+json",
+			rawBody:     `{"title":"updated"}`,
+			wantReq:     &UpdatePostRequest{Title: ptr("updated")},
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "json patch",
+			contentType: "application/json-patch+json",
+			rawBody: `[{"op":"test","path":"/title","value":"title"},
+				{"op":"replace","path":"/content","value":"updated"}]`,
+			wantReq:    &UpdatePostRequest{Content: ptr("updated")},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "failed test operation",
+			contentType: "application/json-patch+json",
+			rawBody:     `[{"op":"test","path":"/title","value":"other"}]`,
+			wantStatus:  http.StatusConflict,
+			wantErr:     "testing value /title failed",
+		},
+		{
+			name:        "read-only field",
+			contentType: "application/json-patch+json",
+			rawBody:     `[{"op":"replace","path":"/author/username","value":"x"}]`,
+			wantStatus:  http.StatusBadRequest,
+			wantErr:     "read-only fields cannot be changed: [author]",
+		},
+		{
+			name:        "merge patch must be an object",
+			contentType: "application/merge-patch+json",
+			rawBody:     `"title"`,
+			wantStatus:  http.StatusBadRequest,
+			wantErr:     "merge patch must be a JSON object",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupTestRouterWithMockService(t)
+			mockService.EXPECT().GetPost(gomock.Any(), id, gomock.Any()).Return(current, nil)
+			if test.wantReq != nil {
+				mockService.EXPECT().
+					UpdatePost(gomock.Any(), id, test.wantReq).
+					Return(current, nil)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPatch, "/posts/"+id.String(),
+				strings.NewReader(test.rawBody))
+			req.Header.Set("Content-Type", test.contentType)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantErr)
+		})
+	}
+}
+
 func TestHandler_DeletePost(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -417,7 +590,7 @@ func TestHandler_DeletePost(t *testing.T) {
 			name: "success",
 			id:   uuid.Nil.String(),
 			mockBehaviour: func(service *MockService, id uuid.UUID) {
-				service.EXPECT().DeletePost(id).Return(nil)
+				service.EXPECT().DeletePost(gomock.Any(), id).Return(nil)
 			},
 			wantStatus: 204,
 			wantErr:    "",
@@ -433,7 +606,7 @@ func TestHandler_DeletePost(t *testing.T) {
 			name: "post not found",
 			id:   uuid.Nil.String(),
 			mockBehaviour: func(service *MockService, id uuid.UUID) {
-				service.EXPECT().DeletePost(id).
+				service.EXPECT().DeletePost(gomock.Any(), id).
 					Return(apperrors.NewNotFoundError("post", uuid.Nil))
 			},
 			wantStatus: 404,
@@ -460,3 +633,538 @@ func TestHandler_DeletePost(t *testing.T) {
 		})
 	}
 }
+
+func setupUserRoutes(t *testing.T) (*gin.Engine, *MockService) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockService := NewMockService(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	NewHandler(mockService).RegisterUserRoutes(router.Group("/users"))
+	return router, mockService
+}
+
+func TestHandler_GetUserPosts(t *testing.T) {
+	userID := uuid.MustParse("3c9d5f8d-91c6-4e3e-9f76-046b7e9b6c1a")
+	posts := []*model.Post{{
+		ID:      uuid.Nil,
+		Title:   "title",
+		Content: "content",
+		User:    &model.User{ID: userID, Username: "user1"},
+	}}
+	tests := []struct {
+		name          string
+		id            string
+		query         string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name:  "success",
+			id:    userID.String(),
+			query: "?page=2&per_page=1&sort=title&fields=title",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetUserPosts(gomock.Any(), userID,
+					query.Page{Number: 2, PerPage: 1, Sort: "title"},
+					gomock.Any()).
+					Return(posts, int64(2), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"items":[{"author":{"email":"","user_id":"` +
+				userID.String() + `","username":"user1"},"title":"title"}],` +
+				`"page":2,"per_page":1,"total":2}`,
+		},
+		{
+			name:       "not an uuid",
+			id:         "abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "ID must be a uuid",
+		},
+		{
+			name:       "invalid sort",
+			id:         userID.String(),
+			query:      "?sort=content",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `cannot sort by \"content\"`,
+		},
+		{
+			name: "user not found",
+			id:   userID.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetUserPosts(gomock.Any(), userID,
+					gomock.Any(), gomock.Any()).
+					Return(nil, int64(0), apperrors.NewNotFoundError("user", userID))
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   "not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupUserRoutes(t)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockService)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet,
+				"/users/"+test.id+"/posts"+test.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantBody)
+		})
+	}
+}
+
+func TestHandler_GetUserStats(t *testing.T) {
+	userID := uuid.MustParse("3c9d5f8d-91c6-4e3e-9f76-046b7e9b6c1a")
+	first := time.Date(2025, 7, 18, 15, 4, 5, 0, time.UTC)
+	last := time.Date(2025, 8, 19, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name          string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name: "success",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetUserStats(gomock.Any(), userID).
+					Return(&UserStats{
+						PostCount:   2,
+						TotalWords:  26,
+						FirstPostAt: &first,
+						LastPostAt:  &last,
+						Monthly: []MonthCount{
+							{Month: "2025-07", Posts: 1},
+							{Month: "2025-08", Posts: 1},
+						},
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"post_count":2,"total_words":26,` +
+				`"first_post_at":"2025-07-18T15:04:05Z",` +
+				`"last_post_at":"2025-08-19T15:04:05Z",` +
+				`"monthly":[{"month":"2025-07","posts":1},` +
+				`{"month":"2025-08","posts":1}]}`,
+		},
+		{
+			name: "no posts",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetUserStats(gomock.Any(), userID).
+					Return(&UserStats{}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"post_count":0,"total_words":0,"first_post_at":null,` +
+				`"last_post_at":null,"monthly":[]}`,
+		},
+		{
+			name: "user not found",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetUserStats(gomock.Any(), userID).
+					Return(nil, apperrors.NewNotFoundError("user", userID))
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   "not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupUserRoutes(t)
+			test.mockBehaviour(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet,
+				"/users/"+userID.String()+"/stats", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantBody)
+		})
+	}
+}
+
+func TestHandler_Batch(t *testing.T) {
+	post := &model.Post{ID: uuid.Nil, Title: "new title",
+		User: &model.User{ID: uuid.Nil, Username: "author"}}
+	tests := []struct {
+		name          string
+		rawBody       string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name: "success",
+			rawBody: `{"operations":[{"op":"update",` +
+				`"id":"5faeb0b2-43b3-4a7a-aaf2-77b71ea59d90",` +
+				`"data":{"title":"new title"}}]}`,
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().ApplyBatch(gomock.Any(), gomock.Any(), true).
+					DoAndReturn(func(ctx context.Context, ops []*BatchOperation,
+						atomic bool) ([]*BatchResult, error) {
+						assert.Equal(t, "new title", *ops[0].Update.Title)
+						return []*BatchResult{{Post: post}}, nil
+					})
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"results":[{"index":0,"status":200,"data":{"post_id"`,
+		},
+		{
+			name: "rolled back",
+			rawBody: `{"operations":[` +
+				`{"op":"delete","id":"5faeb0b2-43b3-4a7a-aaf2-77b71ea59d90"},` +
+				`{"op":"delete","id":"5faeb0b2-43b3-4a7a-aaf2-77b71ea59d91"}]}`,
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().ApplyBatch(gomock.Any(), gomock.Any(), true).
+					Return([]*BatchResult{
+						{Err: batch.ErrRolledBack},
+						{Err: apperrors.NewNotFoundError("post", uuid.Nil)},
+					}, nil)
+			},
+			wantStatus: http.StatusMultiStatus,
+			wantBody:   `"succeeded":0,"failed":2}`,
+		},
+		{
+			name:       "unknown mode",
+			rawBody:    `{"mode":"eventually","operations":[{"op":"delete"}]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `mode must be \"atomic\" or \"best_effort\"`,
+		},
+		{
+			name:       "no operations",
+			rawBody:    `{"operations":[]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "a batch needs between 1 and 2 operations",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := NewMockService(ctrl)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockService)
+			}
+			handler := NewHandler(mockService)
+			handler.MaxBatchSize = 2
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/posts:batch", handler.Batch)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/posts:batch",
+				strings.NewReader(test.rawBody))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantBody)
+		})
+	}
+}
+
+func TestHandler_GetFeed(t *testing.T) {
+	userID := uuid.New()
+	next := query.Cursor{CreatedAt: time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC),
+		ID: uuid.New()}
+	posts := []*model.Post{{ID: uuid.Nil, Title: "title", Content: "content"}}
+	tests := []struct {
+		name          string
+		actor         bool
+		query         string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name:  "first page",
+			actor: true,
+			query: "?limit=1&fields=title&expand=",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetFeed(gomock.Any(), userID,
+					query.CursorPage{Limit: 1}, gomock.Any()).
+					Return(posts, &next, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"items":[{"title":"title"}],"next_cursor":"` +
+				next.String() + `"}`,
+		},
+		{
+			name:  "last page",
+			actor: true,
+			query: "?cursor=" + next.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetFeed(gomock.Any(), userID,
+					query.CursorPage{Cursor: &next, Limit: query.DefaultPerPage},
+					gomock.Any()).
+					Return(nil, nil, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"items":[]}`,
+		},
+		{
+			name:       "key without user",
+			wantStatus: http.StatusForbidden,
+			wantBody:   "the API key is not linked to a user",
+		},
+		{
+			name:       "invalid cursor",
+			actor:      true,
+			query:      "?cursor=abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "cursor is invalid",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := NewMockService(ctrl)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockService)
+			}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			if test.actor {
+				router.Use(func(c *gin.Context) {
+					c.Request = c.Request.WithContext(
+						auth.WithUser(c.Request.Context(), userID))
+				})
+			}
+			NewHandler(mockService).RegisterFeedRoutes(router.Group("/feed"))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/feed"+test.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantBody)
+		})
+	}
+}
+
+func TestHandler_React(t *testing.T) {
+	actor := uuid.New()
+	postID := uuid.New()
+	tests := []struct {
+		name          string
+		method        string
+		actor         *uuid.UUID
+		id            string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantErr       string
+	}{
+		{
+			name:   "react",
+			method: http.MethodPut,
+			actor:  &actor,
+			id:     postID.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().React(gomock.Any(), postID, actor, "like").
+					Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "unreact",
+			method: http.MethodDelete,
+			actor:  &actor,
+			id:     postID.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().Unreact(gomock.Any(), postID, actor, "like").
+					Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "key without user",
+			method:     http.MethodPut,
+			id:         postID.String(),
+			wantStatus: http.StatusForbidden,
+			wantErr:    "the API key is not linked to a user",
+		},
+		{
+			name:       "not an uuid",
+			method:     http.MethodPut,
+			actor:      &actor,
+			id:         "abc",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "ID must be a uuid",
+		},
+		{
+			name:   "post not found",
+			method: http.MethodPut,
+			actor:  &actor,
+			id:     postID.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().React(gomock.Any(), postID, actor, "like").
+					Return(apperrors.NewNotFoundError("post", postID))
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    "not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := NewMockService(ctrl)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockService)
+			}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			if test.actor != nil {
+				router.Use(func(c *gin.Context) {
+					c.Request = c.Request.WithContext(
+						auth.WithUser(c.Request.Context(), *test.actor))
+				})
+			}
+			NewHandler(mockService).RegisterRoutes(router.Group("/posts"))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(test.method,
+				"/posts/"+test.id+"/reactions/like", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			if test.wantErr != "" {
+				assert.Contains(t, w.Body.String(), test.wantErr)
+			} else {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_GetReactions(t *testing.T) {
+	postID := uuid.New()
+	user := &model.User{ID: uuid.New(), Username: "dave"}
+	reactedAt := time.Date(2025, 7, 18, 15, 4, 5, 0, time.UTC)
+	router, mockService := setupTestRouterWithMockService(t)
+	mockService.EXPECT().GetReactions(gomock.Any(), postID, "love",
+		query.Page{Number: 1, PerPage: 20, Sort: "reacted_at", Desc: true}).
+		Return([]*model.Reaction{{PostID: postID, UserID: user.ID,
+			Kind: "love", CreatedAt: reactedAt, User: user}}, int64(1), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet,
+		"/posts/"+postID.String()+"/reactions?kind=love", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items":[{"user_id":"`+user.ID.String()+`",
+		"username":"dave","kind":"love","reacted_at":"2025-07-18T15:04:05Z"}],
+		"page":1,"per_page":20,"total":1}`, w.Body.String())
+}
+
+// viewLog is a ViewRecorder that remembers the posts it was told about.
+type viewLog struct {
+	postIDs []uuid.UUID
+}
+
+func (v *viewLog) RecordView(_ context.Context, postID uuid.UUID, _ string,
+	_ string) {
+	v.postIDs = append(v.postIDs, postID)
+}
+
+func TestHandler_GetPost_RecordsView(t *testing.T) {
+	id := uuid.New()
+	ctrl := gomock.NewController(t)
+	mockService := NewMockService(ctrl)
+	mockService.EXPECT().GetPost(gomock.Any(), id, gomock.Any()).
+		Return(&model.Post{ID: id}, nil).Times(2)
+	mockService.EXPECT().GetPost(gomock.Any(), uuid.Nil, gomock.Any()).
+		Return(nil, apperrors.NewNotFoundError("post", uuid.Nil))
+	views := &viewLog{}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewHandler(mockService)
+	handler.Views = views
+	handler.RegisterRoutes(router.Group("/posts"))
+	router.HEAD("/posts/:id", handler.getPost)
+
+	for _, r := range []struct{ method, path string }{
+		{http.MethodGet, id.String()},
+		{http.MethodGet, uuid.Nil.String()},
+		{http.MethodHead, id.String()},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(r.method, "/posts/"+r.path, nil)
+		router.ServeHTTP(w, req)
+	}
+
+	assert.Equal(t, []uuid.UUID{id}, views.postIDs)
+}
+
+func TestHandler_ChangePost_Ownership(t *testing.T) {
+	owner := uuid.New()
+	other := uuid.New()
+	postID := uuid.New()
+	tests := []struct {
+		name          string
+		method        string
+		body          string
+		actor         uuid.UUID
+		mockBehaviour func(repo *MockRepository, post *model.Post)
+		wantStatus    int
+		wantErr       string
+	}{
+		{
+			name:       "update other user's post",
+			method:     http.MethodPatch,
+			body:       `{"title":"taken over"}`,
+			actor:      other,
+			wantStatus: http.StatusForbidden,
+			wantErr:    "may only change its own user's resources",
+		},
+		{
+			name:       "delete other user's post",
+			method:     http.MethodDelete,
+			actor:      other,
+			wantStatus: http.StatusForbidden,
+			wantErr:    "may only change its own user's resources",
+		},
+		{
+			name:   "delete own post",
+			method: http.MethodDelete,
+			actor:  owner,
+			mockBehaviour: func(repo *MockRepository, post *model.Post) {
+				repo.EXPECT().Delete(gomock.Any(), post).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockRepository(ctrl)
+			post := &model.Post{ID: postID, Title: "title", UserID: owner,
+				User: &model.User{ID: owner, Username: "owner"}}
+			mockRepo.EXPECT().FindByID(gomock.Any(), postID, gomock.Any()).
+				Return(post, nil)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockRepo, post)
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Request = c.Request.WithContext(
+					auth.WithUser(c.Request.Context(), test.actor))
+			})
+			NewHandler(NewService(mockRepo)).RegisterRoutes(router.Group("/posts"))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(test.method, "/posts/"+postID.String(),
+				strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantErr)
+		})
+	}
+}
@@ -1,55 +1,213 @@
 package post
 
 import (
+	"context"
 	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //go:generate mockgen -source=repository.go -destination=repository_mock.go -package=post
 
 type Repository interface {
-	FindAll() ([]*model.Post, error)
-	FindByID(id uuid.UUID) (*model.Post, error)
-	Create(post *model.Post) (*model.Post, error)
-	Delete(post *model.Post) error
-	Update(post *model.Post) (*model.Post, error)
+	FindAll(ctx context.Context, opts query.Options) ([]*model.Post, error)
+	FindByID(ctx context.Context, id uuid.UUID, opts query.Options) (*model.Post, error)
+	Create(ctx context.Context, post *model.Post) (*model.Post, error)
+	Delete(ctx context.Context, post *model.Post) error
+	Update(ctx context.Context, post *model.Post) (*model.Post, error)
+	// Transaction runs fn with a repository whose queries share one
+	// transaction, committed if fn returns nil and rolled back otherwise.
+	Transaction(ctx context.Context, fn func(repo Repository) error) error
+	// FindPage returns one page of all posts and the number of posts in
+	// total.
+	FindPage(ctx context.Context, page query.Page,
+		opts query.Options) ([]*model.Post, int64, error)
+	// FindByUser returns one page of the posts of a user and the number of
+	// posts the user has in total.
+	FindByUser(ctx context.Context, userID uuid.UUID, page query.Page,
+		opts query.Options) ([]*model.Post, int64, error)
+	// FindFeed returns up to page.Limit posts by the users userID follows,
+	// newest first, starting after page.Cursor.
+	FindFeed(ctx context.Context, userID uuid.UUID, page query.CursorPage,
+		opts query.Options) ([]*model.Post, error)
+	// CreateReaction stores r unless the user reacted with its kind already.
+	CreateReaction(ctx context.Context, r *model.Reaction) error
+	DeleteReaction(ctx context.Context, postID uuid.UUID, userID uuid.UUID, kind string) error
+	// FindReactions returns one page of the reactions to a post with their
+	// users loaded, only those of kind unless it is empty, and their number.
+	FindReactions(ctx context.Context, postID uuid.UUID, kind string,
+		page query.Page) ([]*model.Reaction, int64, error)
+	StatsByUser(ctx context.Context, userID uuid.UUID) (*UserStats, error)
+	UserExists(ctx context.Context, userID uuid.UUID) (bool, error)
 }
 
 type repository struct {
 	db *gorm.DB
 }
 
-func (r repository) FindAll() ([]*model.Post, error) {
+// preload loads the reaction counts and the relations expanded in opts.
+func preload(db *gorm.DB, opts query.Options) *gorm.DB {
+	db = db.Preload("ReactionCounts")
+	if opts.Expands(ExpandAuthor) {
+		db = db.Preload("User")
+	}
+	return db
+}
+
+func (r repository) FindAll(ctx context.Context, opts query.Options) ([]*model.Post, error) {
 	var posts []*model.Post
-	err := r.db.Preload("User").Find(&posts).Error
+	err := preload(r.db.WithContext(ctx), opts).Find(&posts).Error
 	return posts, err
 }
 
-func (r repository) FindByID(id uuid.UUID) (*model.Post, error) {
+func (r repository) FindByID(ctx context.Context, id uuid.UUID, opts query.Options) (*model.Post, error) {
 	var post model.Post
-	err := r.db.Preload("User").First(&post, id).Error
+	err := preload(r.db.WithContext(ctx), opts).First(&post, id).Error
 	return &post, err
 }
 
-func (r repository) Create(post *model.Post) (*model.Post, error) {
-	err := r.db.Preload("User").Create(post).Error
+func (r repository) Create(ctx context.Context, post *model.Post) (*model.Post, error) {
+	if err := r.db.WithContext(ctx).Create(post).Error; err != nil {
+		return nil, database.TranslateError(err)
+	}
 
-	if err := r.db.Preload("User").
+	if err := r.db.WithContext(ctx).Preload("User").Preload("ReactionCounts").
 		First(&post, "id = ?", post.ID).Error; err != nil {
 		return nil, err
 	}
-	return post, err
+	return post, nil
+}
+
+func (r repository) Delete(ctx context.Context, post *model.Post) error {
+	err := r.db.WithContext(ctx).Preload("User").Delete(post).Error
+	return database.TranslateError(err)
+}
+
+func (r repository) Update(ctx context.Context, post *model.Post) (*model.Post, error) {
+	err := r.db.WithContext(ctx).Preload("User").Omit("ReactionCounts").
+		Save(post).Error
+	return post, database.TranslateError(err)
+}
+
+func (r repository) FindPage(ctx context.Context, page query.Page,
+	opts query.Options) ([]*model.Post, int64, error) {
+	return findPage(r.db.WithContext(ctx).Model(&model.Post{}).
+		Session(&gorm.Session{}), page, opts)
+}
+
+func (r repository) FindByUser(ctx context.Context, userID uuid.UUID,
+	page query.Page, opts query.Options) ([]*model.Post, int64, error) {
+	return findPage(r.db.WithContext(ctx).Model(&model.Post{}).
+		Where("user_id = ?", userID).Session(&gorm.Session{}), page, opts)
+}
+
+// findPage counts the posts matched by db and loads one page of them.
+func findPage(db *gorm.DB, page query.Page,
+	opts query.Options) ([]*model.Post, int64, error) {
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var posts []*model.Post
+	err := preload(db, opts).
+		Order(clause.OrderByColumn{
+			Column: clause.Column{Name: page.Sort}, Desc: page.Desc}).
+		Order("id").
+		Limit(page.PerPage).
+		Offset(page.Offset()).
+		Find(&posts).Error
+	return posts, total, err
+}
+
+func (r repository) FindFeed(ctx context.Context, userID uuid.UUID,
+	page query.CursorPage, opts query.Options) ([]*model.Post, error) {
+	followees := r.db.Model(&model.Follow{}).Select("followee_id").
+		Where("follower_id = ?", userID)
+	db := preload(r.db.WithContext(ctx), opts).
+		Where("posts.user_id IN (?)", followees)
+	if c := page.Cursor; c != nil {
+		db = db.Where("(posts.created_at, posts.id) < (?, ?)",
+			c.CreatedAt, c.ID)
+	}
+	var posts []*model.Post
+	err := db.Order("posts.created_at DESC").Order("posts.id DESC").
+		Limit(page.Limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r repository) CreateReaction(ctx context.Context, reaction *model.Reaction) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(reaction).Error
+	return database.TranslateError(err)
+}
+
+func (r repository) DeleteReaction(ctx context.Context, postID uuid.UUID,
+	userID uuid.UUID, kind string) error {
+	return r.db.WithContext(ctx).
+		Where("post_id = ? AND user_id = ? AND kind = ?", postID, userID, kind).
+		Delete(&model.Reaction{}).Error
+}
+
+func (r repository) FindReactions(ctx context.Context, postID uuid.UUID,
+	kind string, page query.Page) ([]*model.Reaction, int64, error) {
+	db := r.db.WithContext(ctx).Model(&model.Reaction{}).
+		Where("post_id = ?", postID)
+	if kind != "" {
+		db = db.Where("kind = ?", kind)
+	}
+	db = db.Session(&gorm.Session{})
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var reactions []*model.Reaction
+	err := db.Preload("User").
+		Order(clause.OrderByColumn{
+			Column: clause.Column{Name: "created_at"}, Desc: page.Desc}).
+		Order("user_id").Order("kind").
+		Limit(page.PerPage).
+		Offset(page.Offset()).
+		Find(&reactions).Error
+	return reactions, total, err
+}
+
+func (r repository) StatsByUser(ctx context.Context, userID uuid.UUID) (*UserStats, error) {
+	byUser := r.db.WithContext(ctx).Model(&model.Post{}).
+		Where("user_id = ?", userID).Session(&gorm.Session{})
+
+	var stats UserStats
+	err := byUser.Select(`COUNT(*) AS post_count,
+		COALESCE(SUM(array_length(
+			regexp_split_to_array(btrim(content), '\s+'), 1)), 0) AS total_words,
+		MIN(created_at) AS first_post_at,
+		MAX(created_at) AS last_post_at`).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	err = byUser.Select(`to_char(date_trunc('month', created_at), 'YYYY-MM') AS month,
+		COUNT(*) AS posts`).
+		Group("month").
+		Order("month").
+		Scan(&stats.Monthly).Error
+	return &stats, err
 }
 
-func (r repository) Delete(post *model.Post) error {
-	err := r.db.Preload("User").Delete(post).Error
-	return err
+func (r repository) UserExists(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ?", userID).Count(&count).Error
+	return count > 0, err
 }
 
-func (r repository) Update(post *model.Post) (*model.Post, error) {
-	err := r.db.Preload("User").Save(post).Error
-	return post, err
+func (r repository) Transaction(ctx context.Context, fn func(repo Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repository{db: tx})
+	})
 }
 
 func NewRepository(db *gorm.DB) Repository {
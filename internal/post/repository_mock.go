@@ -5,10 +5,12 @@
 package post
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
+	query "github.com/pandahawk/blog-api/internal/query"
 	model "github.com/pandahawk/blog-api/internal/shared/model"
 )
 
@@ -36,75 +38,210 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockRepository) Create(post *model.Post) (*model.Post, error) {
+func (m *MockRepository) Create(ctx context.Context, post *model.Post) (*model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", post)
+	ret := m.ctrl.Call(m, "Create", ctx, post)
 	ret0, _ := ret[0].(*model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockRepositoryMockRecorder) Create(post interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Create(ctx, post interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), post)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, post)
+}
+
+// CreateReaction mocks base method.
+func (m *MockRepository) CreateReaction(ctx context.Context, r *model.Reaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReaction", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReaction indicates an expected call of CreateReaction.
+func (mr *MockRepositoryMockRecorder) CreateReaction(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReaction", reflect.TypeOf((*MockRepository)(nil).CreateReaction), ctx, r)
 }
 
 // Delete mocks base method.
-func (m *MockRepository) Delete(post *model.Post) error {
+func (m *MockRepository) Delete(ctx context.Context, post *model.Post) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", post)
+	ret := m.ctrl.Call(m, "Delete", ctx, post)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRepositoryMockRecorder) Delete(post interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(ctx, post interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, post)
+}
+
+// DeleteReaction mocks base method.
+func (m *MockRepository) DeleteReaction(ctx context.Context, postID, userID uuid.UUID, kind string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReaction", ctx, postID, userID, kind)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReaction indicates an expected call of DeleteReaction.
+func (mr *MockRepositoryMockRecorder) DeleteReaction(ctx, postID, userID, kind interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), post)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReaction", reflect.TypeOf((*MockRepository)(nil).DeleteReaction), ctx, postID, userID, kind)
 }
 
 // FindAll mocks base method.
-func (m *MockRepository) FindAll() ([]*model.Post, error) {
+func (m *MockRepository) FindAll(ctx context.Context, opts query.Options) ([]*model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll")
+	ret := m.ctrl.Call(m, "FindAll", ctx, opts)
 	ret0, _ := ret[0].([]*model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockRepositoryMockRecorder) FindAll() *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindAll(ctx, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepository)(nil).FindAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepository)(nil).FindAll), ctx, opts)
 }
 
 // FindByID mocks base method.
-func (m *MockRepository) FindByID(id uuid.UUID) (*model.Post, error) {
+func (m *MockRepository) FindByID(ctx context.Context, id uuid.UUID, opts query.Options) (*model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByID", id)
+	ret := m.ctrl.Call(m, "FindByID", ctx, id, opts)
 	ret0, _ := ret[0].(*model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByID indicates an expected call of FindByID.
-func (mr *MockRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindByID(ctx, id, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockRepository)(nil).FindByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockRepository)(nil).FindByID), ctx, id, opts)
+}
+
+// FindByUser mocks base method.
+func (m *MockRepository) FindByUser(ctx context.Context, userID uuid.UUID, page query.Page, opts query.Options) ([]*model.Post, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userID, page, opts)
+	ret0, _ := ret[0].([]*model.Post)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockRepositoryMockRecorder) FindByUser(ctx, userID, page, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockRepository)(nil).FindByUser), ctx, userID, page, opts)
+}
+
+// FindFeed mocks base method.
+func (m *MockRepository) FindFeed(ctx context.Context, userID uuid.UUID, page query.CursorPage, opts query.Options) ([]*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFeed", ctx, userID, page, opts)
+	ret0, _ := ret[0].([]*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFeed indicates an expected call of FindFeed.
+func (mr *MockRepositoryMockRecorder) FindFeed(ctx, userID, page, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFeed", reflect.TypeOf((*MockRepository)(nil).FindFeed), ctx, userID, page, opts)
+}
+
+// FindPage mocks base method.
+func (m *MockRepository) FindPage(ctx context.Context, page query.Page, opts query.Options) ([]*model.Post, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, page, opts)
+	ret0, _ := ret[0].([]*model.Post)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockRepositoryMockRecorder) FindPage(ctx, page, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockRepository)(nil).FindPage), ctx, page, opts)
+}
+
+// FindReactions mocks base method.
+func (m *MockRepository) FindReactions(ctx context.Context, postID uuid.UUID, kind string, page query.Page) ([]*model.Reaction, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReactions", ctx, postID, kind, page)
+	ret0, _ := ret[0].([]*model.Reaction)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindReactions indicates an expected call of FindReactions.
+func (mr *MockRepositoryMockRecorder) FindReactions(ctx, postID, kind, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReactions", reflect.TypeOf((*MockRepository)(nil).FindReactions), ctx, postID, kind, page)
+}
+
+// StatsByUser mocks base method.
+func (m *MockRepository) StatsByUser(ctx context.Context, userID uuid.UUID) (*UserStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatsByUser", ctx, userID)
+	ret0, _ := ret[0].(*UserStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatsByUser indicates an expected call of StatsByUser.
+func (mr *MockRepositoryMockRecorder) StatsByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatsByUser", reflect.TypeOf((*MockRepository)(nil).StatsByUser), ctx, userID)
+}
+
+// Transaction mocks base method.
+func (m *MockRepository) Transaction(ctx context.Context, fn func(Repository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockRepositoryMockRecorder) Transaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockRepository)(nil).Transaction), ctx, fn)
 }
 
 // Update mocks base method.
-func (m *MockRepository) Update(post *model.Post) (*model.Post, error) {
+func (m *MockRepository) Update(ctx context.Context, post *model.Post) (*model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", post)
+	ret := m.ctrl.Call(m, "Update", ctx, post)
 	ret0, _ := ret[0].(*model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRepositoryMockRecorder) Update(post interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(ctx, post interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, post)
+}
+
+// UserExists mocks base method.
+func (m *MockRepository) UserExists(ctx context.Context, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserExists", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserExists indicates an expected call of UserExists.
+func (mr *MockRepositoryMockRecorder) UserExists(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), post)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserExists", reflect.TypeOf((*MockRepository)(nil).UserExists), ctx, userID)
 }
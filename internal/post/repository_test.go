@@ -1,8 +1,10 @@
 package post
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
 	"github.com/pandahawk/blog-api/internal/shared/testdata"
 	"github.com/stretchr/testify/assert"
@@ -14,7 +16,7 @@ func TestRepository_FindAll(t *testing.T) {
 	db := database.SetupTestDB(t)
 	repo := NewRepository(db)
 
-	posts, err := repo.FindAll()
+	posts, err := repo.FindAll(context.Background(), query.Options{})
 	require.NoError(t, err)
 	require.Len(t, posts, len(testdata.SamplePosts))
 
@@ -30,13 +32,27 @@ func TestRepository_FindByID(t *testing.T) {
 	db := database.SetupTestDB(t)
 	repo := NewRepository(db)
 
-	post, err := repo.FindByID(testdata.PostIDs[0])
+	post, err := repo.FindByID(context.Background(), testdata.PostIDs[0],
+		query.Options{})
 
 	require.NoError(t, err)
 	assert.Equal(t, testdata.Post1.ID, post.ID)
 	assert.Equal(t, testdata.Post1.UserID, post.UserID)
 	assert.Equal(t, testdata.Post1.Title, post.Title)
 	assert.Equal(t, testdata.Post1.Content, post.Content)
+	assert.Nil(t, post.User)
+}
+
+func TestRepository_FindByID_ExpandAuthor(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+
+	post, err := repo.FindByID(context.Background(), testdata.PostIDs[0],
+		query.Options{Expand: []string{ExpandAuthor}})
+
+	require.NoError(t, err)
+	require.NotNil(t, post.User)
+	assert.Equal(t, testdata.Post1.UserID, post.User.ID)
 }
 
 func TestRepository_Create(t *testing.T) {
@@ -46,14 +62,14 @@ func TestRepository_Create(t *testing.T) {
 		"content of a new got",
 		testdata.Caren.ID)
 
-	got, err := repo.Create(post)
+	got, err := repo.Create(context.Background(), post)
 
 	require.NoError(t, err)
 	assert.Equal(t, "content of a new got", got.Content)
 	assert.Equal(t, "a new got", got.Title)
 	assert.Equal(t, testdata.Caren.ID, got.UserID)
 
-	got, err = repo.Create(post)
+	got, err = repo.Create(context.Background(), post)
 	assert.Error(t, err)
 }
 
@@ -61,7 +77,7 @@ func TestRepository_CreateWithouUser(t *testing.T) {
 	db := database.SetupTestDB(t)
 	repo := NewRepository(db)
 
-	_, err := repo.Create(model.NewPost("test", "test", uuid.Nil))
+	_, err := repo.Create(context.Background(), model.NewPost("test", "test", uuid.Nil))
 
 	assert.Error(t, err)
 
@@ -71,7 +87,7 @@ func TestRepository_Delete(t *testing.T) {
 	db := database.SetupTestDB(t)
 	repo := NewRepository(db)
 
-	err := repo.Delete(testdata.Post1)
+	err := repo.Delete(context.Background(), testdata.Post1)
 	require.NoError(t, err)
 }
 
@@ -82,7 +98,7 @@ func TestRepository_Update(t *testing.T) {
 	updatedPost.Title = "new title"
 	updatedPost.Content = "new content"
 
-	post, err := repo.Update(&updatedPost)
+	post, err := repo.Update(context.Background(), &updatedPost)
 
 	require.NoError(t, err)
 	assert.Equal(t, updatedPost.ID, post.ID)
@@ -90,3 +106,142 @@ func TestRepository_Update(t *testing.T) {
 	assert.Equal(t, updatedPost.Content, "new content")
 
 }
+
+func TestRepository_FindByUser(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+
+	page := query.Page{Number: 1, PerPage: 1, Sort: "title"}
+	posts, total, err := repo.FindByUser(context.Background(), testdata.Alice.ID,
+		page, query.Options{})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, posts, 1)
+	assert.Equal(t, testdata.Post1.Title, posts[0].Title)
+
+	page.Number = 2
+	posts, _, err = repo.FindByUser(context.Background(), testdata.Alice.ID,
+		page, query.Options{})
+
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, testdata.Post4.Title, posts[0].Title)
+}
+
+func TestRepository_StatsByUser(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+
+	stats, err := repo.StatsByUser(context.Background(), testdata.Alice.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.PostCount)
+	assert.Equal(t, int64(26), stats.TotalWords)
+	require.NotNil(t, stats.FirstPostAt)
+	require.NotNil(t, stats.LastPostAt)
+	assert.False(t, stats.LastPostAt.Before(*stats.FirstPostAt))
+	var monthly int64
+	for _, m := range stats.Monthly {
+		monthly += m.Posts
+	}
+	assert.Equal(t, int64(2), monthly)
+}
+
+func TestRepository_StatsByUser_NoPosts(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+
+	stats, err := repo.StatsByUser(context.Background(), testdata.Dave.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.PostCount)
+	assert.Nil(t, stats.FirstPostAt)
+	assert.Empty(t, stats.Monthly)
+}
+
+func TestRepository_UserExists(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+
+	exists, err := repo.UserExists(context.Background(), testdata.Alice.ID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.UserExists(context.Background(), uuid.New())
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRepository_FindFeed(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	for _, followee := range []*model.User{testdata.Alice, testdata.Bob} {
+		require.NoError(t, db.Create(
+			model.NewFollow(testdata.Dave.ID, followee.ID)).Error)
+	}
+
+	posts, err := repo.FindFeed(ctx, testdata.Dave.ID,
+		query.CursorPage{Limit: 10}, query.Options{})
+
+	require.NoError(t, err)
+	require.Len(t, posts, 4)
+	for i, p := range posts {
+		assert.NotEqual(t, testdata.Caren.ID, p.UserID)
+		if i > 0 {
+			assert.False(t, p.CreatedAt.After(posts[i-1].CreatedAt))
+		}
+	}
+
+	cursor := query.Cursor{CreatedAt: posts[1].CreatedAt, ID: posts[1].ID}
+	rest, err := repo.FindFeed(ctx, testdata.Dave.ID,
+		query.CursorPage{Cursor: &cursor, Limit: 10}, query.Options{})
+
+	require.NoError(t, err)
+	require.Len(t, rest, 2)
+	assert.Equal(t, posts[2].ID, rest[0].ID)
+}
+
+func TestRepository_Reactions(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	postID := testdata.Post1.ID
+	for _, r := range []*model.Reaction{
+		model.NewReaction(postID, testdata.Bob.ID, model.ReactionLike),
+		model.NewReaction(postID, testdata.Bob.ID, model.ReactionLike),
+		model.NewReaction(postID, testdata.Dave.ID, model.ReactionLike),
+		model.NewReaction(postID, testdata.Dave.ID, model.ReactionLove),
+	} {
+		require.NoError(t, repo.CreateReaction(ctx, r))
+	}
+	require.NoError(t, repo.DeleteReaction(ctx, postID, testdata.Dave.ID,
+		model.ReactionLove))
+
+	post, err := repo.FindByID(ctx, postID, query.Options{})
+	require.NoError(t, err)
+	counts := map[string]int64{}
+	for _, c := range post.ReactionCounts {
+		counts[c.Kind] = c.Count
+	}
+	assert.Equal(t, map[string]int64{model.ReactionLike: 2,
+		model.ReactionLove: 0}, counts)
+
+	reactions, total, err := repo.FindReactions(ctx, postID,
+		model.ReactionLike, query.Page{Number: 1, PerPage: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, reactions, 1)
+	require.NotNil(t, reactions[0].User)
+
+	// Deleting a user removes their reactions from the counts.
+	require.NoError(t, db.Delete(testdata.Dave).Error)
+	post, err = repo.FindByID(ctx, postID, query.Options{})
+	require.NoError(t, err)
+	for _, c := range post.ReactionCounts {
+		if c.Kind == model.ReactionLike {
+			assert.Equal(t, int64(1), c.Count)
+		}
+	}
+}
@@ -1,10 +1,17 @@
 package post
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/metrics"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
 	"strconv"
 	"strings"
 )
@@ -12,11 +19,34 @@ import (
 //go:generate mockgen -source=service.go -destination=service_mock.go -package=post
 
 type Service interface {
-	GetPost(id uuid.UUID) (*model.Post, error)
-	CreatePost(req *CreatePostRequest) (*model.Post, error)
-	GetPosts() ([]*model.Post, error)
-	UpdatePost(id uuid.UUID, req *UpdatePostRequest) (*model.Post, error)
-	DeletePost(id uuid.UUID) error
+	GetPost(ctx context.Context, id uuid.UUID, opts query.Options) (*model.Post, error)
+	CreatePost(ctx context.Context, req *CreatePostRequest) (*model.Post, error)
+	GetPosts(ctx context.Context, opts query.Options) ([]*model.Post, error)
+	UpdatePost(ctx context.Context, id uuid.UUID, req *UpdatePostRequest) (*model.Post, error)
+	DeletePost(ctx context.Context, id uuid.UUID) error
+	// ApplyBatch applies ops in order and returns the result of each. In
+	// atomic mode one failed operation rolls back all others.
+	ApplyBatch(ctx context.Context, ops []*BatchOperation, atomic bool) ([]*BatchResult, error)
+	// GetPostPage returns one page of all posts and the number of posts.
+	GetPostPage(ctx context.Context, page query.Page,
+		opts query.Options) ([]*model.Post, int64, error)
+	GetUserPosts(ctx context.Context, userID uuid.UUID, page query.Page,
+		opts query.Options) ([]*model.Post, int64, error)
+	GetUserStats(ctx context.Context, userID uuid.UUID) (*UserStats, error)
+	// React adds the reaction of kind by userID to a post. Reacting twice
+	// is not an error.
+	React(ctx context.Context, postID uuid.UUID, userID uuid.UUID, kind string) error
+	// Unreact removes a reaction. Removing one that does not exist is not
+	// an error.
+	Unreact(ctx context.Context, postID uuid.UUID, userID uuid.UUID, kind string) error
+	// GetReactions returns one page of the reactions to a post, only those
+	// of kind unless it is empty, and their number.
+	GetReactions(ctx context.Context, postID uuid.UUID, kind string,
+		page query.Page) ([]*model.Reaction, int64, error)
+	// GetFeed returns one page of the posts by the users userID follows,
+	// newest first, and the cursor of the next page, nil on the last.
+	GetFeed(ctx context.Context, userID uuid.UUID, page query.CursorPage,
+		opts query.Options) ([]*model.Post, *query.Cursor, error)
 }
 
 type service struct {
@@ -40,15 +70,26 @@ func isBlank(s string) bool {
 	return strings.TrimSpace(s) == ""
 }
 
-func (s service) GetPost(id uuid.UUID) (*model.Post, error) {
-	post, err := s.repo.FindByID(id)
+func (s service) GetPost(ctx context.Context, id uuid.UUID, opts query.Options) (*model.Post, error) {
+	post, err := s.repo.FindByID(ctx, id, opts)
 	if err != nil {
 		return nil, apperrors.NewNotFoundError("post", id)
 	}
 	return post, nil
 }
 
-func (s service) CreatePost(req *CreatePostRequest) (*model.Post, error) {
+func (s service) CreatePost(ctx context.Context, req *CreatePostRequest) (*model.Post, error) {
+	created, err := s.createPost(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	metrics.PostsPublished.Inc()
+	return created, nil
+}
+
+// createPost creates the post without counting it as published, so a batch
+// can count its posts once the transaction has committed.
+func (s service) createPost(ctx context.Context, req *CreatePostRequest) (*model.Post, error) {
 	if err := validateTitle(req.Title); err != nil {
 		return nil, err
 	}
@@ -58,23 +99,27 @@ func (s service) CreatePost(req *CreatePostRequest) (*model.Post, error) {
 	}
 
 	post := model.NewPost(req.Title, req.Content, req.AuthorID)
-	created, err := s.repo.Create(post)
-	return created, err
+	return s.repo.Create(ctx, post)
 }
 
-func (s service) GetPosts() ([]*model.Post, error) {
-	posts, err := s.repo.FindAll()
+func (s service) GetPosts(ctx context.Context, opts query.Options) ([]*model.Post, error) {
+	posts, err := s.repo.FindAll(ctx, opts)
 	if err != nil {
+		logging.FromContext(ctx).Error("find posts", "error", err)
 		return nil, errors.New("db error")
 	}
 	return posts, nil
 }
 
-func (s service) UpdatePost(id uuid.UUID, req *UpdatePostRequest) (*model.Post, error) {
-	post, err := s.repo.FindByID(id)
+func (s service) UpdatePost(ctx context.Context, id uuid.UUID, req *UpdatePostRequest) (*model.Post, error) {
+	post, err := s.repo.FindByID(ctx, id,
+		query.Options{Expand: []string{ExpandAuthor}})
 	if err != nil {
 		return nil, apperrors.NewNotFoundError("post", id)
 	}
+	if err := auth.RequireOwner(ctx, post.UserID); err != nil {
+		return nil, err
+	}
 	if req.Title != nil {
 		err := validateTitle(*req.Title)
 		if err != nil {
@@ -89,20 +134,158 @@ func (s service) UpdatePost(id uuid.UUID, req *UpdatePostRequest) (*model.Post,
 		}
 		post.Content = *req.Content
 	}
-	return s.repo.Update(post)
+	return s.repo.Update(ctx, post)
 }
 
-func (s service) DeletePost(id uuid.UUID) error {
-	user, err := s.repo.FindByID(id)
+func (s service) DeletePost(ctx context.Context, id uuid.UUID) error {
+	post, err := s.repo.FindByID(ctx, id, query.Options{})
 	if err != nil {
 		return apperrors.NewNotFoundError("post", id)
 	}
-	err = s.repo.Delete(user)
+	if err := auth.RequireOwner(ctx, post.UserID); err != nil {
+		return err
+	}
+	err = s.repo.Delete(ctx, post)
 	if err != nil {
+		logging.FromContext(ctx).Error("delete post",
+			"post_id", id, "error", err)
 		return errors.New("error deleting post")
 	}
 	return nil
 }
+
+func (s service) GetPostPage(ctx context.Context, page query.Page,
+	opts query.Options) ([]*model.Post, int64, error) {
+	posts, total, err := s.repo.FindPage(ctx, page, opts)
+	if err != nil {
+		logging.FromContext(ctx).Error("find post page", "error", err)
+		return nil, 0, errors.New("db error")
+	}
+	return posts, total, nil
+}
+
+// checkReaction validates kind and returns a NotFoundError when the post
+// does not exist.
+func (s service) checkReaction(ctx context.Context, postID uuid.UUID,
+	kind string) error {
+	if kind != "" && !model.IsReactionKind(kind) {
+		return apperrors.NewInvalidInputError(fmt.Sprintf(
+			"unknown reaction %q, allowed: %s", kind,
+			strings.Join(model.ReactionKinds, ",")))
+	}
+	_, err := s.repo.FindByID(ctx, postID, query.Options{})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperrors.NewNotFoundError("post", postID)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("find post", "post_id", postID,
+			"error", err)
+		return errors.New("db error")
+	}
+	return nil
+}
+
+func (s service) React(ctx context.Context, postID uuid.UUID,
+	userID uuid.UUID, kind string) error {
+	if err := s.checkReaction(ctx, postID, kind); err != nil {
+		return err
+	}
+	err := s.repo.CreateReaction(ctx, model.NewReaction(postID, userID, kind))
+	var re *apperrors.ReferenceNotFoundError
+	if errors.As(err, &re) {
+		// The post exists, so the user was deleted meanwhile.
+		return apperrors.NewNotFoundError("user", userID)
+	}
+	return err
+}
+
+func (s service) Unreact(ctx context.Context, postID uuid.UUID,
+	userID uuid.UUID, kind string) error {
+	if err := s.checkReaction(ctx, postID, kind); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteReaction(ctx, postID, userID, kind); err != nil {
+		logging.FromContext(ctx).Error("delete reaction", "post_id", postID,
+			"error", err)
+		return errors.New("db error")
+	}
+	return nil
+}
+
+func (s service) GetReactions(ctx context.Context, postID uuid.UUID,
+	kind string, page query.Page) ([]*model.Reaction, int64, error) {
+	if err := s.checkReaction(ctx, postID, kind); err != nil {
+		return nil, 0, err
+	}
+	reactions, total, err := s.repo.FindReactions(ctx, postID, kind, page)
+	if err != nil {
+		logging.FromContext(ctx).Error("find reactions", "post_id", postID,
+			"error", err)
+		return nil, 0, errors.New("db error")
+	}
+	return reactions, total, nil
+}
+
+func (s service) GetFeed(ctx context.Context, userID uuid.UUID,
+	page query.CursorPage, opts query.Options) ([]*model.Post, *query.Cursor, error) {
+	// One extra post tells whether there is a next page.
+	more := page
+	more.Limit++
+	posts, err := s.repo.FindFeed(ctx, userID, more, opts)
+	if err != nil {
+		logging.FromContext(ctx).Error("find feed", "user_id", userID,
+			"error", err)
+		return nil, nil, errors.New("db error")
+	}
+	if len(posts) <= page.Limit {
+		return posts, nil, nil
+	}
+	posts = posts[:page.Limit]
+	last := posts[len(posts)-1]
+	return posts, &query.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
+
+func (s service) GetUserPosts(ctx context.Context, userID uuid.UUID,
+	page query.Page, opts query.Options) ([]*model.Post, int64, error) {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+	posts, total, err := s.repo.FindByUser(ctx, userID, page, opts)
+	if err != nil {
+		logging.FromContext(ctx).Error("find user posts",
+			"user_id", userID, "error", err)
+		return nil, 0, errors.New("db error")
+	}
+	return posts, total, nil
+}
+
+func (s service) GetUserStats(ctx context.Context, userID uuid.UUID) (*UserStats, error) {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	stats, err := s.repo.StatsByUser(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).Error("user post stats",
+			"user_id", userID, "error", err)
+		return nil, errors.New("db error")
+	}
+	return stats, nil
+}
+
+// checkUser returns a NotFoundError unless the user exists.
+func (s service) checkUser(ctx context.Context, userID uuid.UUID) error {
+	exists, err := s.repo.UserExists(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).Error("find user",
+			"user_id", userID, "error", err)
+		return errors.New("db error")
+	}
+	if !exists {
+		return apperrors.NewNotFoundError("user", userID)
+	}
+	return nil
+}
+
 func NewService(repo Repository) Service {
 	return &service{repo: repo}
 }
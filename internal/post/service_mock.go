@@ -5,10 +5,12 @@
 package post
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
+	query "github.com/pandahawk/blog-api/internal/query"
 	model "github.com/pandahawk/blog-api/internal/shared/model"
 )
 
@@ -35,76 +37,198 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// ApplyBatch mocks base method.
+func (m *MockService) ApplyBatch(ctx context.Context, ops []*BatchOperation, atomic bool) ([]*BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyBatch", ctx, ops, atomic)
+	ret0, _ := ret[0].([]*BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyBatch indicates an expected call of ApplyBatch.
+func (mr *MockServiceMockRecorder) ApplyBatch(ctx, ops, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyBatch", reflect.TypeOf((*MockService)(nil).ApplyBatch), ctx, ops, atomic)
+}
+
 // CreatePost mocks base method.
-func (m *MockService) CreatePost(req *CreatePostRequest) (*model.Post, error) {
+func (m *MockService) CreatePost(ctx context.Context, req *CreatePostRequest) (*model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePost", req)
+	ret := m.ctrl.Call(m, "CreatePost", ctx, req)
 	ret0, _ := ret[0].(*model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreatePost indicates an expected call of CreatePost.
-func (mr *MockServiceMockRecorder) CreatePost(req interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) CreatePost(ctx, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockService)(nil).CreatePost), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockService)(nil).CreatePost), ctx, req)
 }
 
 // DeletePost mocks base method.
-func (m *MockService) DeletePost(id uuid.UUID) error {
+func (m *MockService) DeletePost(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePost", id)
+	ret := m.ctrl.Call(m, "DeletePost", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeletePost indicates an expected call of DeletePost.
-func (mr *MockServiceMockRecorder) DeletePost(id interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) DeletePost(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockService)(nil).DeletePost), ctx, id)
+}
+
+// GetFeed mocks base method.
+func (m *MockService) GetFeed(ctx context.Context, userID uuid.UUID, page query.CursorPage, opts query.Options) ([]*model.Post, *query.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, userID, page, opts)
+	ret0, _ := ret[0].([]*model.Post)
+	ret1, _ := ret[1].(*query.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockServiceMockRecorder) GetFeed(ctx, userID, page, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockService)(nil).DeletePost), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockService)(nil).GetFeed), ctx, userID, page, opts)
 }
 
 // GetPost mocks base method.
-func (m *MockService) GetPost(id uuid.UUID) (*model.Post, error) {
+func (m *MockService) GetPost(ctx context.Context, id uuid.UUID, opts query.Options) (*model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPost", id)
+	ret := m.ctrl.Call(m, "GetPost", ctx, id, opts)
 	ret0, _ := ret[0].(*model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPost indicates an expected call of GetPost.
-func (mr *MockServiceMockRecorder) GetPost(id interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetPost(ctx, id, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockService)(nil).GetPost), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockService)(nil).GetPost), ctx, id, opts)
+}
+
+// GetPostPage mocks base method.
+func (m *MockService) GetPostPage(ctx context.Context, page query.Page, opts query.Options) ([]*model.Post, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostPage", ctx, page, opts)
+	ret0, _ := ret[0].([]*model.Post)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPostPage indicates an expected call of GetPostPage.
+func (mr *MockServiceMockRecorder) GetPostPage(ctx, page, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostPage", reflect.TypeOf((*MockService)(nil).GetPostPage), ctx, page, opts)
 }
 
 // GetPosts mocks base method.
-func (m *MockService) GetPosts() ([]*model.Post, error) {
+func (m *MockService) GetPosts(ctx context.Context, opts query.Options) ([]*model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPosts")
+	ret := m.ctrl.Call(m, "GetPosts", ctx, opts)
 	ret0, _ := ret[0].([]*model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPosts indicates an expected call of GetPosts.
-func (mr *MockServiceMockRecorder) GetPosts() *gomock.Call {
+func (mr *MockServiceMockRecorder) GetPosts(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockService)(nil).GetPosts), ctx, opts)
+}
+
+// GetReactions mocks base method.
+func (m *MockService) GetReactions(ctx context.Context, postID uuid.UUID, kind string, page query.Page) ([]*model.Reaction, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReactions", ctx, postID, kind, page)
+	ret0, _ := ret[0].([]*model.Reaction)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetReactions indicates an expected call of GetReactions.
+func (mr *MockServiceMockRecorder) GetReactions(ctx, postID, kind, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReactions", reflect.TypeOf((*MockService)(nil).GetReactions), ctx, postID, kind, page)
+}
+
+// GetUserPosts mocks base method.
+func (m *MockService) GetUserPosts(ctx context.Context, userID uuid.UUID, page query.Page, opts query.Options) ([]*model.Post, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPosts", ctx, userID, page, opts)
+	ret0, _ := ret[0].([]*model.Post)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserPosts indicates an expected call of GetUserPosts.
+func (mr *MockServiceMockRecorder) GetUserPosts(ctx, userID, page, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPosts", reflect.TypeOf((*MockService)(nil).GetUserPosts), ctx, userID, page, opts)
+}
+
+// GetUserStats mocks base method.
+func (m *MockService) GetUserStats(ctx context.Context, userID uuid.UUID) (*UserStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserStats", ctx, userID)
+	ret0, _ := ret[0].(*UserStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserStats indicates an expected call of GetUserStats.
+func (mr *MockServiceMockRecorder) GetUserStats(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStats", reflect.TypeOf((*MockService)(nil).GetUserStats), ctx, userID)
+}
+
+// React mocks base method.
+func (m *MockService) React(ctx context.Context, postID, userID uuid.UUID, kind string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "React", ctx, postID, userID, kind)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// React indicates an expected call of React.
+func (mr *MockServiceMockRecorder) React(ctx, postID, userID, kind interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "React", reflect.TypeOf((*MockService)(nil).React), ctx, postID, userID, kind)
+}
+
+// Unreact mocks base method.
+func (m *MockService) Unreact(ctx context.Context, postID, userID uuid.UUID, kind string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unreact", ctx, postID, userID, kind)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unreact indicates an expected call of Unreact.
+func (mr *MockServiceMockRecorder) Unreact(ctx, postID, userID, kind interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockService)(nil).GetPosts))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unreact", reflect.TypeOf((*MockService)(nil).Unreact), ctx, postID, userID, kind)
 }
 
 // UpdatePost mocks base method.
-func (m *MockService) UpdatePost(id uuid.UUID, req *UpdatePostRequest) (*model.Post, error) {
+func (m *MockService) UpdatePost(ctx context.Context, id uuid.UUID, req *UpdatePostRequest) (*model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePost", id, req)
+	ret := m.ctrl.Call(m, "UpdatePost", ctx, id, req)
 	ret0, _ := ret[0].(*model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdatePost indicates an expected call of UpdatePost.
-func (mr *MockServiceMockRecorder) UpdatePost(id, req interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) UpdatePost(ctx, id, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockService)(nil).UpdatePost), id, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockService)(nil).UpdatePost), ctx, id, req)
 }
@@ -1,13 +1,20 @@
 package post
 
 import (
+	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/batch"
+	"github.com/pandahawk/blog-api/internal/metrics"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 	"testing"
+	"time"
 )
 
 func setup(t *testing.T) (*MockRepository, Service) {
@@ -49,7 +56,7 @@ func TestService_CreatePost(t *testing.T) {
 				},
 			},
 			mockBehaviour: func(repo *MockRepository, post *model.Post) {
-				repo.EXPECT().Create(gomock.Any()).Return(post, nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(post, nil)
 			},
 			wantErr: "",
 		},
@@ -62,7 +69,7 @@ func TestService_CreatePost(t *testing.T) {
 			},
 			want: nil,
 			mockBehaviour: func(repo *MockRepository, post *model.Post) {
-				repo.EXPECT().Create(gomock.Any()).Return(nil, errors.New("author not found"))
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("author not found"))
 			},
 			wantErr: "author not found",
 		},
@@ -106,7 +113,7 @@ func TestService_CreatePost(t *testing.T) {
 			if test.mockBehaviour != nil {
 				test.mockBehaviour(mockRepo, test.want)
 			}
-			got, err := service.CreatePost(test.req)
+			got, err := service.CreatePost(context.Background(), test.req)
 			if test.wantErr == "" {
 				assert.NoError(t, err)
 				assert.NotNil(t, got)
@@ -137,7 +144,7 @@ func TestService_GetPost(t *testing.T) {
 				Content: "This is a test gotPost",
 			},
 			expectMock: func(mockRepo *MockRepository, wantPost *model.Post) {
-				mockRepo.EXPECT().FindByID(gomock.Any()).Return(wantPost, nil)
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(wantPost, nil)
 			},
 			wantErr: "",
 		},
@@ -146,7 +153,7 @@ func TestService_GetPost(t *testing.T) {
 			searchID: uuid.Nil,
 			wantPost: nil,
 			expectMock: func(mockRepo *MockRepository, wantPost *model.Post) {
-				mockRepo.EXPECT().FindByID(gomock.Any()).
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(wantPost, apperrors.NewNotFoundError("post", uuid.Nil))
 			},
 			wantErr: "not found",
@@ -159,7 +166,7 @@ func TestService_GetPost(t *testing.T) {
 			if tt.expectMock != nil {
 				tt.expectMock(mockRepo, tt.wantPost)
 			}
-			gotPost, err := service.GetPost(tt.searchID)
+			gotPost, err := service.GetPost(context.Background(), tt.searchID, query.Options{})
 			if tt.wantErr == "" {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.wantPost, gotPost)
@@ -182,7 +189,7 @@ func TestService_GetPosts(t *testing.T) {
 			name: "success",
 			want: []*model.Post{},
 			mockBehaviour: func(repo *MockRepository, posts []*model.Post) {
-				repo.EXPECT().FindAll().Return(posts, nil)
+				repo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(posts, nil)
 			},
 			wantErr: "",
 		},
@@ -190,7 +197,7 @@ func TestService_GetPosts(t *testing.T) {
 			name: "failed",
 			want: nil,
 			mockBehaviour: func(repo *MockRepository, posts []*model.Post) {
-				repo.EXPECT().FindAll().Return(nil, errors.New("db error"))
+				repo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
 			},
 			wantErr: "db error",
 		},
@@ -201,7 +208,7 @@ func TestService_GetPosts(t *testing.T) {
 			if test.mockBehaviour != nil {
 				test.mockBehaviour(mockRepo, test.want)
 			}
-			got, err := service.GetPosts()
+			got, err := service.GetPosts(context.Background(), query.Options{})
 			if test.wantErr == "" {
 				assert.NoError(t, err)
 				assert.Equal(t, test.want, got)
@@ -229,8 +236,8 @@ func TestService_DeletePost(t *testing.T) {
 				Title:   "First Post",
 				Content: "This is a test Post"},
 			mockBehaviour: func(repo *MockRepository, id uuid.UUID, post *model.Post) {
-				repo.EXPECT().FindByID(gomock.Any()).Return(post, nil)
-				repo.EXPECT().Delete(gomock.Any()).Return(nil)
+				repo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(post, nil)
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantErr: "",
 		},
@@ -242,7 +249,7 @@ func TestService_DeletePost(t *testing.T) {
 				Title:   "First Post",
 				Content: "This is a test Post"},
 			mockBehaviour: func(repo *MockRepository, id uuid.UUID, post *model.Post) {
-				repo.EXPECT().FindByID(gomock.Any()).
+				repo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, apperrors.NewNotFoundError("post", post.ID))
 			},
 			wantErr: "not found",
@@ -255,8 +262,8 @@ func TestService_DeletePost(t *testing.T) {
 				Title:   "First Post",
 				Content: "This is a test Post"},
 			mockBehaviour: func(repo *MockRepository, id uuid.UUID, post *model.Post) {
-				repo.EXPECT().FindByID(gomock.Any()).Return(post, nil)
-				repo.EXPECT().Delete(gomock.Any()).
+				repo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(post, nil)
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any()).
 					Return(errors.New("error deleting post"))
 			},
 			wantErr: "error deleting post",
@@ -269,7 +276,7 @@ func TestService_DeletePost(t *testing.T) {
 				test.mockBehaviour(mockRepo, test.id, test.post)
 			}
 
-			err := service.DeletePost(test.id)
+			err := service.DeletePost(context.Background(), test.id)
 
 			if test.wantErr == "" {
 				assert.NoError(t, err)
@@ -302,12 +309,12 @@ func TestService_UpdatePost(t *testing.T) {
 				Content: "update post",
 			},
 			mockBehaviour: func(repo *MockRepository, id uuid.UUID, post *model.Post) {
-				repo.EXPECT().FindByID(gomock.Any()).
+				repo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(&model.Post{
 						ID:      uuid.Nil,
 						Title:   "old title",
 						Content: "old content"}, nil)
-				repo.EXPECT().Update(gomock.Any()).Return(post, nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(post, nil)
 			},
 			wantErr: "",
 		},
@@ -320,7 +327,7 @@ func TestService_UpdatePost(t *testing.T) {
 			},
 			want: nil,
 			mockBehaviour: func(repo *MockRepository, id uuid.UUID, post *model.Post) {
-				repo.EXPECT().FindByID(gomock.Any()).
+				repo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, apperrors.NewNotFoundError("post", id))
 			},
 			wantErr: "not found",
@@ -334,7 +341,7 @@ func TestService_UpdatePost(t *testing.T) {
 			},
 			want: nil,
 			mockBehaviour: func(repo *MockRepository, id uuid.UUID, post *model.Post) {
-				repo.EXPECT().FindByID(gomock.Any()).
+				repo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(&model.Post{
 						ID:      uuid.Nil,
 						Title:   "old title",
@@ -351,7 +358,7 @@ func TestService_UpdatePost(t *testing.T) {
 			},
 			want: nil,
 			mockBehaviour: func(repo *MockRepository, id uuid.UUID, post *model.Post) {
-				repo.EXPECT().FindByID(gomock.Any()).
+				repo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(&model.Post{
 						ID:      uuid.Nil,
 						Title:   "old title",
@@ -367,7 +374,7 @@ func TestService_UpdatePost(t *testing.T) {
 				test.mockBehaviour(mockRepo, test.id, test.want)
 			}
 
-			got, err := service.UpdatePost(test.id, test.req)
+			got, err := service.UpdatePost(context.Background(), test.id, test.req)
 
 			if test.wantErr == "" {
 				assert.NoError(t, err)
@@ -379,3 +386,381 @@ func TestService_UpdatePost(t *testing.T) {
 		})
 	}
 }
+
+func TestService_GetUserPosts(t *testing.T) {
+	userID := uuid.New()
+	page := query.Page{Number: 1, PerPage: 20, Sort: "created_at"}
+	tests := []struct {
+		name          string
+		mockBehaviour func(repo *MockRepository)
+		wantTotal     int64
+		wantErr       string
+	}{
+		{
+			name: "success",
+			mockBehaviour: func(repo *MockRepository) {
+				repo.EXPECT().UserExists(gomock.Any(), userID).Return(true, nil)
+				repo.EXPECT().FindByUser(gomock.Any(), userID, page, gomock.Any()).
+					Return([]*model.Post{{Title: "title"}}, int64(21), nil)
+			},
+			wantTotal: 21,
+		},
+		{
+			name: "user not found",
+			mockBehaviour: func(repo *MockRepository) {
+				repo.EXPECT().UserExists(gomock.Any(), userID).Return(false, nil)
+			},
+			wantErr: apperrors.NewNotFoundError("user", userID).Error(),
+		},
+		{
+			name: "db error",
+			mockBehaviour: func(repo *MockRepository) {
+				repo.EXPECT().UserExists(gomock.Any(), userID).Return(true, nil)
+				repo.EXPECT().FindByUser(gomock.Any(), userID, page, gomock.Any()).
+					Return(nil, int64(0), errors.New("connection refused"))
+			},
+			wantErr: "db error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setup(t)
+			test.mockBehaviour(mockRepo)
+
+			got, total, err := service.GetUserPosts(context.Background(),
+				userID, page, query.Options{})
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+				assert.Len(t, got, 1)
+				assert.Equal(t, test.wantTotal, total)
+			} else {
+				assert.Nil(t, got)
+				assert.ErrorContains(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_GetPostPage(t *testing.T) {
+	page := query.Page{Number: 2, PerPage: 10, Sort: "created_at", Desc: true}
+	t.Run("success", func(t *testing.T) {
+		mockRepo, service := setup(t)
+		mockRepo.EXPECT().FindPage(gomock.Any(), page, gomock.Any()).
+			Return([]*model.Post{{Title: "title"}}, int64(11), nil)
+
+		got, total, err := service.GetPostPage(context.Background(), page,
+			query.Options{})
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, int64(11), total)
+	})
+	t.Run("db error", func(t *testing.T) {
+		mockRepo, service := setup(t)
+		mockRepo.EXPECT().FindPage(gomock.Any(), page, gomock.Any()).
+			Return(nil, int64(0), errors.New("connection refused"))
+
+		got, _, err := service.GetPostPage(context.Background(), page,
+			query.Options{})
+
+		assert.Nil(t, got)
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+func TestService_GetUserStats(t *testing.T) {
+	userID := uuid.New()
+	stats := &UserStats{PostCount: 2, TotalWords: 26}
+	tests := []struct {
+		name          string
+		mockBehaviour func(repo *MockRepository)
+		want          *UserStats
+		wantErr       string
+	}{
+		{
+			name: "success",
+			mockBehaviour: func(repo *MockRepository) {
+				repo.EXPECT().UserExists(gomock.Any(), userID).Return(true, nil)
+				repo.EXPECT().StatsByUser(gomock.Any(), userID).Return(stats, nil)
+			},
+			want: stats,
+		},
+		{
+			name: "user not found",
+			mockBehaviour: func(repo *MockRepository) {
+				repo.EXPECT().UserExists(gomock.Any(), userID).Return(false, nil)
+			},
+			wantErr: apperrors.NewNotFoundError("user", userID).Error(),
+		},
+		{
+			name: "lookup failed",
+			mockBehaviour: func(repo *MockRepository) {
+				repo.EXPECT().UserExists(gomock.Any(), userID).
+					Return(false, errors.New("connection refused"))
+			},
+			wantErr: "db error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setup(t)
+			test.mockBehaviour(mockRepo)
+
+			got, err := service.GetUserStats(context.Background(), userID)
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			} else {
+				assert.Nil(t, got)
+				assert.ErrorContains(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_ApplyBatch(t *testing.T) {
+	id := uuid.New()
+	updated := &model.Post{ID: id, Title: "updated", Content: "content"}
+	ops := []*BatchOperation{
+		{Op: batch.OpUpdate, ID: id, Update: &UpdatePostRequest{
+			Title: ptr("updated")}},
+		{Op: batch.OpCreate, Create: &CreatePostRequest{
+			Title: "42", Content: "content"}},
+	}
+	tests := []struct {
+		name       string
+		atomic     bool
+		expectMock func(repo *MockRepository)
+		wantPosts  []*model.Post
+		wantErrs   []string
+	}{
+		{
+			name:   "atomic",
+			atomic: true,
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context,
+						fn func(Repository) error) error {
+						return fn(repo)
+					})
+				repo.EXPECT().FindByID(gomock.Any(), id, gomock.Any()).
+					Return(&model.Post{ID: id, Title: "title"}, nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					Return(updated, nil)
+			},
+			wantPosts: []*model.Post{nil, nil},
+			wantErrs: []string{batch.ErrRolledBack.Error(),
+				"title must not be a number"},
+		},
+		{
+			name:   "best effort",
+			atomic: false,
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), id, gomock.Any()).
+					Return(&model.Post{ID: id, Title: "title"}, nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					Return(updated, nil)
+			},
+			wantPosts: []*model.Post{updated, nil},
+			wantErrs:  []string{"", "title must not be a number"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setup(t)
+			test.expectMock(mockRepo)
+
+			results, err := service.ApplyBatch(context.Background(), ops,
+				test.atomic)
+
+			assert.NoError(t, err)
+			assert.Len(t, results, len(ops))
+			for i, r := range results {
+				assert.Equal(t, test.wantPosts[i], r.Post)
+				if test.wantErrs[i] == "" {
+					assert.NoError(t, r.Err)
+				} else {
+					assert.ErrorContains(t, r.Err, test.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestService_ApplyBatch_CountsPublishedAfterCommit(t *testing.T) {
+	id := uuid.New()
+	created := &model.Post{ID: uuid.New(), Title: "title"}
+	ops := []*BatchOperation{
+		{Op: batch.OpCreate, Create: &CreatePostRequest{
+			Title: "title", Content: "content"}},
+		{Op: batch.OpDelete, ID: id},
+	}
+	tests := []struct {
+		name      string
+		findErr   error
+		wantAdded float64
+	}{
+		{name: "committed", wantAdded: 1},
+		{name: "rolled back", findErr: gorm.ErrRecordNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setup(t)
+			mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context,
+					fn func(Repository) error) error {
+					return fn(mockRepo)
+				})
+			mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+				Return(created, nil)
+			mockRepo.EXPECT().FindByID(gomock.Any(), id, gomock.Any()).
+				Return(&model.Post{ID: id}, test.findErr)
+			if test.findErr == nil {
+				mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).
+					Return(nil)
+			}
+			before := testutil.ToFloat64(metrics.PostsPublished)
+
+			_, err := service.ApplyBatch(context.Background(), ops, true)
+
+			assert.NoError(t, err)
+			assert.Equal(t, before+test.wantAdded,
+				testutil.ToFloat64(metrics.PostsPublished))
+		})
+	}
+}
+
+func TestService_ApplyBatch_TransactionFails(t *testing.T) {
+	mockRepo, service := setup(t)
+	mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+		Return(errors.New("commit failed"))
+
+	results, err := service.ApplyBatch(context.Background(),
+		[]*BatchOperation{{Op: batch.OpDelete, ID: uuid.New()}}, true)
+
+	assert.Nil(t, results)
+	assert.EqualError(t, err, "commit failed")
+}
+
+func TestService_GetFeed(t *testing.T) {
+	userID := uuid.New()
+	created := time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC)
+	posts := make([]*model.Post, 3)
+	for i := range posts {
+		posts[i] = &model.Post{ID: uuid.New(),
+			CreatedAt: created.Add(-time.Duration(i) * time.Hour)}
+	}
+	tests := []struct {
+		name     string
+		found    []*model.Post
+		want     []*model.Post
+		wantNext *query.Cursor
+	}{
+		{
+			name:     "more posts",
+			found:    posts,
+			want:     posts[:2],
+			wantNext: &query.Cursor{CreatedAt: posts[1].CreatedAt, ID: posts[1].ID},
+		},
+		{
+			name:  "last page",
+			found: posts[:2],
+			want:  posts[:2],
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setup(t)
+			mockRepo.EXPECT().FindFeed(gomock.Any(), userID,
+				query.CursorPage{Limit: 3}, gomock.Any()).
+				Return(test.found, nil)
+
+			got, next, err := service.GetFeed(context.Background(), userID,
+				query.CursorPage{Limit: 2}, query.Options{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+			assert.Equal(t, test.wantNext, next)
+		})
+	}
+}
+
+func TestService_React(t *testing.T) {
+	postID := uuid.New()
+	userID := uuid.New()
+	tests := []struct {
+		name          string
+		kind          string
+		mockBehaviour func(repo *MockRepository)
+		wantErr       string
+	}{
+		{
+			name: "success",
+			kind: model.ReactionLike,
+			mockBehaviour: func(repo *MockRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), postID, gomock.Any()).
+					Return(&model.Post{ID: postID}, nil)
+				repo.EXPECT().CreateReaction(gomock.Any(),
+					model.NewReaction(postID, userID, model.ReactionLike)).
+					Return(nil)
+			},
+		},
+		{
+			name:    "unknown kind",
+			kind:    "angry",
+			wantErr: `unknown reaction "angry", allowed: like,love,laugh,wow,sad,celebrate`,
+		},
+		{
+			name: "post not found",
+			kind: model.ReactionLike,
+			mockBehaviour: func(repo *MockRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), postID, gomock.Any()).
+					Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: apperrors.NewNotFoundError("post", postID).Error(),
+		},
+		{
+			name: "user deleted",
+			kind: model.ReactionLike,
+			mockBehaviour: func(repo *MockRepository) {
+				repo.EXPECT().FindByID(gomock.Any(), postID, gomock.Any()).
+					Return(&model.Post{ID: postID}, nil)
+				repo.EXPECT().CreateReaction(gomock.Any(), gomock.Any()).
+					Return(apperrors.NewReferenceNotFoundError("user_id"))
+			},
+			wantErr: apperrors.NewNotFoundError("user", userID).Error(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setup(t)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockRepo)
+			}
+
+			err := service.React(context.Background(), postID, userID, test.kind)
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_GetReactions(t *testing.T) {
+	postID := uuid.New()
+	page := query.Page{Number: 1, PerPage: 20}
+	mockRepo, service := setup(t)
+	mockRepo.EXPECT().FindByID(gomock.Any(), postID, gomock.Any()).
+		Return(&model.Post{ID: postID}, nil)
+	mockRepo.EXPECT().FindReactions(gomock.Any(), postID, "", page).
+		Return(nil, int64(0), errors.New("connection refused"))
+
+	_, _, err := service.GetReactions(context.Background(), postID, "", page)
+
+	assert.EqualError(t, err, "db error")
+}
@@ -0,0 +1,19 @@
+package post
+
+import "time"
+
+// UserStats aggregates the posts of one user.
+type UserStats struct {
+	PostCount   int64
+	TotalWords  int64
+	FirstPostAt *time.Time
+	LastPostAt  *time.Time
+	Monthly     []MonthCount `gorm:"-"`
+}
+
+// MonthCount is the number of posts created in a month, written as
+// YYYY-MM.
+type MonthCount struct {
+	Month string
+	Posts int64
+}
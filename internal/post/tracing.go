@@ -0,0 +1,142 @@
+package post
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("post")
+
+type tracedService struct {
+	next Service
+}
+
+// NewTracedService wraps a Service so every call runs in its own span.
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next}
+}
+
+func (s *tracedService) GetPost(ctx context.Context, id uuid.UUID, opts query.Options) (*model.Post, error) {
+	ctx, span := tracer.Start(ctx, "post.Service.GetPost",
+		trace.WithAttributes(attribute.String("post.id", id.String())))
+	post, err := s.next.GetPost(ctx, id, opts)
+	tracing.End(span, err)
+	return post, err
+}
+
+func (s *tracedService) CreatePost(ctx context.Context, req *CreatePostRequest) (*model.Post, error) {
+	ctx, span := tracer.Start(ctx, "post.Service.CreatePost",
+		trace.WithAttributes(attribute.String("user.id", req.AuthorID.String())))
+	post, err := s.next.CreatePost(ctx, req)
+	tracing.End(span, err)
+	return post, err
+}
+
+func (s *tracedService) GetPosts(ctx context.Context, opts query.Options) ([]*model.Post, error) {
+	ctx, span := tracer.Start(ctx, "post.Service.GetPosts")
+	posts, err := s.next.GetPosts(ctx, opts)
+	span.SetAttributes(attribute.Int("post.count", len(posts)))
+	tracing.End(span, err)
+	return posts, err
+}
+
+func (s *tracedService) UpdatePost(ctx context.Context, id uuid.UUID, req *UpdatePostRequest) (*model.Post, error) {
+	ctx, span := tracer.Start(ctx, "post.Service.UpdatePost",
+		trace.WithAttributes(attribute.String("post.id", id.String())))
+	post, err := s.next.UpdatePost(ctx, id, req)
+	tracing.End(span, err)
+	return post, err
+}
+
+func (s *tracedService) DeletePost(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "post.Service.DeletePost",
+		trace.WithAttributes(attribute.String("post.id", id.String())))
+	err := s.next.DeletePost(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) GetPostPage(ctx context.Context, page query.Page,
+	opts query.Options) ([]*model.Post, int64, error) {
+	ctx, span := tracer.Start(ctx, "post.Service.GetPostPage",
+		trace.WithAttributes(attribute.Int("page.number", page.Number)))
+	posts, total, err := s.next.GetPostPage(ctx, page, opts)
+	span.SetAttributes(attribute.Int("post.count", len(posts)))
+	tracing.End(span, err)
+	return posts, total, err
+}
+
+func (s *tracedService) GetUserPosts(ctx context.Context, userID uuid.UUID,
+	page query.Page, opts query.Options) ([]*model.Post, int64, error) {
+	ctx, span := tracer.Start(ctx, "post.Service.GetUserPosts",
+		trace.WithAttributes(attribute.String("user.id", userID.String())))
+	posts, total, err := s.next.GetUserPosts(ctx, userID, page, opts)
+	span.SetAttributes(attribute.Int("post.count", len(posts)))
+	tracing.End(span, err)
+	return posts, total, err
+}
+
+func (s *tracedService) GetFeed(ctx context.Context, userID uuid.UUID,
+	page query.CursorPage, opts query.Options) ([]*model.Post, *query.Cursor, error) {
+	ctx, span := tracer.Start(ctx, "post.Service.GetFeed",
+		trace.WithAttributes(attribute.String("user.id", userID.String())))
+	posts, next, err := s.next.GetFeed(ctx, userID, page, opts)
+	span.SetAttributes(attribute.Int("post.count", len(posts)))
+	tracing.End(span, err)
+	return posts, next, err
+}
+
+func (s *tracedService) GetUserStats(ctx context.Context, userID uuid.UUID) (*UserStats, error) {
+	ctx, span := tracer.Start(ctx, "post.Service.GetUserStats",
+		trace.WithAttributes(attribute.String("user.id", userID.String())))
+	stats, err := s.next.GetUserStats(ctx, userID)
+	tracing.End(span, err)
+	return stats, err
+}
+
+func (s *tracedService) ApplyBatch(ctx context.Context, ops []*BatchOperation,
+	atomic bool) ([]*BatchResult, error) {
+	ctx, span := tracer.Start(ctx, "post.Service.ApplyBatch",
+		trace.WithAttributes(attribute.Int("batch.size", len(ops)),
+			attribute.Bool("batch.atomic", atomic)))
+	results, err := s.next.ApplyBatch(ctx, ops, atomic)
+	tracing.End(span, err)
+	return results, err
+}
+
+func (s *tracedService) React(ctx context.Context, postID uuid.UUID,
+	userID uuid.UUID, kind string) error {
+	ctx, span := tracer.Start(ctx, "post.Service.React",
+		trace.WithAttributes(attribute.String("post.id", postID.String()),
+			attribute.String("user.id", userID.String()),
+			attribute.String("reaction.kind", kind)))
+	err := s.next.React(ctx, postID, userID, kind)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) Unreact(ctx context.Context, postID uuid.UUID,
+	userID uuid.UUID, kind string) error {
+	ctx, span := tracer.Start(ctx, "post.Service.Unreact",
+		trace.WithAttributes(attribute.String("post.id", postID.String()),
+			attribute.String("user.id", userID.String()),
+			attribute.String("reaction.kind", kind)))
+	err := s.next.Unreact(ctx, postID, userID, kind)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) GetReactions(ctx context.Context, postID uuid.UUID,
+	kind string, page query.Page) ([]*model.Reaction, int64, error) {
+	ctx, span := tracer.Start(ctx, "post.Service.GetReactions",
+		trace.WithAttributes(attribute.String("post.id", postID.String()),
+			attribute.String("reaction.kind", kind)))
+	reactions, total, err := s.next.GetReactions(ctx, postID, kind, page)
+	tracing.End(span, err)
+	return reactions, total, err
+}
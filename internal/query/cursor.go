@@ -0,0 +1,75 @@
+package query
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cursor marks a position in a list sorted newest first, by creation time
+// and by ID among rows created at the same time. It is passed to clients
+// as an opaque string.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// String encodes the cursor for the cursor query parameter.
+func (c Cursor) String() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseCursor decodes a cursor made by Cursor.String.
+func ParseCursor(s string) (Cursor, error) {
+	invalid := apperrors.NewInvalidInputError("cursor is invalid")
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, invalid
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return Cursor{}, invalid
+	}
+	var c Cursor
+	if c.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return Cursor{}, invalid
+	}
+	if c.ID, err = uuid.Parse(id); err != nil {
+		return Cursor{}, invalid
+	}
+	return c, nil
+}
+
+// CursorPage selects the Limit rows after Cursor, or the first Limit rows
+// when Cursor is nil.
+type CursorPage struct {
+	Cursor *Cursor
+	Limit  int
+}
+
+// ParseCursorPage reads the cursor and limit parameters from values.
+func ParseCursorPage(values url.Values) (CursorPage, error) {
+	page := CursorPage{Limit: DefaultPerPage}
+	if v := values.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxPerPage {
+			return CursorPage{}, apperrors.NewInvalidInputError(fmt.Sprintf(
+				"limit must be between 1 and %d", MaxPerPage))
+		}
+		page.Limit = n
+	}
+	if v := values.Get("cursor"); v != "" {
+		c, err := ParseCursor(v)
+		if err != nil {
+			return CursorPage{}, err
+		}
+		page.Cursor = &c
+	}
+	return page, nil
+}
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	want := Cursor{CreatedAt: time.Date(2025, 7, 18, 15, 4, 5, 123456000,
+		time.UTC), ID: uuid.New()}
+
+	got, err := ParseCursor(want.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestParseCursorPage(t *testing.T) {
+	cursor := Cursor{CreatedAt: time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC),
+		ID: uuid.New()}
+	tests := []struct {
+		name    string
+		query   string
+		want    CursorPage
+		wantErr string
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			want:  CursorPage{Limit: DefaultPerPage},
+		},
+		{
+			name:  "all parameters",
+			query: "limit=5&cursor=" + cursor.String(),
+			want:  CursorPage{Cursor: &cursor, Limit: 5},
+		},
+		{
+			name:    "limit too large",
+			query:   "limit=101",
+			wantErr: "limit must be between 1 and 100",
+		},
+		{
+			name:    "cursor not base64",
+			query:   "cursor=!!",
+			wantErr: "cursor is invalid",
+		},
+		{
+			name:    "cursor without id",
+			query:   "cursor=MjAyNS0wNy0xOFQwMDowMDowMFo",
+			wantErr: "cursor is invalid",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := url.ParseQuery(test.query)
+			assert.NoError(t, err)
+
+			got, err := ParseCursorPage(values)
+
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package query
+
+import (
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// Page selects one page of a sorted list with the page, per_page and sort
+// query parameters. Sort names a column, Desc is set when sort starts
+// with "-".
+type Page struct {
+	Number  int
+	PerPage int
+	Sort    string
+	Desc    bool
+}
+
+// Offset returns the number of rows before the page.
+func (p Page) Offset() int {
+	return (p.Number - 1) * p.PerPage
+}
+
+// ParsePage reads the page, per_page and sort parameters from values.
+// sortable lists the columns that may be sorted on and defaultSort is used
+// when sort is missing, both in the "-column" form for descending order.
+func ParsePage(values url.Values, sortable []string,
+	defaultSort string) (Page, error) {
+	page := Page{Number: 1, PerPage: DefaultPerPage}
+
+	if v := values.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return Page{}, apperrors.NewInvalidInputError(
+				"page must be a positive integer")
+		}
+		page.Number = n
+	}
+	if v := values.Get("per_page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxPerPage {
+			return Page{}, apperrors.NewInvalidInputError(fmt.Sprintf(
+				"per_page must be between 1 and %d", MaxPerPage))
+		}
+		page.PerPage = n
+	}
+	// Keep the offset and the rows after it within an int, a larger page
+	// would overflow Offset and wrap around to the first page.
+	if page.Number-1 > (math.MaxInt-page.PerPage)/page.PerPage {
+		return Page{}, apperrors.NewInvalidInputError("page is too large")
+	}
+
+	sort := values.Get("sort")
+	if sort == "" {
+		sort = defaultSort
+	}
+	page.Sort, page.Desc = strings.CutPrefix(sort, "-")
+	if !slices.Contains(sortable, page.Sort) {
+		return Page{}, apperrors.NewInvalidInputError(fmt.Sprintf(
+			"cannot sort by %q, allowed: %s", page.Sort,
+			strings.Join(sortable, ",")))
+	}
+	return page, nil
+}
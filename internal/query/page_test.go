@@ -0,0 +1,80 @@
+package query
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestParsePage(t *testing.T) {
+	sortable := []string{"created_at", "title"}
+	tests := []struct {
+		name    string
+		query   string
+		want    Page
+		wantErr string
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			want: Page{Number: 1, PerPage: DefaultPerPage,
+				Sort: "created_at", Desc: true},
+		},
+		{
+			name:  "all parameters",
+			query: "page=3&per_page=10&sort=title",
+			want:  Page{Number: 3, PerPage: 10, Sort: "title"},
+		},
+		{
+			name:    "page not a number",
+			query:   "page=abc",
+			wantErr: "page must be a positive integer",
+		},
+		{
+			name:    "page zero",
+			query:   "page=0",
+			wantErr: "page must be a positive integer",
+		},
+		{
+			name:    "offset would overflow",
+			query:   "page=9223372036854775807&per_page=2",
+			wantErr: "page is too large",
+		},
+		{
+			name:  "largest page",
+			query: "page=461168601842738790",
+			want: Page{Number: 461168601842738790, PerPage: DefaultPerPage,
+				Sort: "created_at", Desc: true},
+		},
+		{
+			name:    "per_page too large",
+			query:   "per_page=101",
+			wantErr: "per_page must be between 1 and 100",
+		},
+		{
+			name:    "unknown sort column",
+			query:   "sort=-content",
+			wantErr: `cannot sort by "content", allowed: created_at,title`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := url.ParseQuery(test.query)
+			assert.NoError(t, err)
+
+			got, err := ParsePage(values, sortable, "-created_at")
+
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestPage_Offset(t *testing.T) {
+	assert.Equal(t, 0, Page{Number: 1, PerPage: 20}.Offset())
+	assert.Equal(t, 40, Page{Number: 3, PerPage: 20}.Offset())
+}
@@ -0,0 +1,183 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Options holds the sparse fieldset and the relations a client asked for
+// with the fields= and expand= query parameters. Repositories only look at
+// Expand to decide what to preload.
+type Options struct {
+	// Fields lists the attributes to return, nil means all of them.
+	// Attributes of an expanded relation are written as "relation.attr".
+	Fields []string
+	Expand []string
+}
+
+// Expands reports whether relation should be loaded.
+func (o Options) Expands(relation string) bool {
+	return slices.Contains(o.Expand, relation)
+}
+
+// Spec describes the attributes and relations of a resource representation
+// by their JSON names.
+type Spec struct {
+	Fields []string
+	// Relations maps each expandable relation to its attributes.
+	Relations map[string][]string
+	// DefaultExpand is used when the request has no expand parameter.
+	DefaultExpand []string
+	// DefaultFields is used when the request has no fields parameter, nil
+	// means all fields.
+	DefaultFields []string
+}
+
+// Parse reads the fields and expand parameters from values. Unknown names
+// are reported as InvalidInputError.
+func (s Spec) Parse(values url.Values) (Options, error) {
+	opts := Options{Fields: s.DefaultFields, Expand: s.DefaultExpand}
+
+	if values.Has("expand") {
+		opts.Expand = split(values.Get("expand"))
+		for _, relation := range opts.Expand {
+			if _, ok := s.Relations[relation]; !ok {
+				return Options{}, apperrors.NewInvalidInputError(fmt.Sprintf(
+					"cannot expand %q, allowed: %s", relation,
+					strings.Join(s.relations(), ",")))
+			}
+		}
+	}
+
+	if values.Has("fields") {
+		opts.Fields = split(values.Get("fields"))
+		for _, field := range opts.Fields {
+			if err := s.checkField(field, opts); err != nil {
+				return Options{}, err
+			}
+		}
+	}
+	return opts, nil
+}
+
+func (s Spec) checkField(field string, opts Options) error {
+	if slices.Contains(s.Fields, field) {
+		return nil
+	}
+	relation, attr, hasAttr := strings.Cut(field, ".")
+	attrs, ok := s.Relations[relation]
+	if !ok || (hasAttr && !slices.Contains(attrs, attr)) {
+		return apperrors.NewInvalidInputError(
+			fmt.Sprintf("unknown field %q", field))
+	}
+	if !opts.Expands(relation) {
+		return apperrors.NewInvalidInputError(fmt.Sprintf(
+			"field %q requires expand=%s", field, relation))
+	}
+	return nil
+}
+
+func (s Spec) relations() []string {
+	names := make([]string, 0, len(s.Relations))
+	for name := range s.Relations {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Render trims resp, a response struct or a slice of them, to the fields
+// and expanded relations in opts. Relations that were not expanded are
+// left out, expanded ones are kept even if fields does not name them.
+func (s Spec) Render(resp any, opts Options) (any, error) {
+	if opts.Fields == nil && s.expandsAll(opts) {
+		return resp, nil
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(string(data), "[") {
+		var items []map[string]any
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			s.trim(item, opts)
+		}
+		return items, nil
+	}
+	var item map[string]any
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	s.trim(item, opts)
+	return item, nil
+}
+
+func (s Spec) expandsAll(opts Options) bool {
+	for relation := range s.Relations {
+		if !opts.Expands(relation) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Spec) trim(item map[string]any, opts Options) {
+	for key := range item {
+		if _, ok := s.Relations[key]; ok {
+			if !opts.Expands(key) {
+				delete(item, key)
+			} else if attrs := nested(opts.Fields, key); len(attrs) > 0 {
+				item[key] = trimRelation(item[key], attrs)
+			}
+			continue
+		}
+		if opts.Fields != nil && !slices.Contains(opts.Fields, key) {
+			delete(item, key)
+		}
+	}
+}
+
+// nested returns the attributes of relation named in fields.
+func nested(fields []string, relation string) []string {
+	var attrs []string
+	for _, field := range fields {
+		if attr, ok := strings.CutPrefix(field, relation+"."); ok {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
+
+func trimRelation(value any, attrs []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key := range v {
+			if !slices.Contains(attrs, key) {
+				delete(v, key)
+			}
+		}
+	case []any:
+		for _, elem := range v {
+			trimRelation(elem, attrs)
+		}
+	}
+	return value
+}
+
+func split(s string) []string {
+	parts := []string{}
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
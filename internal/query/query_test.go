@@ -0,0 +1,163 @@
+package query
+
+import (
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+var spec = Spec{
+	Fields: []string{"id", "title", "content"},
+	Relations: map[string][]string{
+		"author": {"id", "name", "email"},
+		"tags":   {"name"},
+	},
+	DefaultExpand: []string{"author"},
+}
+
+type tag struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type author struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type resource struct {
+	ID      int     `json:"id"`
+	Title   string  `json:"title"`
+	Content string  `json:"content"`
+	Author  *author `json:"author,omitempty"`
+	Tags    []tag   `json:"tags"`
+}
+
+func TestSpec_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    Options
+		wantErr string
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			want:  Options{Expand: []string{"author"}},
+		},
+		{
+			name:  "fields and expand",
+			query: "fields=title,+author.name&expand=author,tags",
+			want: Options{
+				Fields: []string{"title", "author.name"},
+				Expand: []string{"author", "tags"},
+			},
+		},
+		{
+			name:  "empty expand",
+			query: "expand=",
+			want:  Options{Expand: []string{}},
+		},
+		{
+			name:    "unknown relation",
+			query:   "expand=comments",
+			wantErr: `cannot expand "comments", allowed: author,tags`,
+		},
+		{
+			name:    "unknown field",
+			query:   "fields=secret",
+			wantErr: `unknown field "secret"`,
+		},
+		{
+			name:    "unknown relation field",
+			query:   "fields=author.password",
+			wantErr: `unknown field "author.password"`,
+		},
+		{
+			name:    "field of relation not expanded",
+			query:   "fields=tags.name",
+			wantErr: `field "tags.name" requires expand=tags`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := url.ParseQuery(test.query)
+			assert.NoError(t, err)
+
+			got, err := spec.Parse(values)
+
+			if test.wantErr != "" {
+				assert.IsType(t, &apperrors.InvalidInputError{}, err)
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestSpec_Render(t *testing.T) {
+	resp := &resource{
+		ID:      1,
+		Title:   "title",
+		Content: "content",
+		Author:  &author{ID: 2, Name: "alice", Email: "alice@example.com"},
+		Tags:    []tag{{Name: "go", Color: "blue"}},
+	}
+	tests := []struct {
+		name string
+		opts Options
+		want any
+	}{
+		{
+			name: "everything",
+			opts: Options{Expand: []string{"author", "tags"}},
+			want: resp,
+		},
+		{
+			name: "relation not expanded",
+			opts: Options{Expand: []string{"author"}},
+			want: map[string]any{
+				"id":      float64(1),
+				"title":   "title",
+				"content": "content",
+				"author": map[string]any{
+					"id": float64(2), "name": "alice",
+					"email": "alice@example.com"},
+			},
+		},
+		{
+			name: "sparse fields",
+			opts: Options{
+				Fields: []string{"title", "author.name", "tags.name"},
+				Expand: []string{"author", "tags"},
+			},
+			want: map[string]any{
+				"title":  "title",
+				"author": map[string]any{"name": "alice"},
+				"tags":   []any{map[string]any{"name": "go"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := spec.Render(resp, test.opts)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestSpec_Render_Slice(t *testing.T) {
+	resp := []*resource{{ID: 1, Title: "a"}, {ID: 2, Title: "b"}}
+
+	got, err := spec.Render(resp, Options{Fields: []string{"id"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"id": float64(1)}, {"id": float64(2)}},
+		got)
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how many calls to Take pass between removals of buckets
+// that have refilled completely and so carry no state worth keeping.
+const sweepEvery = 10000
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will have refilled completely.
+	full time.Time
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	calls   int
+	now     func() time.Time
+}
+
+// NewMemoryStore returns a Store that keeps buckets in process memory. Each
+// replica then enforces its own budget.
+func NewMemoryStore() Store {
+	return &memoryStore{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+func (s *memoryStore) Take(_ context.Context, key string, policy Policy) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.calls++
+	if s.calls%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: policy.capacity(), updated: now}
+		s.buckets[key] = b
+	}
+	tokens, result := take(b.tokens, b.updated, now, policy)
+	b.tokens, b.updated = tokens, now
+	b.full = now.Add(result.ResetAfter)
+	return result, nil
+}
+
+func (s *memoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sync/atomic"
+	"time"
+)
+
+// pruneAfter is how long a bucket may go unused before it is deleted. It
+// must exceed the longest refill period of any policy.
+const pruneAfter = 24 * time.Hour
+
+type bucketRow struct {
+	Key       string `gorm:"primaryKey"`
+	Tokens    float64
+	UpdatedAt time.Time `gorm:"autoUpdateTime:false"`
+}
+
+func (bucketRow) TableName() string {
+	return "rate_limit_buckets"
+}
+
+type postgresStore struct {
+	db    *gorm.DB
+	calls atomic.Int64
+	now   func() time.Time
+}
+
+// NewPostgresStore returns a Store that keeps buckets in the
+// rate_limit_buckets table, so every replica draws from the same budget.
+// Each bucket is updated under a row lock.
+func NewPostgresStore(db *gorm.DB) Store {
+	return &postgresStore{db: db, now: time.Now}
+}
+
+func (s *postgresStore) Take(ctx context.Context, key string, policy Policy) (*Result, error) {
+	if s.calls.Add(1)%sweepEvery == 0 {
+		s.prune(ctx)
+	}
+
+	var result *Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := s.now()
+		row := bucketRow{Key: key, Tokens: policy.capacity(), UpdatedAt: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&row).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&row, "key = ?", key).Error; err != nil {
+			return err
+		}
+
+		var tokens float64
+		tokens, result = take(row.Tokens, row.UpdatedAt, now, policy)
+		return tx.Model(&row).Updates(map[string]any{
+			"tokens":     tokens,
+			"updated_at": now,
+		}).Error
+	})
+	return result, err
+}
+
+func (s *postgresStore) prune(ctx context.Context) {
+	s.db.WithContext(ctx).
+		Where("updated_at < ?", s.now().Add(-pruneAfter)).
+		Delete(&bucketRow{})
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Policy is a token bucket holding up to Burst tokens that refills at
+// Requests per Per. A zero Burst means Requests.
+type Policy struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+func (p Policy) capacity() float64 {
+	if p.Burst > 0 {
+		return float64(p.Burst)
+	}
+	return float64(p.Requests)
+}
+
+// interval is the time it takes to refill one token.
+func (p Policy) interval() time.Duration {
+	return p.Per / time.Duration(p.Requests)
+}
+
+// Result describes the bucket after a request has been counted.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the bucket is full again.
+	ResetAfter time.Duration
+	// RetryAfter is the time until the next request would be allowed. It is
+	// zero for allowed requests.
+	RetryAfter time.Duration
+}
+
+//go:generate mockgen -source=ratelimit.go -destination=ratelimit_mock.go -package=ratelimit
+
+// Store keeps one token bucket per key.
+type Store interface {
+	// Take removes a token from the bucket for key, creating a full bucket
+	// if there is none, and reports whether the request is allowed.
+	Take(ctx context.Context, key string, policy Policy) (*Result, error)
+}
+
+// take refills a bucket that held tokens at last and, if at least one token
+// is available at now, removes it. It returns the new token count.
+func take(tokens float64, last, now time.Time, policy Policy) (float64, *Result) {
+	capacity := policy.capacity()
+	interval := policy.interval()
+	if elapsed := now.Sub(last); elapsed > 0 {
+		tokens = math.Min(capacity, tokens+float64(elapsed)/float64(interval))
+	}
+
+	result := &Result{Limit: int(capacity)}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(interval))
+	}
+	result.Remaining = int(tokens)
+	result.ResetAfter = time.Duration((capacity - tokens) * float64(interval))
+	return tokens, result
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+var policy = Policy{Requests: 2, Per: time.Second, Burst: 3}
+
+func TestTake(t *testing.T) {
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		tokens     float64
+		elapsed    time.Duration
+		wantTokens float64
+		want       Result
+	}{
+		{
+			name:       "full bucket",
+			tokens:     3,
+			wantTokens: 2,
+			want: Result{Allowed: true, Limit: 3, Remaining: 2,
+				ResetAfter: 500 * time.Millisecond},
+		},
+		{
+			name:       "empty bucket",
+			tokens:     0.5,
+			wantTokens: 0.5,
+			want: Result{Limit: 3, Remaining: 0,
+				ResetAfter: 1250 * time.Millisecond,
+				RetryAfter: 250 * time.Millisecond},
+		},
+		{
+			name:       "refilled since last request",
+			tokens:     0,
+			elapsed:    time.Second,
+			wantTokens: 1,
+			want: Result{Allowed: true, Limit: 3, Remaining: 1,
+				ResetAfter: time.Second},
+		},
+		{
+			name:       "refill stops at the burst",
+			tokens:     0,
+			elapsed:    time.Hour,
+			wantTokens: 2,
+			want: Result{Allowed: true, Limit: 3, Remaining: 2,
+				ResetAfter: 500 * time.Millisecond},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokens, got := take(test.tokens, start, start.Add(test.elapsed), policy)
+
+			assert.InDelta(t, test.wantTokens, tokens, 1e-9)
+			assert.Equal(t, test.want, *got)
+		})
+	}
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	store := &memoryStore{buckets: map[string]*bucket{},
+		now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		got, err := store.Take(ctx, "a", policy)
+		require.NoError(t, err)
+		assert.True(t, got.Allowed)
+	}
+	got, err := store.Take(ctx, "a", policy)
+	require.NoError(t, err)
+	assert.False(t, got.Allowed)
+	assert.Equal(t, 500*time.Millisecond, got.RetryAfter)
+
+	other, err := store.Take(ctx, "b", policy)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "keys have separate buckets")
+
+	now = now.Add(500 * time.Millisecond)
+	got, err = store.Take(ctx, "a", policy)
+	require.NoError(t, err)
+	assert.True(t, got.Allowed)
+
+	now = now.Add(time.Hour)
+	store.sweep(now)
+	assert.Empty(t, store.buckets)
+}
+
+func TestRepository_PostgresStore(t *testing.T) {
+	db := database.SetupTestDB(t)
+	store := NewPostgresStore(db)
+	ctx := context.Background()
+	slow := Policy{Requests: 1, Per: time.Hour, Burst: 3}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := store.Take(ctx, "posts:write:ip:10.0.0.1", slow)
+			assert.NoError(t, err)
+			if err == nil && got.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 3, allowed, "only the burst is allowed across connections")
+}
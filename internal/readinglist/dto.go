@@ -0,0 +1,78 @@
+package readinglist
+
+import (
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/post"
+	"time"
+)
+
+// expandPosts is the name of the posts of a list when loading it.
+const expandPosts = "posts"
+
+// DefaultListAlias names the default list of the caller in place of its ID.
+const DefaultListAlias = "saved"
+
+// maxNameLength is the longest name a list may have, in characters.
+const maxNameLength = 100
+
+type CreateRequest struct {
+	Name string `json:"name" binding:"required" example:"Weekend reads"`
+}
+
+// UpdateRequest renames a list or changes whether it is shared. Sharing a
+// list that is shared already keeps its link; unsharing and sharing it
+// again replaces the link.
+type UpdateRequest struct {
+	Name   *string `json:"name" example:"Weekend reads"`
+	Shared *bool   `json:"shared"`
+}
+
+// ReorderRequest lists every post of a list in the new order.
+type ReorderRequest struct {
+	PostIDs []uuid.UUID `json:"post_ids" binding:"required" swaggertype:"array,string"`
+}
+
+type Response struct {
+	ListID  uuid.UUID `json:"list_id" swaggertype:"string"`
+	OwnerID uuid.UUID `json:"owner_id" swaggertype:"string"`
+	Name    string    `json:"name" example:"Saved"`
+	Default bool      `json:"default"`
+	Shared  bool      `json:"shared"`
+	// ShareToken reads the list at GET /shared/reading-lists/{token}
+	// without an API key. It is left out while the list is private.
+	ShareToken string    `json:"share_token,omitempty" example:"q8NwYtq1mT0c3Jr6xXbK2w"`
+	PostCount  int64     `json:"post_count" example:"3"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// DetailResponse is a list together with its posts in list order.
+type DetailResponse struct {
+	Response
+	Posts []*post.Response `json:"posts"`
+}
+
+// SharedResponse is a list as anyone with its link sees it. It leaves out
+// who owns the list and the email addresses of the authors.
+type SharedResponse struct {
+	Name      string                `json:"name" example:"Weekend reads"`
+	PostCount int64                 `json:"post_count" example:"3"`
+	UpdatedAt time.Time             `json:"updated_at"`
+	Posts     []*SharedPostResponse `json:"posts"`
+}
+
+// SharedPostResponse is a post in a shared list, without its content.
+type SharedPostResponse struct {
+	PostID    uuid.UUID `json:"post_id" swaggertype:"string"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Reactions counts the reactions to the post by kind.
+	Reactions map[string]int64      `json:"reactions" example:"like:3,love:1"`
+	Author    *SharedAuthorResponse `json:"author,omitempty"`
+}
+
+type SharedAuthorResponse struct {
+	UserID   uuid.UUID `json:"user_id" swaggertype:"string"`
+	Username string    `json:"username" example:"mike"`
+}
@@ -0,0 +1,370 @@
+package readinglist
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/post"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"net/http"
+)
+
+type Handler struct {
+	Service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{Service: service}
+}
+
+func handleError(c *gin.Context, err error) {
+	status := apperrors.StatusCode(err)
+	if status == http.StatusInternalServerError {
+		logging.FromContext(c.Request.Context()).Error("request failed",
+			"error", err)
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func buildListResponse(l *model.ReadingList) *Response {
+	resp := &Response{
+		ListID:    l.ID,
+		OwnerID:   l.UserID,
+		Name:      l.Name,
+		Default:   l.IsDefault,
+		Shared:    l.ShareToken != nil,
+		PostCount: l.PostCount,
+		CreatedAt: l.CreatedAt,
+		UpdatedAt: l.UpdatedAt,
+	}
+	if l.ShareToken != nil {
+		resp.ShareToken = *l.ShareToken
+	}
+	return resp
+}
+
+func buildDetailResponse(l *model.ReadingList) *DetailResponse {
+	resp := &DetailResponse{Response: *buildListResponse(l),
+		Posts: make([]*post.Response, 0, len(l.Items))}
+	for _, item := range l.Items {
+		if item.Post != nil {
+			resp.Posts = append(resp.Posts, post.NewSummaryResponse(item.Post))
+		}
+	}
+	resp.PostCount = int64(len(resp.Posts))
+	return resp
+}
+
+func buildSharedResponse(l *model.ReadingList) *SharedResponse {
+	resp := &SharedResponse{Name: l.Name, UpdatedAt: l.UpdatedAt,
+		Posts: make([]*SharedPostResponse, 0, len(l.Items))}
+	for _, item := range l.Items {
+		if item.Post == nil {
+			continue
+		}
+		p := post.NewSummaryResponse(item.Post)
+		shared := &SharedPostResponse{
+			PostID:    p.PostID,
+			Title:     p.Title,
+			CreatedAt: p.CreatedAt,
+			UpdatedAt: p.UpdatedAt,
+			Reactions: p.Reactions,
+		}
+		if p.Author != nil {
+			shared.Author = &SharedAuthorResponse{UserID: p.Author.UserID,
+				Username: p.Author.Username}
+		}
+		resp.Posts = append(resp.Posts, shared)
+	}
+	resp.PostCount = int64(len(resp.Posts))
+	return resp
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("", h.getLists)
+	r.POST("", h.createList)
+	r.GET("/:id", h.getList)
+	r.PATCH("/:id", h.updateList)
+	r.DELETE("/:id", h.deleteList)
+	r.PUT("/:id/posts", h.reorderPosts)
+	r.PUT("/:id/posts/:post_id", h.addPost)
+	r.DELETE("/:id/posts/:post_id", h.removePost)
+}
+
+// RegisterSharedRoutes adds the route that reads shared lists. It belongs
+// in a group that needs no API key, since the link is the permission.
+func (h *Handler) RegisterSharedRoutes(r *gin.RouterGroup) {
+	r.GET("/reading-lists/:token", h.getSharedList)
+}
+
+// list returns the user the request acts as and the ID of the list in the
+// path, resolving the default list alias. It writes the error response and
+// returns false when either is missing.
+func (h *Handler) list(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	ctx := c.Request.Context()
+	userID, err := auth.RequireUser(ctx)
+	if err != nil {
+		handleError(c, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	if c.Param("id") == DefaultListAlias {
+		list, err := h.Service.GetDefaultList(ctx, userID)
+		if err != nil {
+			handleError(c, err)
+			return uuid.Nil, uuid.Nil, false
+		}
+		return userID, list.ID, true
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleError(c, apperrors.NewInvalidInputError(
+			"ID must be a uuid or "+DefaultListAlias))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+// @Summary Get the reading lists of the caller
+// @Description Get the reading lists of the user the API key is linked to,
+// @Description the default list first. The default list is created on first
+// @Description use.
+// @Tags reading-lists
+// @Produce json
+// @Success 200 {array} Response
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Router /reading-lists [get]
+// @Security ApiKeyAuth
+func (h *Handler) getLists(c *gin.Context) {
+	userID, err := auth.RequireUser(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	lists, err := h.Service.GetLists(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	resp := make([]*Response, len(lists))
+	for i, l := range lists {
+		resp[i] = buildListResponse(l)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Create a reading list
+// @Description Creates a reading list for the user the API key is linked to
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param list body readinglist.CreateRequest true "List data"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 201 {object} Response
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Router /reading-lists [post]
+// @Security ApiKeyAuth
+func (h *Handler) createList(c *gin.Context) {
+	userID, err := auth.RequireUser(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewInvalidInputError("invalid request body"))
+		return
+	}
+	list, err := h.Service.CreateList(c.Request.Context(), userID, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, buildListResponse(list))
+}
+
+// @Summary Get a reading list
+// @Description Get a reading list of the caller with its posts in list
+// @Description order. The ID "saved" names the default list.
+// @Tags reading-lists
+// @Produce json
+// @Param id path string true "List ID or saved"
+// @Success 200 {object} DetailResponse
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /reading-lists/{id} [get]
+// @Security ApiKeyAuth
+func (h *Handler) getList(c *gin.Context) {
+	userID, id, ok := h.list(c)
+	if !ok {
+		return
+	}
+	list, err := h.Service.GetList(c.Request.Context(), userID, id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, buildDetailResponse(list))
+}
+
+// @Summary Update a reading list
+// @Description Renames a reading list or shares it. A shared list can be
+// @Description read by anyone with its share token; unsharing it revokes
+// @Description the token.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID or saved"
+// @Param list body readinglist.UpdateRequest true "List update data"
+// @Success 200 {object} Response
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /reading-lists/{id} [patch]
+// @Security ApiKeyAuth
+func (h *Handler) updateList(c *gin.Context) {
+	userID, id, ok := h.list(c)
+	if !ok {
+		return
+	}
+	var req UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewInvalidInputError("invalid request body"))
+		return
+	}
+	list, err := h.Service.UpdateList(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, buildListResponse(list))
+}
+
+// @Summary Delete a reading list
+// @Description Deletes a reading list of the caller. The posts stay, and
+// @Description the default list cannot be deleted.
+// @Tags reading-lists
+// @Param id path string true "List ID" Format(uuid)
+// @Success 204
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /reading-lists/{id} [delete]
+// @Security ApiKeyAuth
+func (h *Handler) deleteList(c *gin.Context) {
+	userID, id, ok := h.list(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.DeleteList(c.Request.Context(), userID, id); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Add a post to a reading list
+// @Description Appends a post to a reading list of the caller. Adding a
+// @Description post twice is not an error. PUT /reading-lists/saved/posts/{post_id}
+// @Description bookmarks a post.
+// @Tags reading-lists
+// @Param id path string true "List ID or saved"
+// @Param post_id path string true "Post ID" Format(uuid)
+// @Success 204
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /reading-lists/{id}/posts/{post_id} [put]
+// @Security ApiKeyAuth
+func (h *Handler) addPost(c *gin.Context) {
+	h.changePost(c, h.Service.AddPost)
+}
+
+// @Summary Remove a post from a reading list
+// @Description Takes a post out of a reading list of the caller
+// @Tags reading-lists
+// @Param id path string true "List ID or saved"
+// @Param post_id path string true "Post ID" Format(uuid)
+// @Success 204
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /reading-lists/{id}/posts/{post_id} [delete]
+// @Security ApiKeyAuth
+func (h *Handler) removePost(c *gin.Context) {
+	h.changePost(c, h.Service.RemovePost)
+}
+
+func (h *Handler) changePost(c *gin.Context, change func(ctx context.Context,
+	userID uuid.UUID, id uuid.UUID, postID uuid.UUID) error) {
+	postID, err := uuid.Parse(c.Param("post_id"))
+	if err != nil {
+		handleError(c, apperrors.NewInvalidInputError("post ID must be a uuid"))
+		return
+	}
+	userID, id, ok := h.list(c)
+	if !ok {
+		return
+	}
+	if err := change(c.Request.Context(), userID, id, postID); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Reorder the posts of a reading list
+// @Description Puts the posts of a reading list in the given order, which
+// @Description must name every post of the list once.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID or saved"
+// @Param order body readinglist.ReorderRequest true "Post IDs in the new order"
+// @Success 200 {object} DetailResponse
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /reading-lists/{id}/posts [put]
+// @Security ApiKeyAuth
+func (h *Handler) reorderPosts(c *gin.Context) {
+	userID, id, ok := h.list(c)
+	if !ok {
+		return
+	}
+	var req ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewInvalidInputError("invalid request body"))
+		return
+	}
+	list, err := h.Service.ReorderPosts(c.Request.Context(), userID, id,
+		req.PostIDs)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, buildDetailResponse(list))
+}
+
+// @Summary Get a shared reading list
+// @Description Get a reading list its owner shared, with its posts in list
+// @Description order. The share token is the only permission needed, so
+// @Description the list is shown without its owner and author emails.
+// @Tags reading-lists
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} SharedResponse
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /shared/reading-lists/{token} [get]
+func (h *Handler) getSharedList(c *gin.Context) {
+	list, err := h.Service.GetSharedList(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, buildSharedResponse(list))
+}
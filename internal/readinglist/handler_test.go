@@ -0,0 +1,261 @@
+package readinglist
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/shared/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupTestRouter(t *testing.T, actor *uuid.UUID) (*gin.Engine, *MockService) {
+	ctrl := gomock.NewController(t)
+	mockService := NewMockService(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if actor != nil {
+		router.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(
+				auth.WithUser(c.Request.Context(), *actor))
+		})
+	}
+	handler := NewHandler(mockService)
+	handler.RegisterRoutes(router.Group("/reading-lists"))
+	handler.RegisterSharedRoutes(router.Group("/shared"))
+	return router, mockService
+}
+
+func TestHandler_GetList(t *testing.T) {
+	actor := uuid.New()
+	listID := uuid.New()
+	postID := uuid.New()
+	created := time.Date(2025, 7, 18, 15, 4, 5, 0, time.UTC)
+	list := &model.ReadingList{ID: listID, UserID: actor, Name: "Saved",
+		IsDefault: true, CreatedAt: created, UpdatedAt: created,
+		Items: []*model.ReadingListItem{{ListID: listID, PostID: postID,
+			Post: &model.Post{ID: postID, Title: "Hello", Content: "long",
+				CreatedAt: created, UpdatedAt: created}}}}
+	want := `{"list_id":"` + listID.String() + `","owner_id":"` +
+		actor.String() + `","name":"Saved","default":true,"shared":false,
+		"post_count":1,"created_at":"2025-07-18T15:04:05Z",
+		"updated_at":"2025-07-18T15:04:05Z","posts":[{"post_id":"` +
+		postID.String() + `","title":"Hello",
+		"created_at":"2025-07-18T15:04:05Z","updated_at":"2025-07-18T15:04:05Z",
+		"reactions":{"like":0,"love":0,"laugh":0,"wow":0,"sad":0,"celebrate":0}}]}`
+	tests := []struct {
+		name          string
+		actor         *uuid.UUID
+		id            string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name:  "by id",
+			actor: &actor,
+			id:    listID.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetList(gomock.Any(), actor, listID).
+					Return(list, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   want,
+		},
+		{
+			name:  "default list alias",
+			actor: &actor,
+			id:    DefaultListAlias,
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetDefaultList(gomock.Any(), actor).
+					Return(&model.ReadingList{ID: listID}, nil)
+				service.EXPECT().GetList(gomock.Any(), actor, listID).
+					Return(list, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   want,
+		},
+		{
+			name:       "key without user",
+			id:         listID.String(),
+			wantStatus: http.StatusForbidden,
+			wantBody:   `{"error":"the API key is not linked to a user"}`,
+		},
+		{
+			name:       "not an uuid",
+			actor:      &actor,
+			id:         "abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"ID must be a uuid or saved"}`,
+		},
+		{
+			name:  "not found",
+			actor: &actor,
+			id:    listID.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetList(gomock.Any(), actor, listID).
+					Return(nil, apperrors.NewNotFoundError("reading list", listID))
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody: `{"error":"reading list with ID ` + listID.String() +
+				` not found"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupTestRouter(t, test.actor)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockService)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet,
+				"/reading-lists/"+test.id, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.JSONEq(t, test.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_ChangePost(t *testing.T) {
+	actor := uuid.New()
+	listID := uuid.New()
+	postID := uuid.New()
+	tests := []struct {
+		name          string
+		method        string
+		postID        string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+	}{
+		{
+			name:   "add",
+			method: http.MethodPut,
+			postID: postID.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().AddPost(gomock.Any(), actor, listID, postID).
+					Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "remove",
+			method: http.MethodDelete,
+			postID: postID.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().RemovePost(gomock.Any(), actor, listID, postID).
+					Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "post ID not an uuid",
+			method:     http.MethodPut,
+			postID:     "abc",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "post not found",
+			method: http.MethodPut,
+			postID: postID.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().AddPost(gomock.Any(), actor, listID, postID).
+					Return(apperrors.NewNotFoundError("post", postID))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupTestRouter(t, &actor)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockService)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(test.method, "/reading-lists/"+
+				listID.String()+"/posts/"+test.postID, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_ReorderPosts(t *testing.T) {
+	actor := uuid.New()
+	listID := uuid.New()
+	a, b := uuid.New(), uuid.New()
+	router, mockService := setupTestRouter(t, &actor)
+	mockService.EXPECT().ReorderPosts(gomock.Any(), actor, listID,
+		[]uuid.UUID{b, a}).
+		Return(&model.ReadingList{ID: listID, UserID: actor}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut,
+		"/reading-lists/"+listID.String()+"/posts",
+		strings.NewReader(`{"post_ids":["`+b.String()+`","`+a.String()+`"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"posts":[]`)
+}
+
+func TestHandler_GetSharedList(t *testing.T) {
+	token := "q8NwYtq1mT0c3Jr6xXbK2w"
+	ownerID := uuid.New()
+	router, mockService := setupTestRouter(t, nil)
+	mockService.EXPECT().GetSharedList(gomock.Any(), token).
+		Return(&model.ReadingList{ID: uuid.New(), UserID: ownerID,
+			Name: "Picks", ShareToken: &token,
+			Items: []*model.ReadingListItem{{Post: testdata.Post1}}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet,
+		"/shared/reading-lists/"+token, nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"name":"Picks"`)
+	assert.Contains(t, body, `"username":"`+testdata.Post1.User.Username+`"`)
+	// The route needs no API key, so it must not reveal who owns the list
+	// or how to reach the authors.
+	var decoded any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &decoded))
+	keys := jsonKeys(decoded)
+	assert.NotContains(t, keys, "email")
+	assert.NotContains(t, keys, "owner_id")
+	assert.NotContains(t, body, testdata.Post1.User.Email)
+	assert.NotContains(t, body, ownerID.String())
+}
+
+// jsonKeys returns every object key in a decoded JSON value, at any depth.
+func jsonKeys(v any) []string {
+	var keys []string
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			keys = append(keys, k)
+			keys = append(keys, jsonKeys(child)...)
+		}
+	case []any:
+		for _, child := range v {
+			keys = append(keys, jsonKeys(child)...)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,153 @@
+package readinglist
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+//go:generate mockgen -source=repository.go -destination=repository_mock.go -package=readinglist
+
+type Repository interface {
+	// FindByUser returns the lists of a user with their post counts, the
+	// default list first.
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]*model.ReadingList, error)
+	FindByID(ctx context.Context, id uuid.UUID, opts query.Options) (*model.ReadingList, error)
+	FindByShareToken(ctx context.Context, token string, opts query.Options) (*model.ReadingList, error)
+	// FindOrCreateDefault returns the default list of a user, creating it
+	// on first use.
+	FindOrCreateDefault(ctx context.Context, userID uuid.UUID) (*model.ReadingList, error)
+	Create(ctx context.Context, list *model.ReadingList) (*model.ReadingList, error)
+	Update(ctx context.Context, list *model.ReadingList) (*model.ReadingList, error)
+	Delete(ctx context.Context, list *model.ReadingList) error
+	// AddItem appends a post to a list unless it is in the list already.
+	AddItem(ctx context.Context, listID uuid.UUID, postID uuid.UUID) error
+	RemoveItem(ctx context.Context, listID uuid.UUID, postID uuid.UUID) error
+	// SetPositions orders the items of a list as postIDs.
+	SetPositions(ctx context.Context, listID uuid.UUID, postIDs []uuid.UUID) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// preload loads the posts of a list, in list order, when opts expands them.
+func preload(db *gorm.DB, opts query.Options) *gorm.DB {
+	if opts.Expands(expandPosts) {
+		db = db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position").Order("created_at")
+		}).Preload("Items.Post.User").Preload("Items.Post.ReactionCounts")
+	}
+	return db
+}
+
+func (r *repository) FindByUser(ctx context.Context,
+	userID uuid.UUID) ([]*model.ReadingList, error) {
+	var lists []*model.ReadingList
+	err := r.db.WithContext(ctx).Model(&model.ReadingList{}).
+		Select("reading_lists.*, (SELECT COUNT(*) FROM reading_list_items i "+
+			"WHERE i.list_id = reading_lists.id) AS post_count").
+		Where("user_id = ?", userID).
+		Order("is_default DESC").Order("created_at").Order("id").
+		Find(&lists).Error
+	return lists, err
+}
+
+func (r *repository) FindByID(ctx context.Context, id uuid.UUID,
+	opts query.Options) (*model.ReadingList, error) {
+	var list model.ReadingList
+	err := preload(r.db.WithContext(ctx), opts).First(&list, "id = ?", id).Error
+	return &list, err
+}
+
+func (r *repository) FindByShareToken(ctx context.Context, token string,
+	opts query.Options) (*model.ReadingList, error) {
+	var list model.ReadingList
+	err := preload(r.db.WithContext(ctx), opts).
+		First(&list, "share_token = ?", token).Error
+	return &list, err
+}
+
+func (r *repository) FindOrCreateDefault(ctx context.Context,
+	userID uuid.UUID) (*model.ReadingList, error) {
+	var list model.ReadingList
+	find := func() error {
+		return r.db.WithContext(ctx).
+			First(&list, "user_id = ? AND is_default", userID).Error
+	}
+	err := find()
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return &list, err
+	}
+
+	// Two first requests may race, the loser keeps the winner's list.
+	created := model.NewReadingList(userID, model.DefaultReadingListName)
+	created.IsDefault = true
+	err = r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "is_default"}}},
+		DoNothing:   true,
+	}).Create(created).Error
+	if err != nil {
+		return nil, database.TranslateError(err)
+	}
+	return &list, find()
+}
+
+func (r *repository) Create(ctx context.Context,
+	list *model.ReadingList) (*model.ReadingList, error) {
+	err := r.db.WithContext(ctx).Omit("Items").Create(list).Error
+	return list, database.TranslateError(err)
+}
+
+func (r *repository) Update(ctx context.Context,
+	list *model.ReadingList) (*model.ReadingList, error) {
+	err := r.db.WithContext(ctx).Omit("Items").Save(list).Error
+	return list, database.TranslateError(err)
+}
+
+func (r *repository) Delete(ctx context.Context, list *model.ReadingList) error {
+	return r.db.WithContext(ctx).Delete(list).Error
+}
+
+func (r *repository) AddItem(ctx context.Context, listID uuid.UUID,
+	postID uuid.UUID) error {
+	err := r.db.WithContext(ctx).Exec(`
+		INSERT INTO reading_list_items (list_id, post_id, position, created_at)
+		SELECT ?, ?, COALESCE(MAX(position), 0) + 1, ?
+		FROM reading_list_items
+		WHERE list_id = ?
+		ON CONFLICT DO NOTHING`, listID, postID, time.Now(), listID).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) RemoveItem(ctx context.Context, listID uuid.UUID,
+	postID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("list_id = ? AND post_id = ?", listID, postID).
+		Delete(&model.ReadingListItem{}).Error
+}
+
+func (r *repository) SetPositions(ctx context.Context, listID uuid.UUID,
+	postIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, postID := range postIDs {
+			if err := tx.Model(&model.ReadingListItem{}).
+				Where("list_id = ? AND post_id = ?", listID, postID).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
@@ -0,0 +1,184 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package readinglist is a generated GoMock package.
+package readinglist
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	query "github.com/pandahawk/blog-api/internal/query"
+	model "github.com/pandahawk/blog-api/internal/shared/model"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddItem mocks base method.
+func (m *MockRepository) AddItem(ctx context.Context, listID, postID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItem", ctx, listID, postID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddItem indicates an expected call of AddItem.
+func (mr *MockRepositoryMockRecorder) AddItem(ctx, listID, postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItem", reflect.TypeOf((*MockRepository)(nil).AddItem), ctx, listID, postID)
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(ctx context.Context, list *model.ReadingList) (*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, list)
+	ret0, _ := ret[0].(*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(ctx, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, list)
+}
+
+// Delete mocks base method.
+func (m *MockRepository) Delete(ctx context.Context, list *model.ReadingList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(ctx, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, list)
+}
+
+// FindByID mocks base method.
+func (m *MockRepository) FindByID(ctx context.Context, id uuid.UUID, opts query.Options) (*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id, opts)
+	ret0, _ := ret[0].(*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockRepositoryMockRecorder) FindByID(ctx, id, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockRepository)(nil).FindByID), ctx, id, opts)
+}
+
+// FindByShareToken mocks base method.
+func (m *MockRepository) FindByShareToken(ctx context.Context, token string, opts query.Options) (*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByShareToken", ctx, token, opts)
+	ret0, _ := ret[0].(*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByShareToken indicates an expected call of FindByShareToken.
+func (mr *MockRepositoryMockRecorder) FindByShareToken(ctx, token, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByShareToken", reflect.TypeOf((*MockRepository)(nil).FindByShareToken), ctx, token, opts)
+}
+
+// FindByUser mocks base method.
+func (m *MockRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userID)
+	ret0, _ := ret[0].([]*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockRepositoryMockRecorder) FindByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockRepository)(nil).FindByUser), ctx, userID)
+}
+
+// FindOrCreateDefault mocks base method.
+func (m *MockRepository) FindOrCreateDefault(ctx context.Context, userID uuid.UUID) (*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrCreateDefault", ctx, userID)
+	ret0, _ := ret[0].(*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrCreateDefault indicates an expected call of FindOrCreateDefault.
+func (mr *MockRepositoryMockRecorder) FindOrCreateDefault(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreateDefault", reflect.TypeOf((*MockRepository)(nil).FindOrCreateDefault), ctx, userID)
+}
+
+// RemoveItem mocks base method.
+func (m *MockRepository) RemoveItem(ctx context.Context, listID, postID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveItem", ctx, listID, postID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveItem indicates an expected call of RemoveItem.
+func (mr *MockRepositoryMockRecorder) RemoveItem(ctx, listID, postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveItem", reflect.TypeOf((*MockRepository)(nil).RemoveItem), ctx, listID, postID)
+}
+
+// SetPositions mocks base method.
+func (m *MockRepository) SetPositions(ctx context.Context, listID uuid.UUID, postIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPositions", ctx, listID, postIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPositions indicates an expected call of SetPositions.
+func (mr *MockRepositoryMockRecorder) SetPositions(ctx, listID, postIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPositions", reflect.TypeOf((*MockRepository)(nil).SetPositions), ctx, listID, postIDs)
+}
+
+// Update mocks base method.
+func (m *MockRepository) Update(ctx context.Context, list *model.ReadingList) (*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, list)
+	ret0, _ := ret[0].(*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRepositoryMockRecorder) Update(ctx, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, list)
+}
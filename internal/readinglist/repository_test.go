@@ -0,0 +1,79 @@
+package readinglist
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/shared/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRepository_FindOrCreateDefault(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	first, err := repo.FindOrCreateDefault(ctx, testdata.Bob.ID)
+	require.NoError(t, err)
+	second, err := repo.FindOrCreateDefault(ctx, testdata.Bob.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, first.ID, second.ID)
+	assert.True(t, second.IsDefault)
+	assert.Equal(t, model.DefaultReadingListName, second.Name)
+}
+
+func TestRepository_Items(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	list, err := repo.Create(ctx,
+		model.NewReadingList(testdata.Bob.ID, "Later"))
+	require.NoError(t, err)
+
+	for _, p := range []*model.Post{testdata.Post1, testdata.Post2,
+		testdata.Post3, testdata.Post1} {
+		require.NoError(t, repo.AddItem(ctx, list.ID, p.ID))
+	}
+	err = repo.AddItem(ctx, list.ID, uuid.New())
+	var re *apperrors.ReferenceNotFoundError
+	assert.ErrorAs(t, err, &re)
+
+	require.NoError(t, repo.RemoveItem(ctx, list.ID, testdata.Post2.ID))
+	require.NoError(t, repo.SetPositions(ctx, list.ID,
+		[]uuid.UUID{testdata.Post3.ID, testdata.Post1.ID}))
+
+	got, err := repo.FindByID(ctx, list.ID,
+		query.Options{Expand: []string{expandPosts}})
+	require.NoError(t, err)
+	require.Len(t, got.Items, 2)
+	assert.Equal(t, testdata.Post3.ID, got.Items[0].Post.ID)
+	assert.Equal(t, testdata.Post1.ID, got.Items[1].Post.ID)
+	assert.NotNil(t, got.Items[0].Post.User)
+
+	lists, err := repo.FindByUser(ctx, testdata.Bob.ID)
+	require.NoError(t, err)
+	require.Len(t, lists, 1)
+	assert.Equal(t, int64(2), lists[0].PostCount)
+}
+
+func TestRepository_FindByShareToken(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	token := "q8NwYtq1mT0c3Jr6xXbK2w"
+	list := model.NewReadingList(testdata.Dave.ID, "Picks")
+	list.ShareToken = &token
+	_, err := repo.Create(ctx, list)
+	require.NoError(t, err)
+
+	got, err := repo.FindByShareToken(ctx, token, query.Options{})
+
+	require.NoError(t, err)
+	assert.Equal(t, list.ID, got.ID)
+}
@@ -0,0 +1,258 @@
+package readinglist
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+	"strings"
+	"unicode/utf8"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock.go -package=readinglist
+
+// Service manages the reading lists of the users. Every method but
+// GetSharedList acts for userID and treats lists of other users as missing.
+type Service interface {
+	// GetLists returns the lists of a user, creating the default list on
+	// first use.
+	GetLists(ctx context.Context, userID uuid.UUID) ([]*model.ReadingList, error)
+	// GetList returns a list with its posts.
+	GetList(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*model.ReadingList, error)
+	// GetDefaultList returns the default list of a user without its posts,
+	// creating it on first use.
+	GetDefaultList(ctx context.Context, userID uuid.UUID) (*model.ReadingList, error)
+	// GetSharedList returns the list shared under token with its posts.
+	GetSharedList(ctx context.Context, token string) (*model.ReadingList, error)
+	CreateList(ctx context.Context, userID uuid.UUID, req *CreateRequest) (*model.ReadingList, error)
+	UpdateList(ctx context.Context, userID uuid.UUID, id uuid.UUID, req *UpdateRequest) (*model.ReadingList, error)
+	// DeleteList deletes a list other than the default one.
+	DeleteList(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	// AddPost appends a post to a list. Adding it twice is not an error.
+	AddPost(ctx context.Context, userID uuid.UUID, id uuid.UUID, postID uuid.UUID) error
+	// RemovePost takes a post out of a list. Removing one that is not in
+	// the list is not an error.
+	RemovePost(ctx context.Context, userID uuid.UUID, id uuid.UUID, postID uuid.UUID) error
+	// ReorderPosts puts the posts of a list in the order of postIDs, which
+	// must name each of them once, and returns the reordered list.
+	ReorderPosts(ctx context.Context, userID uuid.UUID, id uuid.UUID, postIDs []uuid.UUID) (*model.ReadingList, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+var withPosts = query.Options{Expand: []string{expandPosts}}
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", apperrors.NewInvalidInputError("name must not be blank")
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return "", apperrors.NewInvalidInputError(fmt.Sprintf(
+			"name must not be longer than %d characters", maxNameLength))
+	}
+	return name, nil
+}
+
+// newShareToken returns a random token for the share link of a list.
+func newShareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// findOwned returns the list with id if userID owns it and a NotFoundError
+// otherwise, so other users cannot tell their lists exist.
+func (s *service) findOwned(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID, opts query.Options) (*model.ReadingList, error) {
+	list, err := s.repo.FindByID(ctx, id, opts)
+	if errors.Is(err, gorm.ErrRecordNotFound) ||
+		(err == nil && list.UserID != userID) {
+		return nil, apperrors.NewNotFoundError("reading list", id)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("find reading list", "list_id", id,
+			"error", err)
+		return nil, errors.New("db error")
+	}
+	return list, nil
+}
+
+func (s *service) GetLists(ctx context.Context,
+	userID uuid.UUID) ([]*model.ReadingList, error) {
+	if _, err := s.GetDefaultList(ctx, userID); err != nil {
+		return nil, err
+	}
+	lists, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).Error("find reading lists",
+			"user_id", userID, "error", err)
+		return nil, errors.New("db error")
+	}
+	return lists, nil
+}
+
+func (s *service) GetList(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID) (*model.ReadingList, error) {
+	return s.findOwned(ctx, userID, id, withPosts)
+}
+
+func (s *service) GetDefaultList(ctx context.Context,
+	userID uuid.UUID) (*model.ReadingList, error) {
+	list, err := s.repo.FindOrCreateDefault(ctx, userID)
+	var re *apperrors.ReferenceNotFoundError
+	if errors.As(err, &re) {
+		// The user of the API key was deleted meanwhile.
+		return nil, apperrors.NewNotFoundError("user", userID)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("find default reading list",
+			"user_id", userID, "error", err)
+		return nil, errors.New("db error")
+	}
+	return list, nil
+}
+
+func (s *service) GetSharedList(ctx context.Context,
+	token string) (*model.ReadingList, error) {
+	list, err := s.repo.FindByShareToken(ctx, token, withPosts)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.NewNotFoundByNameError("reading list", token)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("find shared reading list",
+			"error", err)
+		return nil, errors.New("db error")
+	}
+	return list, nil
+}
+
+func (s *service) CreateList(ctx context.Context, userID uuid.UUID,
+	req *CreateRequest) (*model.ReadingList, error) {
+	name, err := validateName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.repo.Create(ctx, model.NewReadingList(userID, name))
+	var re *apperrors.ReferenceNotFoundError
+	if errors.As(err, &re) {
+		return nil, apperrors.NewNotFoundError("user", userID)
+	}
+	return list, err
+}
+
+func (s *service) UpdateList(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID, req *UpdateRequest) (*model.ReadingList, error) {
+	list, err := s.findOwned(ctx, userID, id, query.Options{})
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		if list.Name, err = validateName(*req.Name); err != nil {
+			return nil, err
+		}
+	}
+	if req.Shared != nil {
+		switch {
+		case !*req.Shared:
+			list.ShareToken = nil
+		case list.ShareToken == nil:
+			token, err := newShareToken()
+			if err != nil {
+				return nil, err
+			}
+			list.ShareToken = &token
+		}
+	}
+	return s.repo.Update(ctx, list)
+}
+
+func (s *service) DeleteList(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID) error {
+	list, err := s.findOwned(ctx, userID, id, query.Options{})
+	if err != nil {
+		return err
+	}
+	if list.IsDefault {
+		return apperrors.NewInvalidInputError(
+			"the default list cannot be deleted")
+	}
+	if err := s.repo.Delete(ctx, list); err != nil {
+		logging.FromContext(ctx).Error("delete reading list",
+			"list_id", id, "error", err)
+		return errors.New("db error")
+	}
+	return nil
+}
+
+func (s *service) AddPost(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID, postID uuid.UUID) error {
+	if _, err := s.findOwned(ctx, userID, id, query.Options{}); err != nil {
+		return err
+	}
+	err := s.repo.AddItem(ctx, id, postID)
+	var re *apperrors.ReferenceNotFoundError
+	if errors.As(err, &re) {
+		return apperrors.NewNotFoundError("post", postID)
+	}
+	return err
+}
+
+func (s *service) RemovePost(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID, postID uuid.UUID) error {
+	if _, err := s.findOwned(ctx, userID, id, query.Options{}); err != nil {
+		return err
+	}
+	if err := s.repo.RemoveItem(ctx, id, postID); err != nil {
+		logging.FromContext(ctx).Error("remove reading list item",
+			"list_id", id, "post_id", postID, "error", err)
+		return errors.New("db error")
+	}
+	return nil
+}
+
+func (s *service) ReorderPosts(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID, postIDs []uuid.UUID) (*model.ReadingList, error) {
+	list, err := s.findOwned(ctx, userID, id, withPosts)
+	if err != nil {
+		return nil, err
+	}
+	inList := make(map[uuid.UUID]bool, len(list.Items))
+	for _, item := range list.Items {
+		inList[item.PostID] = true
+	}
+	seen := make(map[uuid.UUID]bool, len(postIDs))
+	for _, postID := range postIDs {
+		if !inList[postID] || seen[postID] {
+			return nil, apperrors.NewInvalidInputError(
+				"post_ids must name every post of the list once")
+		}
+		seen[postID] = true
+	}
+	if len(seen) != len(inList) {
+		return nil, apperrors.NewInvalidInputError(
+			"post_ids must name every post of the list once")
+	}
+
+	if err := s.repo.SetPositions(ctx, id, postIDs); err != nil {
+		logging.FromContext(ctx).Error("reorder reading list",
+			"list_id", id, "error", err)
+		return nil, errors.New("db error")
+	}
+	return s.findOwned(ctx, userID, id, withPosts)
+}
+
+func NewService(r Repository) Service {
+	return &service{repo: r}
+}
@@ -0,0 +1,184 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package readinglist is a generated GoMock package.
+package readinglist
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	model "github.com/pandahawk/blog-api/internal/shared/model"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// AddPost mocks base method.
+func (m *MockService) AddPost(ctx context.Context, userID, id, postID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPost", ctx, userID, id, postID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPost indicates an expected call of AddPost.
+func (mr *MockServiceMockRecorder) AddPost(ctx, userID, id, postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPost", reflect.TypeOf((*MockService)(nil).AddPost), ctx, userID, id, postID)
+}
+
+// CreateList mocks base method.
+func (m *MockService) CreateList(ctx context.Context, userID uuid.UUID, req *CreateRequest) (*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateList", ctx, userID, req)
+	ret0, _ := ret[0].(*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateList indicates an expected call of CreateList.
+func (mr *MockServiceMockRecorder) CreateList(ctx, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateList", reflect.TypeOf((*MockService)(nil).CreateList), ctx, userID, req)
+}
+
+// DeleteList mocks base method.
+func (m *MockService) DeleteList(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteList", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteList indicates an expected call of DeleteList.
+func (mr *MockServiceMockRecorder) DeleteList(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteList", reflect.TypeOf((*MockService)(nil).DeleteList), ctx, userID, id)
+}
+
+// GetDefaultList mocks base method.
+func (m *MockService) GetDefaultList(ctx context.Context, userID uuid.UUID) (*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultList", ctx, userID)
+	ret0, _ := ret[0].(*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDefaultList indicates an expected call of GetDefaultList.
+func (mr *MockServiceMockRecorder) GetDefaultList(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultList", reflect.TypeOf((*MockService)(nil).GetDefaultList), ctx, userID)
+}
+
+// GetList mocks base method.
+func (m *MockService) GetList(ctx context.Context, userID, id uuid.UUID) (*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userID, id)
+	ret0, _ := ret[0].(*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockServiceMockRecorder) GetList(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockService)(nil).GetList), ctx, userID, id)
+}
+
+// GetLists mocks base method.
+func (m *MockService) GetLists(ctx context.Context, userID uuid.UUID) ([]*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLists", ctx, userID)
+	ret0, _ := ret[0].([]*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLists indicates an expected call of GetLists.
+func (mr *MockServiceMockRecorder) GetLists(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLists", reflect.TypeOf((*MockService)(nil).GetLists), ctx, userID)
+}
+
+// GetSharedList mocks base method.
+func (m *MockService) GetSharedList(ctx context.Context, token string) (*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedList", ctx, token)
+	ret0, _ := ret[0].(*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedList indicates an expected call of GetSharedList.
+func (mr *MockServiceMockRecorder) GetSharedList(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedList", reflect.TypeOf((*MockService)(nil).GetSharedList), ctx, token)
+}
+
+// RemovePost mocks base method.
+func (m *MockService) RemovePost(ctx context.Context, userID, id, postID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePost", ctx, userID, id, postID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemovePost indicates an expected call of RemovePost.
+func (mr *MockServiceMockRecorder) RemovePost(ctx, userID, id, postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePost", reflect.TypeOf((*MockService)(nil).RemovePost), ctx, userID, id, postID)
+}
+
+// ReorderPosts mocks base method.
+func (m *MockService) ReorderPosts(ctx context.Context, userID, id uuid.UUID, postIDs []uuid.UUID) (*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderPosts", ctx, userID, id, postIDs)
+	ret0, _ := ret[0].(*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReorderPosts indicates an expected call of ReorderPosts.
+func (mr *MockServiceMockRecorder) ReorderPosts(ctx, userID, id, postIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderPosts", reflect.TypeOf((*MockService)(nil).ReorderPosts), ctx, userID, id, postIDs)
+}
+
+// UpdateList mocks base method.
+func (m *MockService) UpdateList(ctx context.Context, userID, id uuid.UUID, req *UpdateRequest) (*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateList", ctx, userID, id, req)
+	ret0, _ := ret[0].(*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateList indicates an expected call of UpdateList.
+func (mr *MockServiceMockRecorder) UpdateList(ctx, userID, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateList", reflect.TypeOf((*MockService)(nil).UpdateList), ctx, userID, id, req)
+}
@@ -0,0 +1,333 @@
+package readinglist
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func setupMockRepoAndService(t *testing.T) (*MockRepository, Service) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockRepo := NewMockRepository(ctrl)
+	return mockRepo, NewService(mockRepo)
+}
+
+func TestService_GetLists(t *testing.T) {
+	userID := uuid.New()
+	saved := &model.ReadingList{ID: uuid.New(), UserID: userID,
+		Name: model.DefaultReadingListName, IsDefault: true}
+	mockRepo, service := setupMockRepoAndService(t)
+	gomock.InOrder(
+		mockRepo.EXPECT().FindOrCreateDefault(gomock.Any(), userID).
+			Return(saved, nil),
+		mockRepo.EXPECT().FindByUser(gomock.Any(), userID).
+			Return([]*model.ReadingList{saved}, nil),
+	)
+
+	lists, err := service.GetLists(context.Background(), userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, []*model.ReadingList{saved}, lists)
+}
+
+func TestService_GetList(t *testing.T) {
+	userID := uuid.New()
+	id := uuid.New()
+	tests := []struct {
+		name       string
+		expectMock func(mockRepo *MockRepository)
+		wantErr    string
+	}{
+		{
+			name: "success",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindByID(gomock.Any(), id, withPosts).
+					Return(&model.ReadingList{ID: id, UserID: userID}, nil)
+			},
+		},
+		{
+			name: "not found",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindByID(gomock.Any(), id, withPosts).
+					Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: apperrors.NewNotFoundError("reading list", id).Error(),
+		},
+		{
+			name: "list of another user",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindByID(gomock.Any(), id, withPosts).
+					Return(&model.ReadingList{ID: id, UserID: uuid.New()}, nil)
+			},
+			wantErr: apperrors.NewNotFoundError("reading list", id).Error(),
+		},
+		{
+			name: "db error",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindByID(gomock.Any(), id, withPosts).
+					Return(nil, errors.New("connection refused"))
+			},
+			wantErr: "db error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setupMockRepoAndService(t)
+			test.expectMock(mockRepo)
+
+			list, err := service.GetList(context.Background(), userID, id)
+
+			if test.wantErr == "" {
+				require.NoError(t, err)
+				assert.Equal(t, id, list.ID)
+			} else {
+				assert.EqualError(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_CreateList(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name       string
+		listName   string
+		expectMock func(mockRepo *MockRepository)
+		wantErr    string
+	}{
+		{
+			name:     "success",
+			listName: "  Weekend reads ",
+			expectMock: func(mockRepo *MockRepository) {
+				want := model.NewReadingList(userID, "Weekend reads")
+				mockRepo.EXPECT().Create(gomock.Any(), want).Return(want, nil)
+			},
+		},
+		{
+			name:     "blank name",
+			listName: "  ",
+			wantErr:  "name must not be blank",
+		},
+		{
+			name:     "name too long",
+			listName: strings.Repeat("ä", maxNameLength+1),
+			wantErr:  "name must not be longer than 100 characters",
+		},
+		{
+			name:     "user deleted",
+			listName: "Later",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(nil, apperrors.NewReferenceNotFoundError("user_id"))
+			},
+			wantErr: apperrors.NewNotFoundError("user", userID).Error(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setupMockRepoAndService(t)
+			if test.expectMock != nil {
+				test.expectMock(mockRepo)
+			}
+
+			_, err := service.CreateList(context.Background(), userID,
+				&CreateRequest{Name: test.listName})
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_UpdateList_Sharing(t *testing.T) {
+	userID := uuid.New()
+	id := uuid.New()
+	token := "existing"
+	yes, no := true, false
+	tests := []struct {
+		name      string
+		token     *string
+		shared    *bool
+		wantToken func(t *testing.T, got *string)
+	}{
+		{
+			name:   "share",
+			shared: &yes,
+			wantToken: func(t *testing.T, got *string) {
+				require.NotNil(t, got)
+				assert.Len(t, *got, 22)
+			},
+		},
+		{
+			name:   "share again keeps the token",
+			token:  &token,
+			shared: &yes,
+			wantToken: func(t *testing.T, got *string) {
+				assert.Equal(t, &token, got)
+			},
+		},
+		{
+			name:   "unshare",
+			token:  &token,
+			shared: &no,
+			wantToken: func(t *testing.T, got *string) {
+				assert.Nil(t, got)
+			},
+		},
+		{
+			name:  "unchanged",
+			token: &token,
+			wantToken: func(t *testing.T, got *string) {
+				assert.Equal(t, &token, got)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setupMockRepoAndService(t)
+			mockRepo.EXPECT().FindByID(gomock.Any(), id, query.Options{}).
+				Return(&model.ReadingList{ID: id, UserID: userID,
+					Name: "Later", ShareToken: test.token}, nil)
+			mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context,
+					l *model.ReadingList) (*model.ReadingList, error) {
+					return l, nil
+				})
+
+			list, err := service.UpdateList(context.Background(), userID, id,
+				&UpdateRequest{Shared: test.shared})
+
+			require.NoError(t, err)
+			assert.Equal(t, "Later", list.Name)
+			test.wantToken(t, list.ShareToken)
+		})
+	}
+}
+
+func TestService_DeleteList_Default(t *testing.T) {
+	userID := uuid.New()
+	id := uuid.New()
+	mockRepo, service := setupMockRepoAndService(t)
+	mockRepo.EXPECT().FindByID(gomock.Any(), id, query.Options{}).
+		Return(&model.ReadingList{ID: id, UserID: userID, IsDefault: true}, nil)
+
+	err := service.DeleteList(context.Background(), userID, id)
+
+	assert.EqualError(t, err, "the default list cannot be deleted")
+}
+
+func TestService_AddPost(t *testing.T) {
+	userID := uuid.New()
+	id := uuid.New()
+	postID := uuid.New()
+	tests := []struct {
+		name    string
+		addErr  error
+		wantErr string
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:    "post not found",
+			addErr:  apperrors.NewReferenceNotFoundError("post_id"),
+			wantErr: apperrors.NewNotFoundError("post", postID).Error(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setupMockRepoAndService(t)
+			mockRepo.EXPECT().FindByID(gomock.Any(), id, query.Options{}).
+				Return(&model.ReadingList{ID: id, UserID: userID}, nil)
+			mockRepo.EXPECT().AddItem(gomock.Any(), id, postID).
+				Return(test.addErr)
+
+			err := service.AddPost(context.Background(), userID, id, postID)
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_ReorderPosts(t *testing.T) {
+	userID := uuid.New()
+	id := uuid.New()
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	list := &model.ReadingList{ID: id, UserID: userID,
+		Items: []*model.ReadingListItem{{ListID: id, PostID: a},
+			{ListID: id, PostID: b}, {ListID: id, PostID: c}}}
+	tests := []struct {
+		name    string
+		postIDs []uuid.UUID
+		wantErr string
+	}{
+		{
+			name:    "success",
+			postIDs: []uuid.UUID{c, a, b},
+		},
+		{
+			name:    "missing post",
+			postIDs: []uuid.UUID{c, a},
+			wantErr: "post_ids must name every post of the list once",
+		},
+		{
+			name:    "duplicate post",
+			postIDs: []uuid.UUID{c, a, a},
+			wantErr: "post_ids must name every post of the list once",
+		},
+		{
+			name:    "post not in list",
+			postIDs: []uuid.UUID{c, a, b, uuid.New()},
+			wantErr: "post_ids must name every post of the list once",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setupMockRepoAndService(t)
+			mockRepo.EXPECT().FindByID(gomock.Any(), id, withPosts).
+				Return(list, nil).MinTimes(1)
+			if test.wantErr == "" {
+				mockRepo.EXPECT().SetPositions(gomock.Any(), id, test.postIDs).
+					Return(nil)
+			}
+
+			_, err := service.ReorderPosts(context.Background(), userID, id,
+				test.postIDs)
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_GetSharedList_NotFound(t *testing.T) {
+	mockRepo, service := setupMockRepoAndService(t)
+	mockRepo.EXPECT().FindByShareToken(gomock.Any(), "nope", withPosts).
+		Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.GetSharedList(context.Background(), "nope")
+
+	assert.Equal(t, http.StatusNotFound, apperrors.StatusCode(err))
+}
@@ -0,0 +1,120 @@
+package readinglist
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("readinglist")
+
+type tracedService struct {
+	next Service
+}
+
+// NewTracedService wraps a Service so every call runs in its own span.
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next}
+}
+
+// listAttributes identifies the list a call works on and who made it.
+func listAttributes(userID uuid.UUID, id uuid.UUID) trace.SpanStartOption {
+	return trace.WithAttributes(attribute.String("user.id", userID.String()),
+		attribute.String("reading_list.id", id.String()))
+}
+
+func (s *tracedService) GetLists(ctx context.Context,
+	userID uuid.UUID) ([]*model.ReadingList, error) {
+	ctx, span := tracer.Start(ctx, "readinglist.Service.GetLists",
+		trace.WithAttributes(attribute.String("user.id", userID.String())))
+	lists, err := s.next.GetLists(ctx, userID)
+	span.SetAttributes(attribute.Int("reading_list.count", len(lists)))
+	tracing.End(span, err)
+	return lists, err
+}
+
+func (s *tracedService) GetList(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID) (*model.ReadingList, error) {
+	ctx, span := tracer.Start(ctx, "readinglist.Service.GetList",
+		listAttributes(userID, id))
+	list, err := s.next.GetList(ctx, userID, id)
+	tracing.End(span, err)
+	return list, err
+}
+
+func (s *tracedService) GetDefaultList(ctx context.Context,
+	userID uuid.UUID) (*model.ReadingList, error) {
+	ctx, span := tracer.Start(ctx, "readinglist.Service.GetDefaultList",
+		trace.WithAttributes(attribute.String("user.id", userID.String())))
+	list, err := s.next.GetDefaultList(ctx, userID)
+	tracing.End(span, err)
+	return list, err
+}
+
+func (s *tracedService) GetSharedList(ctx context.Context,
+	token string) (*model.ReadingList, error) {
+	ctx, span := tracer.Start(ctx, "readinglist.Service.GetSharedList")
+	list, err := s.next.GetSharedList(ctx, token)
+	tracing.End(span, err)
+	return list, err
+}
+
+func (s *tracedService) CreateList(ctx context.Context, userID uuid.UUID,
+	req *CreateRequest) (*model.ReadingList, error) {
+	ctx, span := tracer.Start(ctx, "readinglist.Service.CreateList",
+		trace.WithAttributes(attribute.String("user.id", userID.String())))
+	list, err := s.next.CreateList(ctx, userID, req)
+	tracing.End(span, err)
+	return list, err
+}
+
+func (s *tracedService) UpdateList(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID, req *UpdateRequest) (*model.ReadingList, error) {
+	ctx, span := tracer.Start(ctx, "readinglist.Service.UpdateList",
+		listAttributes(userID, id))
+	list, err := s.next.UpdateList(ctx, userID, id, req)
+	tracing.End(span, err)
+	return list, err
+}
+
+func (s *tracedService) DeleteList(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "readinglist.Service.DeleteList",
+		listAttributes(userID, id))
+	err := s.next.DeleteList(ctx, userID, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) AddPost(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID, postID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "readinglist.Service.AddPost",
+		listAttributes(userID, id))
+	span.SetAttributes(attribute.String("post.id", postID.String()))
+	err := s.next.AddPost(ctx, userID, id, postID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) RemovePost(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID, postID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "readinglist.Service.RemovePost",
+		listAttributes(userID, id))
+	span.SetAttributes(attribute.String("post.id", postID.String()))
+	err := s.next.RemovePost(ctx, userID, id, postID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) ReorderPosts(ctx context.Context, userID uuid.UUID,
+	id uuid.UUID, postIDs []uuid.UUID) (*model.ReadingList, error) {
+	ctx, span := tracer.Start(ctx, "readinglist.Service.ReorderPosts",
+		listAttributes(userID, id))
+	span.SetAttributes(attribute.Int("post.count", len(postIDs)))
+	list, err := s.next.ReorderPosts(ctx, userID, id, postIDs)
+	tracing.End(span, err)
+	return list, err
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/health"
+	"gorm.io/gorm"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Worker is a background task that runs until its context is cancelled.
+type Worker func(ctx context.Context)
+
+type Server struct {
+	cfg     config.ServerConfig
+	http    *http.Server
+	db      *gorm.DB
+	monitor *health.Monitor
+	workers map[string]Worker
+}
+
+func New(cfg config.ServerConfig, handler http.Handler, db *gorm.DB,
+	monitor *health.Monitor) *Server {
+	return &Server{
+		cfg: cfg,
+		http: &http.Server{
+			Addr:         ":" + cfg.Port,
+			Handler:      handler,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			IdleTimeout:  cfg.IdleTimeout,
+		},
+		db:      db,
+		monitor: monitor,
+		workers: map[string]Worker{},
+	}
+}
+
+// AddWorker registers a background task that is started with the server and
+// stopped during shutdown. It must be called before Run.
+func (s *Server) AddWorker(name string, w Worker) {
+	s.workers[name] = w
+}
+
+// Run listens on the configured port and serves until ctx is cancelled or
+// the process receives SIGINT or SIGTERM, then shuts down gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts connections on ln until ctx is cancelled. Shutdown first
+// marks the process not ready, waits for the configured delay, then stops
+// accepting connections and waits up to the shutdown timeout for in-flight
+// requests and workers to finish before closing the database pool.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var wg sync.WaitGroup
+	for name, w := range s.workers {
+		wg.Add(1)
+		s.monitor.WorkerStarted(name)
+		go func() {
+			defer wg.Done()
+			w(workerCtx)
+			s.monitor.WorkerStopped(name)
+			slog.Info("worker stopped", "worker", name)
+		}()
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.http.Serve(ln)
+	}()
+	s.monitor.SetReady(true)
+	slog.Info("listening", "addr", ln.Addr().String())
+
+	var errs []error
+	select {
+	case err := <-serveErr:
+		errs = append(errs, fmt.Errorf("serve: %w", err))
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down")
+	s.monitor.SetReady(false)
+	time.Sleep(s.cfg.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(
+		context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := s.http.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("drain requests: %w", err))
+	}
+
+	stopWorkers()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		errs = append(errs, errors.New("workers did not stop before the shutdown timeout"))
+	}
+
+	if s.db != nil {
+		if sqlDB, err := s.db.DB(); err == nil {
+			if err := sqlDB.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("close database: %w", err))
+			}
+		}
+	}
+
+	slog.Info("shutdown complete")
+	return errors.Join(errs...)
+}
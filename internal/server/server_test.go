@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testConfig() config.ServerConfig {
+	return config.ServerConfig{
+		ReadTimeout:     time.Second,
+		WriteTimeout:    time.Second,
+		IdleTimeout:     time.Second,
+		ShutdownTimeout: 2 * time.Second,
+	}
+}
+
+func TestServer_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		_, _ = io.WriteString(w, "done")
+	})
+	monitor := health.NewMonitor()
+	srv := New(testConfig(), handler, nil, monitor)
+
+	var workerStopped atomic.Bool
+	srv.AddWorker("test", func(ctx context.Context) {
+		<-ctx.Done()
+		workerStopped.Store(true)
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, ln) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{body: string(body), err: err}
+	}()
+
+	<-started
+	assert.True(t, monitor.Ready())
+	assert.True(t, monitor.Workers()["test"].Running)
+	cancel()
+
+	res := <-responses
+	require.NoError(t, res.err)
+	assert.Equal(t, "done", res.body)
+	require.NoError(t, <-served)
+	assert.False(t, monitor.Ready())
+	assert.True(t, workerStopped.Load())
+	assert.False(t, monitor.Workers()["test"].Running)
+
+	_, err = http.Get("http://" + ln.Addr().String())
+	assert.Error(t, err)
+}
+
+func TestServer_ShutdownTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.ShutdownTimeout = 50 * time.Millisecond
+	srv := New(cfg, http.NotFoundHandler(), nil, health.NewMonitor())
+	release := make(chan struct{})
+	defer close(release)
+	srv.AddWorker("stuck", func(ctx context.Context) { <-release })
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = srv.Serve(ctx, ln)
+
+	assert.ErrorContains(t, err, "workers did not stop")
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type APIKey struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Name      string    `gorm:"not null"`
+	Prefix    string    `gorm:"not null"`
+	KeyHash   string    `gorm:"not null;unique"`
+	CreatedAt time.Time `gorm:"not null"`
+	RevokedAt *time.Time
+	// UserID links the key to the user who acts with it, for endpoints such
+	// as following an author. Keys of services are not linked.
+	UserID *uuid.UUID `gorm:"type:char(36)"`
+}
+
+func NewAPIKey(name string, prefix string, keyHash string,
+	userID *uuid.UUID) *APIKey {
+	return &APIKey{Name: name, Prefix: prefix, KeyHash: keyHash,
+		UserID: userID}
+}
+
+//goland:noinspection GoUnusedParameter
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Follow records that the follower reads the posts of the followee.
+type Follow struct {
+	FollowerID uuid.UUID `gorm:"type:char(36);primaryKey"`
+	FolloweeID uuid.UUID `gorm:"type:char(36);primaryKey"`
+	CreatedAt  time.Time `gorm:"not null"`
+	Follower   *User     `gorm:"foreignKey:FollowerID"`
+	Followee   *User     `gorm:"foreignKey:FolloweeID"`
+}
+
+func NewFollow(followerID uuid.UUID, followeeID uuid.UUID) *Follow {
+	return &Follow{FollowerID: followerID, FolloweeID: followeeID}
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// IdempotencyKey remembers the response to a request made with an
+// Idempotency-Key header. StatusCode is zero while the first request is
+// still being processed.
+type IdempotencyKey struct {
+	Key         string `gorm:"primaryKey"`
+	Fingerprint string `gorm:"not null"`
+	StatusCode  int    `gorm:"not null"`
+	ContentType string `gorm:"not null"`
+	Body        []byte
+	CreatedAt   time.Time `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null"`
+}
+
+func NewIdempotencyKey(key string, fingerprint string,
+	expiresAt time.Time) *IdempotencyKey {
+	return &IdempotencyKey{Key: key, Fingerprint: fingerprint,
+		ExpiresAt: expiresAt}
+}
+
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
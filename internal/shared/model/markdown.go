@@ -0,0 +1,16 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// MarkdownPost links a post to the Markdown file it is synced with. The file
+// is named after Slug, and Tags keeps the tags of its front matter, which
+// posts have no column for.
+type MarkdownPost struct {
+	Slug      string    `gorm:"primaryKey"`
+	PostID    uuid.UUID `gorm:"type:char(36);not null;unique"`
+	Tags      []string  `gorm:"serializer:json;not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
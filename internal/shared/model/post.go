@@ -14,6 +14,8 @@ type Post struct {
 	UpdatedAt time.Time `gorm:"not null" example:"2025-08-19T15:04:05Z"`
 	UserID    uuid.UUID `gorm:"type:char(36);not null;index"`
 	User      *User     `gorm:"constraint:OnDelete:CASCADE"`
+	// ReactionCounts is read only, the database maintains it.
+	ReactionCounts []*ReactionCount `gorm:"foreignKey:PostID"`
 }
 
 func NewPost(title string, content string, authorID uuid.UUID) *Post {
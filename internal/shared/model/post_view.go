@@ -0,0 +1,13 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// PostView is the number of views a post had on one day, in UTC.
+type PostView struct {
+	PostID uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Day    time.Time `gorm:"type:date;primaryKey"`
+	Views  int64     `gorm:"not null"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+// Reaction kinds, each shown as an emoji by clients.
+const (
+	ReactionLike      = "like"      // 👍
+	ReactionLove      = "love"      // ❤️
+	ReactionLaugh     = "laugh"     // 😂
+	ReactionWow       = "wow"       // 😮
+	ReactionSad       = "sad"       // 😢
+	ReactionCelebrate = "celebrate" // 🎉
+)
+
+// ReactionKinds lists every reaction kind in display order.
+var ReactionKinds = []string{ReactionLike, ReactionLove, ReactionLaugh,
+	ReactionWow, ReactionSad, ReactionCelebrate}
+
+func IsReactionKind(kind string) bool {
+	return slices.Contains(ReactionKinds, kind)
+}
+
+// Reaction is one user's reaction of one kind to a post.
+type Reaction struct {
+	PostID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Kind      string    `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"not null"`
+	User      *User
+}
+
+func NewReaction(postID uuid.UUID, userID uuid.UUID, kind string) *Reaction {
+	return &Reaction{PostID: postID, UserID: userID, Kind: kind}
+}
+
+// ReactionCount is the number of reactions of one kind to a post, kept up
+// to date by the database.
+type ReactionCount struct {
+	PostID uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Kind   string    `gorm:"primaryKey"`
+	Count  int64     `gorm:"not null"`
+}
+
+func (ReactionCount) TableName() string {
+	return "post_reaction_counts"
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// DefaultReadingListName is the name of the list every user has for posts
+// saved without picking a list.
+const DefaultReadingListName = "Saved"
+
+// ReadingList is a collection of posts a user saved to read later.
+type ReadingList struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null"`
+	Name      string    `gorm:"not null"`
+	IsDefault bool      `gorm:"not null;default:false"`
+	// ShareToken lets anyone with a link read the list. It is nil while the
+	// list is private.
+	ShareToken *string
+	CreatedAt  time.Time `gorm:"not null"`
+	UpdatedAt  time.Time `gorm:"not null"`
+	// PostCount is the number of posts in the list, filled in when lists
+	// are loaded without their items.
+	PostCount int64              `gorm:"->;-:migration"`
+	Items     []*ReadingListItem `gorm:"foreignKey:ListID;constraint:OnDelete:CASCADE"`
+}
+
+func NewReadingList(userID uuid.UUID, name string) *ReadingList {
+	return &ReadingList{UserID: userID, Name: name}
+}
+
+//goland:noinspection GoExportedElementShouldHaveComment
+func (l *ReadingList) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// ReadingListItem is a post in a reading list. Items are shown in the
+// order of their position.
+type ReadingListItem struct {
+	ListID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	PostID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Position  int       `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	Post      *Post     `gorm:"constraint:OnDelete:CASCADE"`
+}
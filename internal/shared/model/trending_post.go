@@ -0,0 +1,15 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// TrendingPost is the score of a post in the trending ranking as of the
+// last refresh.
+type TrendingPost struct {
+	PostID     uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Score      float64   `gorm:"not null"`
+	ComputedAt time.Time `gorm:"not null"`
+	Post       *Post
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Kinds of WordPress entities a mapping points from.
+const (
+	WordPressAuthor = "author"
+	WordPressPost   = "post"
+)
+
+// WordPressMapping links an author or post of an imported WordPress site to
+// the user or post it became, so old IDs and URLs can be redirected.
+type WordPressMapping struct {
+	Site        string    `gorm:"primaryKey"`
+	Kind        string    `gorm:"primaryKey"`
+	WordPressID int64     `gorm:"column:wordpress_id;primaryKey"`
+	URL         string    `gorm:"not null"`
+	TargetID    uuid.UUID `gorm:"type:char(36);not null"`
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+func (WordPressMapping) TableName() string {
+	return "wordpress_mappings"
+}
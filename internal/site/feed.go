@@ -0,0 +1,148 @@
+package site
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// feedSize is the number of latest posts in the feeds.
+const feedSize = 20
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Author      string `xml:"author,omitempty"`
+	Description string `xml:"description"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string       `xml:"title"`
+	ID      string       `xml:"id"`
+	Links   []atomLink   `xml:"link"`
+	Updated string       `xml:"updated"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Link      atomLink    `xml:"link"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Summary   string      `xml:"summary"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// latest returns the first feedSize posts, which are sorted newest first.
+func latest(posts []*PostView) []*PostView {
+	return posts[:min(len(posts), feedSize)]
+}
+
+// lastUpdate returns when any of posts last changed, or now without
+// posts.
+func lastUpdate(posts []*PostView) time.Time {
+	var t time.Time
+	for _, p := range posts {
+		if p.Updated.After(t) {
+			t = p.Updated
+		}
+	}
+	if t.IsZero() {
+		return time.Now()
+	}
+	return t
+}
+
+// WriteRSS writes an RSS 2.0 feed of the latest posts.
+func WriteRSS(w io.Writer, site *Site, posts []*PostView) error {
+	posts = latest(posts)
+	feed := &rss{Version: "2.0", Channel: rssChannel{
+		Title:       site.Title,
+		Link:        site.URL(""),
+		Description: "Latest posts of " + site.Title,
+	}}
+	feed.Channel.LastBuildDate = lastUpdate(posts).UTC().Format(time.RFC1123Z)
+	for _, p := range posts {
+		item := &rssItem{
+			Title:       p.Title,
+			Link:        p.URL,
+			GUID:        p.URL,
+			PubDate:     p.Published.UTC().Format(time.RFC1123Z),
+			Description: p.Summary,
+		}
+		if p.Author != nil {
+			item.Author = p.Author.Username
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+	return writeXML(w, feed)
+}
+
+// WriteAtom writes an Atom feed of the latest posts.
+func WriteAtom(w io.Writer, site *Site, posts []*PostView) error {
+	posts = latest(posts)
+	feed := &atomFeed{
+		Title: site.Title,
+		ID:    site.URL(""),
+		Links: []atomLink{
+			{Href: site.URL("")},
+			{Href: site.URL("atom.xml"), Rel: "self"},
+		},
+		Updated: lastUpdate(posts).UTC().Format(time.RFC3339),
+	}
+	for _, p := range posts {
+		entry := &atomEntry{
+			Title:     p.Title,
+			ID:        "urn:uuid:" + p.ID.String(),
+			Link:      atomLink{Href: p.URL},
+			Published: p.Published.UTC().Format(time.RFC3339),
+			Updated:   p.Updated.UTC().Format(time.RFC3339),
+			Summary:   p.Summary,
+		}
+		if p.Author != nil {
+			entry.Author = &atomAuthor{Name: p.Author.Username}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return writeXML(w, feed)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
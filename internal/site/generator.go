@@ -0,0 +1,197 @@
+package site
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/post"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const DefaultPerPage = 10
+
+// Generator renders all posts into a static site.
+type Generator struct {
+	Posts   post.Service
+	Theme   *Theme
+	Site    *Site
+	PerPage int
+}
+
+type BuildReport struct {
+	Pages   int `json:"pages"`
+	Posts   int `json:"posts"`
+	Authors int `json:"authors"`
+}
+
+func NewGenerator(posts post.Service, theme *Theme, site *Site) *Generator {
+	return &Generator{Posts: posts, Theme: theme, Site: site,
+		PerPage: DefaultPerPage}
+}
+
+// Build writes the index, the archive, a page per post and per author, the
+// feeds and the static files of the theme to outDir. Files from an earlier
+// build are overwritten but never deleted.
+func (g *Generator) Build(ctx context.Context, outDir string) (*BuildReport, error) {
+	posts, err := g.Posts.GetPosts(ctx,
+		query.Options{Expand: []string{post.ExpandAuthor}})
+	if err != nil {
+		return nil, fmt.Errorf("load posts: %w", err)
+	}
+	sortNewestFirst(posts)
+
+	b := &build{Generator: g, outDir: outDir, report: &BuildReport{}}
+	views := make([]*PostView, len(posts))
+	byAuthor := map[string][]*PostView{}
+	var authors []*AuthorView
+	for i, p := range posts {
+		views[i] = newPostView(g.Site, p)
+		if a := views[i].Author; a != nil {
+			if _, ok := byAuthor[a.Username]; !ok {
+				authors = append(authors, a)
+			}
+			byAuthor[a.Username] = append(byAuthor[a.Username], views[i])
+		}
+	}
+
+	// The index is the first archive page, its older posts continue there.
+	index := &Page{Site: g.Site, Posts: views[:min(len(views), g.PerPage)],
+		Pagination: newPagination(g.Site, archivePath, 1,
+			b.pages(len(views)))}
+	if err := b.page("", PageIndex, index); err != nil {
+		return nil, err
+	}
+	if err := b.list(archivePath, PageArchive, nil, views); err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		if err := b.page(postPath(v.ID), PagePost,
+			&Page{Site: g.Site, Post: v}); err != nil {
+			return nil, err
+		}
+	}
+	for _, a := range authors {
+		if err := b.list(authorPath(a.Username), PageAuthor, a,
+			byAuthor[a.Username]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := b.file("feed.xml", func(w io.Writer) error {
+		return WriteRSS(w, g.Site, views)
+	}); err != nil {
+		return nil, err
+	}
+	if err := b.file("atom.xml", func(w io.Writer) error {
+		return WriteAtom(w, g.Site, views)
+	}); err != nil {
+		return nil, err
+	}
+	if err := b.static(); err != nil {
+		return nil, err
+	}
+
+	b.report.Posts = len(views)
+	b.report.Authors = len(authors)
+	return b.report, nil
+}
+
+// sortNewestFirst orders posts by publish date, newest first, and by ID
+// among posts published at the same time.
+func sortNewestFirst(posts []*model.Post) {
+	slices.SortFunc(posts, func(a, b *model.Post) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return slices.Compare(a.ID[:], b.ID[:])
+	})
+}
+
+// build is one run of a Generator.
+type build struct {
+	*Generator
+	outDir string
+	report *BuildReport
+}
+
+// pages returns the number of list pages n posts take.
+func (b *build) pages(n int) int {
+	return (n + b.PerPage - 1) / b.PerPage
+}
+
+// list writes posts split into pages below base.
+func (b *build) list(base string, page string, author *AuthorView,
+	posts []*PostView) error {
+	total := b.pages(len(posts))
+	for n := 1; n <= max(total, 1); n++ {
+		from := min((n-1)*b.PerPage, len(posts))
+		to := min(n*b.PerPage, len(posts))
+		err := b.page(pagePath(base, n), page, &Page{
+			Site:       b.Site,
+			Posts:      posts[from:to],
+			Author:     author,
+			Pagination: newPagination(b.Site, base, n, total),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// page renders page into the index.html of dir.
+func (b *build) page(dir string, page string, data *Page) error {
+	var buf bytes.Buffer
+	if err := b.Theme.Render(&buf, page, data); err != nil {
+		return fmt.Errorf("render %s: %w", dir+"index.html", err)
+	}
+	b.report.Pages++
+	return b.file(dir+"index.html", func(w io.Writer) error {
+		_, err := buf.WriteTo(w)
+		return err
+	})
+}
+
+// file creates the file at path below the output directory and fills it
+// with write.
+func (b *build) file(path string, write func(w io.Writer) error) error {
+	name := filepath.Join(b.outDir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// static copies the static files of the theme below static/.
+func (b *build) static() error {
+	files := b.Theme.Static()
+	return fs.WalkDir(files, ".", func(path string, d fs.DirEntry,
+		err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		return b.file(staticDir+"/"+path, func(w io.Writer) error {
+			f, err := files.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(w, f)
+			return err
+		})
+	})
+}
@@ -0,0 +1,147 @@
+package site
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/post"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	alice = &model.User{ID: uuid.New(), Username: "alice"}
+	bob   = &model.User{ID: uuid.New(), Username: "bob"}
+)
+
+func samplePosts() []*model.Post {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	posts := make([]*model.Post, 5)
+	for i := range posts {
+		author := alice
+		if i%2 == 1 {
+			author = bob
+		}
+		created := start.Add(time.Duration(i) * 24 * time.Hour)
+		posts[i] = &model.Post{ID: uuid.New(),
+			Title:     fmt.Sprintf("Post %d", i),
+			Content:   fmt.Sprintf("Body <%d>\n\nSecond paragraph", i),
+			CreatedAt: created, UpdatedAt: created,
+			UserID: author.ID, User: author}
+	}
+	return posts
+}
+
+func loadDefaultTheme(t *testing.T) *Theme {
+	t.Helper()
+	files, err := EmbeddedTheme(DefaultTheme)
+	require.NoError(t, err)
+	theme, err := LoadTheme(files)
+	require.NoError(t, err)
+	return theme
+}
+
+func read(t *testing.T, dir string, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestGenerator_Build(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	posts := post.NewMockService(ctrl)
+	sample := samplePosts()
+	posts.EXPECT().GetPosts(gomock.Any(),
+		query.Options{Expand: []string{post.ExpandAuthor}}).
+		Return(slices.Clone(sample), nil)
+	generator := NewGenerator(posts, loadDefaultTheme(t),
+		&Site{Title: "Test Blog", BaseURL: "https://blog.example.com/"})
+	generator.PerPage = 2
+	out := t.TempDir()
+
+	report, err := generator.Build(context.Background(), out)
+
+	require.NoError(t, err)
+	// index, 3 archive pages, 5 posts, 2 pages for alice and 1 for bob
+	assert.Equal(t, &BuildReport{Pages: 12, Posts: 5, Authors: 2}, report)
+
+	index := read(t, out, "index.html")
+	assert.Contains(t, index, "<title>Test Blog</title>")
+	assert.Contains(t, index, "Post 4")
+	assert.Contains(t, index, "Post 3")
+	assert.NotContains(t, index, "Post 2")
+	assert.Contains(t, index, `href="https://blog.example.com/archive/page/2/"`)
+
+	archive := read(t, out, "archive/page/2/index.html")
+	assert.Contains(t, archive, "Post 2")
+	assert.Contains(t, archive,
+		`href="https://blog.example.com/archive/page/3/"`)
+	assert.Contains(t, archive, `href="https://blog.example.com/archive/"`)
+
+	page := read(t, out, "posts/"+sample[0].ID.String()+"/index.html")
+	assert.Contains(t, page, "<title>Post 0 · Test Blog</title>")
+	assert.Contains(t, page, "<p>Body &lt;0&gt;</p>")
+	assert.Contains(t, page, "<p>Second paragraph</p>")
+	assert.Contains(t, page, `href="https://blog.example.com/authors/alice/"`)
+
+	author := read(t, out, "authors/alice/page/2/index.html")
+	assert.Contains(t, author, "Posts by alice")
+	assert.Contains(t, author, "Post 0")
+	assert.NotContains(t, read(t, out, "authors/bob/index.html"), "Post 0")
+
+	assert.Contains(t, read(t, out, "feed.xml"),
+		"<link>https://blog.example.com/posts/"+sample[4].ID.String()+
+			"/</link>")
+	assert.Contains(t, read(t, out, "atom.xml"),
+		"<id>urn:uuid:"+sample[4].ID.String()+"</id>")
+	assert.Contains(t, read(t, out, "static/style.css"), "body")
+}
+
+func TestGenerator_Build_NoPosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	posts := post.NewMockService(ctrl)
+	posts.EXPECT().GetPosts(gomock.Any(), gomock.Any()).Return(nil, nil)
+	generator := NewGenerator(posts, loadDefaultTheme(t),
+		&Site{Title: "Empty", BaseURL: "/"})
+	out := t.TempDir()
+
+	report, err := generator.Build(context.Background(), out)
+
+	require.NoError(t, err)
+	assert.Equal(t, &BuildReport{Pages: 2}, report)
+	assert.Contains(t, read(t, out, "index.html"), "No posts yet.")
+}
+
+func TestGenerator_Build_LoadFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	posts := post.NewMockService(ctrl)
+	posts.EXPECT().GetPosts(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("db error"))
+	generator := NewGenerator(posts, loadDefaultTheme(t), &Site{})
+
+	_, err := generator.Build(context.Background(), t.TempDir())
+
+	assert.ErrorContains(t, err, "load posts: db error")
+}
+
+func TestEmbeddedTheme_Unknown(t *testing.T) {
+	_, err := EmbeddedTheme("neon")
+
+	assert.ErrorContains(t, err, `unknown theme "neon", available: default`)
+}
+
+func TestSummarize(t *testing.T) {
+	assert.Equal(t, "one two", summarize(" one\n\ntwo "))
+	assert.Equal(t, "word…", summarize("word "+strings.Repeat("x", 300)))
+}
@@ -0,0 +1,275 @@
+package site
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/post"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/user"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// newestFirst is the order of every list page.
+var newestFirst = query.Page{Sort: "created_at", Desc: true}
+
+var expandAuthor = query.Options{Expand: []string{post.ExpandAuthor}}
+
+// Handler serves the pages of a theme rendered from the live data, the
+// public HTML counterpart of the static export.
+type Handler struct {
+	Posts   post.Service
+	Users   user.Service
+	Theme   *Theme
+	Site    *Site
+	PerPage int
+	// MaxAge is how long clients and proxies may cache a response.
+	MaxAge time.Duration
+	// Views counts the reads of post pages unless it is nil.
+	Views post.ViewRecorder
+}
+
+func NewHandler(posts post.Service, users user.Service, theme *Theme,
+	site *Site) *Handler {
+	return &Handler{Posts: posts, Users: users, Theme: theme, Site: site,
+		PerPage: DefaultPerPage}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	get := func(path string, handler gin.HandlerFunc) {
+		r.GET(path, handler)
+		r.HEAD(path, handler)
+	}
+	get("/", h.index)
+	get("/"+archivePath, h.archive)
+	get("/"+archivePath+"page/:n/", h.archive)
+	get("/posts/:id/", h.post)
+	get("/authors/:username/", h.author)
+	get("/authors/:username/page/:n/", h.author)
+	get("/feed.xml", h.feed(WriteRSS, "application/rss+xml"))
+	get("/atom.xml", h.feed(WriteAtom, "application/atom+xml"))
+	get("/"+staticDir+"/*filepath", h.static)
+}
+
+func (h *Handler) index(c *gin.Context) {
+	posts, total, err := h.Posts.GetPostPage(c.Request.Context(),
+		h.page(1), expandAuthor)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	h.render(c, PageIndex, &Page{Site: h.Site, Posts: h.views(posts),
+		Pagination: newPagination(h.Site, archivePath, 1, h.pages(total))},
+		time.Time{})
+}
+
+func (h *Handler) archive(c *gin.Context) {
+	n, ok := h.pageNumber(c, archivePath)
+	if !ok {
+		return
+	}
+	posts, total, err := h.Posts.GetPostPage(c.Request.Context(),
+		h.page(n), expandAuthor)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	h.list(c, PageArchive, archivePath, n, total, nil, posts)
+}
+
+func (h *Handler) post(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		notFound(c)
+		return
+	}
+	p, err := h.Posts.GetPost(c.Request.Context(), id, expandAuthor)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	post.RecordView(c, h.Views, id)
+	view := newPostView(h.Site, p)
+	h.render(c, PagePost, &Page{Site: h.Site, Post: view}, view.Updated)
+}
+
+func (h *Handler) author(c *gin.Context) {
+	ctx := c.Request.Context()
+	u, err := h.Users.GetUserByUsername(ctx, c.Param("username"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	base := authorPath(u.Username)
+	n, ok := h.pageNumber(c, base)
+	if !ok {
+		return
+	}
+	posts, total, err := h.Posts.GetUserPosts(ctx, u.ID, h.page(n),
+		expandAuthor)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	h.list(c, PageAuthor, base, n, total, newAuthorView(h.Site, u), posts)
+}
+
+// feed serves the latest posts written by write.
+func (h *Handler) feed(write func(io.Writer, *Site, []*PostView) error,
+	contentType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page := newestFirst
+		page.Number, page.PerPage = 1, feedSize
+		posts, _, err := h.Posts.GetPostPage(c.Request.Context(), page,
+			expandAuthor)
+		if err != nil {
+			h.fail(c, err)
+			return
+		}
+		var buf bytes.Buffer
+		if err := write(&buf, h.Site, h.views(posts)); err != nil {
+			h.fail(c, err)
+			return
+		}
+		h.respond(c, contentType+"; charset=utf-8", buf.Bytes(), time.Time{})
+	}
+}
+
+func (h *Handler) static(c *gin.Context) {
+	name := strings.TrimPrefix(path.Clean(c.Param("filepath")), "/")
+	data, err := fs.ReadFile(h.Theme.Static(), name)
+	if err != nil {
+		notFound(c)
+		return
+	}
+	h.cache(c)
+	http.ServeContent(c.Writer, c.Request, name, time.Time{},
+		bytes.NewReader(data))
+}
+
+// list renders page n of a list of total posts below base.
+func (h *Handler) list(c *gin.Context, page string, base string, n int,
+	total int64, author *AuthorView, posts []*model.Post) {
+	pages := h.pages(total)
+	if n > max(pages, 1) {
+		notFound(c)
+		return
+	}
+	h.render(c, page, &Page{Site: h.Site, Posts: h.views(posts),
+		Author: author, Pagination: newPagination(h.Site, base, n, pages)},
+		time.Time{})
+}
+
+// pageNumber reads the page number from the path of a list at base. The
+// first page lives at base itself, so its numbered path redirects there.
+func (h *Handler) pageNumber(c *gin.Context, base string) (int, bool) {
+	v := c.Param("n")
+	if v == "" {
+		return 1, true
+	}
+	n, err := strconv.Atoi(v)
+	switch {
+	case err != nil || n < 1:
+		notFound(c)
+		return 0, false
+	case n == 1:
+		c.Redirect(http.StatusMovedPermanently, h.Site.URL(base))
+		return 0, false
+	}
+	return n, true
+}
+
+func (h *Handler) page(n int) query.Page {
+	page := newestFirst
+	page.Number, page.PerPage = n, h.PerPage
+	return page
+}
+
+// pages returns the number of list pages total posts take.
+func (h *Handler) pages(total int64) int {
+	return int((total + int64(h.PerPage) - 1) / int64(h.PerPage))
+}
+
+func (h *Handler) views(posts []*model.Post) []*PostView {
+	views := make([]*PostView, len(posts))
+	for i, p := range posts {
+		views[i] = newPostView(h.Site, p)
+	}
+	return views
+}
+
+// render writes page with data. modified is sent as Last-Modified unless
+// it is zero.
+func (h *Handler) render(c *gin.Context, page string, data *Page,
+	modified time.Time) {
+	var buf bytes.Buffer
+	if err := h.Theme.Render(&buf, page, data); err != nil {
+		h.fail(c, err)
+		return
+	}
+	h.respond(c, "text/html; charset=utf-8", buf.Bytes(), modified)
+}
+
+// respond writes body with cache headers. The ETag is a hash of the body,
+// so a client that already has it gets 304 Not Modified without it.
+func (h *Handler) respond(c *gin.Context, contentType string, body []byte,
+	modified time.Time) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	h.cache(c)
+	c.Header("ETag", etag)
+	if !modified.IsZero() {
+		c.Header("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	if matchesETag(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+func (h *Handler) cache(c *gin.Context) {
+	c.Header("Cache-Control",
+		"public, max-age="+strconv.Itoa(int(h.MaxAge.Seconds())))
+}
+
+// matchesETag reports whether the If-None-Match header lists etag, weak
+// validators included.
+func matchesETag(header string, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == etag || v == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// fail answers with 404 for missing posts and authors and with 500 for
+// everything else.
+func (h *Handler) fail(c *gin.Context, err error) {
+	var ne *apperrors.NotFoundError
+	if errors.As(err, &ne) {
+		notFound(c)
+		return
+	}
+	logging.FromContext(c.Request.Context()).Error("render page failed",
+		"path", c.Request.URL.Path, "error", err)
+	c.String(http.StatusInternalServerError, "internal server error")
+}
+
+func notFound(c *gin.Context) {
+	c.String(http.StatusNotFound, "page not found")
+}
@@ -0,0 +1,278 @@
+package site
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/post"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func setupHandler(t *testing.T) (*gin.Engine, *post.MockService,
+	*user.MockService) {
+	ctrl := gomock.NewController(t)
+	posts := post.NewMockService(ctrl)
+	users := user.NewMockService(ctrl)
+	handler := NewHandler(posts, users, loadDefaultTheme(t),
+		&Site{Title: "Test Blog", BaseURL: "/blog/"})
+	handler.PerPage = 2
+	handler.MaxAge = time.Minute
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler.RegisterRoutes(router.Group("/blog"))
+	return router, posts, users
+}
+
+func get(router *gin.Engine, path string, header http.Header) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func pageOf(n int) query.Page {
+	return query.Page{Number: n, PerPage: 2, Sort: "created_at", Desc: true}
+}
+
+func TestHandler_Index(t *testing.T) {
+	router, posts, _ := setupHandler(t)
+	sample := samplePosts()
+	posts.EXPECT().GetPostPage(gomock.Any(), pageOf(1),
+		query.Options{Expand: []string{post.ExpandAuthor}}).
+		Return(sample[:2], int64(5), nil)
+
+	w := get(router, "/blog/", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), "Post 0")
+	assert.Contains(t, w.Body.String(), `href="/blog/archive/page/2/"`)
+}
+
+func TestHandler_Archive(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		mockBehaviour func(posts *post.MockService)
+		wantStatus    int
+		wantBody      string
+		wantLocation  string
+	}{
+		{
+			name: "second page",
+			path: "/blog/archive/page/2/",
+			mockBehaviour: func(posts *post.MockService) {
+				posts.EXPECT().GetPostPage(gomock.Any(), pageOf(2), gomock.Any()).
+					Return(samplePosts()[2:4], int64(5), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `href="/blog/archive/page/3/"`,
+		},
+		{
+			name:         "first page redirects to the archive",
+			path:         "/blog/archive/page/1/",
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "/blog/archive/",
+		},
+		{
+			name: "page after the last",
+			path: "/blog/archive/page/4/",
+			mockBehaviour: func(posts *post.MockService) {
+				posts.EXPECT().GetPostPage(gomock.Any(), pageOf(4), gomock.Any()).
+					Return(nil, int64(5), nil)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   "page not found",
+		},
+		{
+			name:       "page not a number",
+			path:       "/blog/archive/page/two/",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "db error",
+			path: "/blog/archive/",
+			mockBehaviour: func(posts *post.MockService) {
+				posts.EXPECT().GetPostPage(gomock.Any(), pageOf(1), gomock.Any()).
+					Return(nil, int64(0), errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "internal server error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, posts, _ := setupHandler(t)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(posts)
+			}
+
+			w := get(router, test.path, nil)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantBody)
+			assert.Equal(t, test.wantLocation, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestHandler_Post(t *testing.T) {
+	sample := samplePosts()[0]
+	sample.UpdatedAt = sample.CreatedAt.Add(time.Hour)
+	path := "/blog/posts/" + sample.ID.String() + "/"
+
+	t.Run("renders and revalidates", func(t *testing.T) {
+		router, posts, _ := setupHandler(t)
+		posts.EXPECT().GetPost(gomock.Any(), sample.ID, gomock.Any()).
+			Return(sample, nil).Times(2)
+
+		w := get(router, path, nil)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "<p>Body &lt;0&gt;</p>")
+		assert.Equal(t, "Fri, 01 Mar 2024 11:00:00 GMT",
+			w.Header().Get("Last-Modified"))
+
+		etag := w.Header().Get("ETag")
+		w = get(router, path, http.Header{"If-None-Match": {`W/` + etag}})
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	})
+	t.Run("not found", func(t *testing.T) {
+		router, posts, _ := setupHandler(t)
+		posts.EXPECT().GetPost(gomock.Any(), sample.ID, gomock.Any()).
+			Return(nil, apperrors.NewNotFoundError("post", sample.ID))
+
+		w := get(router, path, nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("Cache-Control"))
+	})
+	t.Run("invalid id", func(t *testing.T) {
+		router, _, _ := setupHandler(t)
+
+		w := get(router, "/blog/posts/first/", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHandler_Author(t *testing.T) {
+	t.Run("second page", func(t *testing.T) {
+		router, posts, users := setupHandler(t)
+		users.EXPECT().GetUserByUsername(gomock.Any(), "alice").
+			Return(alice, nil)
+		posts.EXPECT().GetUserPosts(gomock.Any(), alice.ID, pageOf(2),
+			gomock.Any()).Return(samplePosts()[:1], int64(3), nil)
+
+		w := get(router, "/blog/authors/alice/page/2/", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Posts by alice")
+		assert.Contains(t, w.Body.String(), `href="/blog/authors/alice/"`)
+	})
+	t.Run("unknown author", func(t *testing.T) {
+		router, _, users := setupHandler(t)
+		users.EXPECT().GetUserByUsername(gomock.Any(), "carol").
+			Return(nil, apperrors.NewNotFoundByNameError("user", "carol"))
+
+		w := get(router, "/blog/authors/carol/", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHandler_Feeds(t *testing.T) {
+	latest := query.Page{Number: 1, PerPage: feedSize, Sort: "created_at",
+		Desc: true}
+	tests := []struct {
+		path            string
+		wantContentType string
+	}{
+		{path: "/blog/feed.xml", wantContentType: "application/rss+xml; charset=utf-8"},
+		{path: "/blog/atom.xml", wantContentType: "application/atom+xml; charset=utf-8"},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			router, posts, _ := setupHandler(t)
+			sample := samplePosts()
+			posts.EXPECT().GetPostPage(gomock.Any(), latest, gomock.Any()).
+				Return(sample, int64(5), nil)
+
+			w := get(router, test.path, nil)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, test.wantContentType, w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(),
+				"/blog/posts/"+sample[0].ID.String()+"/")
+		})
+	}
+}
+
+func TestHandler_Static(t *testing.T) {
+	router, _, _ := setupHandler(t)
+
+	w := get(router, "/blog/static/style.css", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/css")
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+
+	assert.Equal(t, http.StatusNotFound,
+		get(router, "/blog/static/../layout.html", nil).Code)
+	assert.Equal(t, http.StatusNotFound, get(router, "/blog/static/", nil).Code)
+}
+
+func TestOpenTheme_Override(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PagePost),
+		[]byte(`{{define "content"}}<h1 class="custom">{{.Post.Title}}</h1>{{end}}`),
+		0o600))
+
+	theme, err := OpenTheme(DefaultTheme, dir)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	require.NoError(t, theme.Render(w, PagePost, &Page{Site: &Site{},
+		Post: &PostView{ID: uuid.New(), Title: "Hello"}}))
+	assert.Contains(t, w.Body.String(), `<h1 class="custom">Hello</h1>`)
+	_, err = theme.Static().Open("style.css")
+	assert.NoError(t, err)
+
+	_, err = OpenTheme(DefaultTheme, filepath.Join(dir, "missing"))
+	assert.ErrorContains(t, err, "theme directory")
+}
+
+func TestOverlay_ReadDir(t *testing.T) {
+	base := fstest.MapFS{"static/a.css": {}, "static/b.css": {}}
+	override := fstest.MapFS{"static/b.css": {Data: []byte("new")},
+		"static/c.css": {}}
+
+	files := Overlay(base, override)
+
+	require.NoError(t, fstest.TestFS(files, "static/a.css", "static/b.css",
+		"static/c.css"))
+	data, err := fs.ReadFile(files, "static/b.css")
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+}
@@ -0,0 +1,89 @@
+package site
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// overlay serves the files of override in place of those of base, so a
+// theme directory only needs the files it changes.
+type overlay struct {
+	base     fs.FS
+	override fs.FS
+}
+
+// Overlay returns a file system with the files of override on top of base.
+func Overlay(base fs.FS, override fs.FS) fs.FS {
+	return &overlay{base: base, override: override}
+}
+
+func (o *overlay) Open(name string) (fs.File, error) {
+	f, err := o.override.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o.base.Open(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil || !info.IsDir() {
+		return f, err
+	}
+	entries, err := o.ReadDir(name)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &overlayDir{File: f, entries: entries}, nil
+}
+
+// overlayDir is a directory of the override listing the merged entries.
+type overlayDir struct {
+	fs.File
+	entries []fs.DirEntry
+}
+
+func (d *overlayDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	entries := d.entries[:min(n, len(d.entries))]
+	d.entries = d.entries[len(entries):]
+	return entries, nil
+}
+
+// ReadDir merges the entries of both file systems, so walking a directory
+// finds the files of the base that were not overridden.
+func (o *overlay) ReadDir(name string) ([]fs.DirEntry, error) {
+	over, overErr := fs.ReadDir(o.override, name)
+	if overErr != nil && !errors.Is(overErr, fs.ErrNotExist) {
+		return nil, overErr
+	}
+	base, baseErr := fs.ReadDir(o.base, name)
+	if baseErr != nil && !errors.Is(baseErr, fs.ErrNotExist) {
+		return nil, baseErr
+	}
+	if overErr != nil && baseErr != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := over
+	for _, e := range base {
+		if !slices.ContainsFunc(over, func(d fs.DirEntry) bool {
+			return d.Name() == e.Name()
+		}) {
+			entries = append(entries, e)
+		}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
@@ -0,0 +1,142 @@
+package site
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+//go:embed themes
+var themes embed.FS
+
+const DefaultTheme = "default"
+
+// Pages every theme provides. Each is parsed together with layoutFile and
+// partialsFile and may define the "title" and "content" blocks.
+const (
+	PageIndex   = "index.html"
+	PageArchive = "archive.html"
+	PageAuthor  = "author.html"
+	PagePost    = "post.html"
+)
+
+const (
+	layoutFile   = "layout.html"
+	partialsFile = "list.html"
+	// staticDir holds the stylesheets, images and scripts of a theme,
+	// served or copied as they are.
+	staticDir = "static"
+)
+
+var pages = []string{PageIndex, PageArchive, PageAuthor, PagePost}
+
+// Theme is a parsed set of page templates and their static files.
+type Theme struct {
+	pages  map[string]*template.Template
+	static fs.FS
+}
+
+// Themes lists the themes shipped with the binary.
+func Themes() []string {
+	entries, _ := themes.ReadDir("themes")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// EmbeddedTheme returns the files of a theme shipped with the binary.
+func EmbeddedTheme(name string) (fs.FS, error) {
+	if !slices.Contains(Themes(), name) {
+		return nil, fmt.Errorf("unknown theme %q, available: %s", name,
+			strings.Join(Themes(), ","))
+	}
+	return fs.Sub(themes, "themes/"+name)
+}
+
+// OpenTheme loads the theme shipped with the binary under name, with the
+// files in dir, when set, replacing those of the same path.
+func OpenTheme(name string, dir string) (*Theme, error) {
+	files, err := EmbeddedTheme(name)
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("theme directory: %w", err)
+		}
+		files = Overlay(files, os.DirFS(dir))
+	}
+	return LoadTheme(files)
+}
+
+// LoadTheme parses the pages of the theme in fsys.
+func LoadTheme(fsys fs.FS) (*Theme, error) {
+	t := &Theme{pages: map[string]*template.Template{}}
+	for _, page := range pages {
+		tmpl, err := template.New(layoutFile).Funcs(funcs).
+			ParseFS(fsys, layoutFile, partialsFile, page)
+		if err != nil {
+			return nil, fmt.Errorf("parse theme page %s: %w", page, err)
+		}
+		t.pages[page] = tmpl
+	}
+	static, err := fs.Sub(fsys, staticDir)
+	if err != nil {
+		return nil, err
+	}
+	t.static = static
+	return t, nil
+}
+
+// Render writes page with data.
+func (t *Theme) Render(w io.Writer, page string, data *Page) error {
+	tmpl, ok := t.pages[page]
+	if !ok {
+		return fmt.Errorf("theme has no page %s", page)
+	}
+	return tmpl.ExecuteTemplate(w, layoutFile, data)
+}
+
+// Static returns the static files of the theme.
+func (t *Theme) Static() fs.FS {
+	return t.static
+}
+
+var funcs = template.FuncMap{
+	"date": func(t time.Time) string {
+		return t.UTC().Format("January 2, 2006")
+	},
+	"isoDate": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+	"paragraphs": paragraphs,
+}
+
+// paragraphs renders plain text content as HTML paragraphs, split at blank
+// lines. Content is escaped, never trusted as HTML.
+func paragraphs(content string) template.HTML {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	var b strings.Builder
+	for _, para := range strings.Split(content, "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		lines := strings.Split(para, "\n")
+		for i, line := range lines {
+			lines[i] = template.HTMLEscapeString(line)
+		}
+		b.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	}
+	return template.HTML(b.String())
+}
@@ -0,0 +1,127 @@
+package site
+
+import (
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// summaryLength is the number of characters of content shown in lists.
+const summaryLength = 280
+
+// Site holds the settings every page shares.
+type Site struct {
+	Title string
+	// BaseURL is prepended to every link. Feeds need an absolute URL, pages
+	// work with a path such as "/" or "/blog/".
+	BaseURL string
+}
+
+// URL returns the link to path, which is relative to the site root.
+func (s *Site) URL(path string) string {
+	return strings.TrimRight(s.BaseURL, "/") + "/" + path
+}
+
+// Paths of the pages, relative to the site root. Every page is a directory
+// with an index.html, so the same links work in the static export and the
+// HTML frontend.
+func postPath(id uuid.UUID) string { return "posts/" + id.String() + "/" }
+
+func authorPath(username string) string { return "authors/" + username + "/" }
+
+const archivePath = "archive/"
+
+// pagePath returns the path of page n of the list at base.
+func pagePath(base string, n int) string {
+	if n <= 1 {
+		return base
+	}
+	return base + "page/" + strconv.Itoa(n) + "/"
+}
+
+// Page is the data a theme page is rendered with. List pages set Posts and
+// Pagination, author pages Author as well, post pages Post.
+type Page struct {
+	Site       *Site
+	Posts      []*PostView
+	Post       *PostView
+	Author     *AuthorView
+	Pagination *Pagination
+}
+
+type PostView struct {
+	ID        uuid.UUID
+	Title     string
+	Content   string
+	Summary   string
+	URL       string
+	Published time.Time
+	Updated   time.Time
+	// Edited is set when the post changed after it was published.
+	Edited bool
+	Author *AuthorView
+}
+
+type AuthorView struct {
+	Username string
+	URL      string
+}
+
+type Pagination struct {
+	Number int
+	Total  int
+	// Prev and Next link to the neighbouring pages, empty on the first and
+	// the last page.
+	Prev string
+	Next string
+}
+
+func newPostView(site *Site, p *model.Post) *PostView {
+	v := &PostView{
+		ID:        p.ID,
+		Title:     p.Title,
+		Content:   p.Content,
+		Summary:   summarize(p.Content),
+		URL:       site.URL(postPath(p.ID)),
+		Published: p.CreatedAt,
+		Updated:   p.UpdatedAt,
+		Edited:    p.UpdatedAt.Sub(p.CreatedAt) > time.Minute,
+	}
+	if p.User != nil {
+		v.Author = newAuthorView(site, p.User)
+	}
+	return v
+}
+
+func newAuthorView(site *Site, u *model.User) *AuthorView {
+	return &AuthorView{Username: u.Username,
+		URL: site.URL(authorPath(u.Username))}
+}
+
+func newPagination(site *Site, base string, number int,
+	total int) *Pagination {
+	p := &Pagination{Number: number, Total: max(total, 1)}
+	if number > 1 {
+		p.Prev = site.URL(pagePath(base, number-1))
+	}
+	if number < total {
+		p.Next = site.URL(pagePath(base, number+1))
+	}
+	return p
+}
+
+// summarize returns the start of content on one line, cut at a word.
+func summarize(content string) string {
+	text := strings.Join(strings.Fields(content), " ")
+	if utf8.RuneCountInString(text) <= summaryLength {
+		return text
+	}
+	cut := string([]rune(text)[:summaryLength])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
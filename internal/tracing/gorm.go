@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"regexp"
+	"strings"
+)
+
+const spanKey = "tracing:span"
+
+// GormPlugin records a client span for every GORM operation, as a child of
+// the span in the statement context. Queries are recorded with their bind
+// placeholders and with literals replaced by "?", never with values.
+type GormPlugin struct {
+	tracer trace.Tracer
+}
+
+func NewGormPlugin() *GormPlugin {
+	return &GormPlugin{tracer: Tracer("gorm")}
+}
+
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", p.before),
+		cb.Create().After("gorm:create").Register("tracing:after_create", p.after("INSERT")),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", p.before),
+		cb.Query().After("gorm:query").Register("tracing:after_query", p.after("SELECT")),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", p.before),
+		cb.Update().After("gorm:update").Register("tracing:after_update", p.after("UPDATE")),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", p.before),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", p.after("DELETE")),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", p.before),
+		cb.Row().After("gorm:row").Register("tracing:after_row", p.after("SELECT")),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", p.before),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", p.after("RAW")),
+	)
+}
+
+func (p *GormPlugin) before(db *gorm.DB) {
+	_, span := p.tracer.Start(db.Statement.Context, "db",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNamePostgreSQL))
+	db.InstanceSet(spanKey, span)
+}
+
+func (p *GormPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(spanKey)
+		if !ok {
+			return
+		}
+		span, ok := v.(trace.Span)
+		if !ok {
+			return
+		}
+
+		name := operation
+		if table := db.Statement.Table; table != "" {
+			name += " " + table
+			span.SetAttributes(semconv.DBCollectionName(table))
+		}
+		span.SetName(name)
+		span.SetAttributes(
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(SanitizeSQL(db.Statement.SQL.String())),
+			semconv.DBResponseReturnedRows(int(db.Statement.RowsAffected)),
+		)
+
+		var err error
+		if !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			err = db.Error
+		}
+		End(span, err)
+	}
+}
+
+var (
+	stringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numericLiteral = regexp.MustCompile(`(^|[^\w$."])(-?\d+(?:\.\d+)?)\b`)
+	whitespace     = regexp.MustCompile(`\s+`)
+)
+
+// SanitizeSQL replaces string and numeric literals with "?" so statements
+// built with inlined values, mostly raw SQL, do not leak data into traces.
+// Bind placeholders such as $1 and quoted identifiers are kept.
+func SanitizeSQL(sql string) string {
+	sql = stringLiteral.ReplaceAllString(sql, "?")
+	sql = numericLiteral.ReplaceAllString(sql, "${1}?")
+	return strings.TrimSpace(whitespace.ReplaceAllString(sql, " "))
+}
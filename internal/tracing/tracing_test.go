@@ -0,0 +1,148 @@
+package tracing
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// recordSpans installs a tracer provider that keeps finished spans in memory
+// for the duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func attributeValue(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestMiddleware_ContinuesIncomingTrace(t *testing.T) {
+	recorder := recordSpans(t)
+	_, err := Setup(context.Background(), config.TracingConfig{Exporter: config.TracingNone})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("traceparent",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(w, req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /users/:id", span.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736",
+		span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.Equal(t, "/users/:id", attributeValue(span, semconv.HTTPRouteKey))
+	assert.Equal(t, "500", attributeValue(span, semconv.HTTPResponseStatusCodeKey))
+}
+
+func TestGormPlugin_RecordsSanitizedQuery(t *testing.T) {
+	recorder := recordSpans(t)
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewGormPlugin()))
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	var users []*model.User
+	db.WithContext(ctx).Where("username = ?", "alice").Find(&users)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	query := spans[0]
+	assert.Equal(t, "SELECT users", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Equal(t, `SELECT * FROM "users" WHERE username = $1`,
+		attributeValue(query, semconv.DBQueryTextKey))
+}
+
+func TestSanitizeSQL(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "placeholders are kept",
+			sql:  `SELECT * FROM "users" WHERE "users"."id" = $1 LIMIT $2`,
+			want: `SELECT * FROM "users" WHERE "users"."id" = $1 LIMIT $2`,
+		},
+		{
+			name: "string literals",
+			sql:  `SELECT * FROM users WHERE email = 'alice@example.com' AND name = 'O''Brien'`,
+			want: `SELECT * FROM users WHERE email = ? AND name = ?`,
+		},
+		{
+			name: "numeric literals",
+			sql:  "SELECT version FROM schema_migrations LIMIT 1 OFFSET -2.5",
+			want: "SELECT version FROM schema_migrations LIMIT ? OFFSET ?",
+		},
+		{
+			name: "whitespace is collapsed",
+			sql:  "SELECT id\n\tFROM posts",
+			want: "SELECT id FROM posts",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, SanitizeSQL(test.sql))
+		})
+	}
+}
+
+func TestSetup_FileExporter(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	path := filepath.Join(t.TempDir(), "traces.json")
+
+	shutdown, err := Setup(context.Background(), config.TracingConfig{
+		Exporter:    config.TracingFile,
+		File:        path,
+		ServiceName: "blog-api-test",
+		SampleRatio: 1,
+	})
+	require.NoError(t, err)
+	_, span := Tracer("test").Start(context.Background(), "work")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"work"`)
+	assert.Contains(t, string(data), "blog-api-test")
+}
@@ -0,0 +1,113 @@
+package transfer
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// SchemaVersion is the version of the export format written by Export.
+// Import accepts every version from MinSchemaVersion up to it; version 1
+// held only users and posts.
+const (
+	SchemaVersion    = 2
+	MinSchemaVersion = 1
+)
+
+const (
+	RecordTypeHeader          = "header"
+	RecordTypeUser            = "user"
+	RecordTypePost            = "post"
+	RecordTypeFollow          = "follow"
+	RecordTypeReaction        = "reaction"
+	RecordTypeReadingList     = "reading_list"
+	RecordTypeReadingListItem = "reading_list_item"
+	RecordTypePostView        = "post_view"
+)
+
+// Omitted lists the tables an export leaves out, so a restore knows what it
+// has to set up again. API keys are credentials and are issued anew; the
+// Markdown and WordPress mappings only track earlier imports.
+var Omitted = []string{"api_keys", "markdown_posts", "wordpress_mappings"}
+
+// Record is a single line of an export. The first line of every export is a
+// header record, every following line carries one entity in Data. Entities
+// come after the ones they reference.
+type Record struct {
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schema_version,omitempty"`
+	ExportedAt    *time.Time      `json:"exported_at,omitempty"`
+	Omitted       []string        `json:"omitted,omitempty"`
+	Data          json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
+
+type UserRecord struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PostRecord struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type FollowRecord struct {
+	FollowerID uuid.UUID `json:"follower_id"`
+	FolloweeID uuid.UUID `json:"followee_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ReactionRecord struct {
+	PostID    uuid.UUID `json:"post_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ReadingListRecord struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Name       string    `json:"name"`
+	IsDefault  bool      `json:"is_default"`
+	ShareToken *string   `json:"share_token,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ReadingListItemRecord struct {
+	ListID    uuid.UUID `json:"list_id"`
+	PostID    uuid.UUID `json:"post_id"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PostViewRecord is the number of views of a post on one UTC day.
+type PostViewRecord struct {
+	PostID uuid.UUID `json:"post_id"`
+	Day    time.Time `json:"day"`
+	Views  int64     `json:"views"`
+}
+
+type ImportReport struct {
+	Users            int            `json:"users"`
+	Posts            int            `json:"posts"`
+	Follows          int            `json:"follows"`
+	Reactions        int            `json:"reactions"`
+	ReadingLists     int            `json:"reading_lists"`
+	ReadingListItems int            `json:"reading_list_items"`
+	PostViews        int            `json:"post_views"`
+	Errors           []*RecordError `json:"errors"`
+}
+
+type RecordError struct {
+	Line  int    `json:"line"`
+	Type  string `json:"type,omitempty"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,94 @@
+package transfer
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/middleware"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SchemaVersionHeader carries the schema version of an export, so clients
+// can check it before reading the stream.
+const SchemaVersionHeader = "X-Schema-Version"
+
+type Handler struct {
+	Service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{Service: service}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/export", h.export)
+	r.POST("/import", h.importData)
+}
+
+// export godoc
+// @Summary Export all content
+// @Description Streams a header record followed by all users, posts,
+// @Description follows, reactions, reading lists with their items and daily
+// @Description post views as newline delimited JSON, one record per line.
+// @Description The header lists the tables left out in omitted: API keys,
+// @Description which are credentials and have to be issued again, and the
+// @Description mappings of Markdown and WordPress imports.
+// @Tags admin
+// @Produce application/x-ndjson
+// @Success 200 {object} Record
+// @Header 200 {integer} X-Schema-Version "Schema version of the export"
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Router /admin/export [get]
+// @Security ApiKeyAuth
+func (h *Handler) export(c *gin.Context) {
+	middleware.ClearDeadlines(c)
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header(SchemaVersionHeader, strconv.Itoa(SchemaVersion))
+	c.Header("Content-Disposition", fmt.Sprintf(
+		`attachment; filename="blog-export-%s.ndjson"`,
+		time.Now().UTC().Format("20060102T150405Z")))
+
+	if err := h.Service.Export(c.Request.Context(), c.Writer); err != nil {
+		logging.FromContext(c.Request.Context()).Error("export failed",
+			"error", err)
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+	}
+}
+
+// importData godoc
+// @Summary Import content
+// @Description Reads an export written by GET /admin/export and upserts
+// @Description every record by ID, keeping IDs and timestamps. Records that
+// @Description cannot be stored are listed in the report and skipped. The
+// @Description reaction counts are rebuilt after reactions were imported.
+// @Description Exports of schema version 1 and 2 are accepted.
+// @Tags admin
+// @Accept application/x-ndjson
+// @Produce json
+// @Param export body string true "Export in NDJSON format"
+// @Success 200 {object} ImportReport
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Router /admin/import [post]
+// @Security ApiKeyAuth
+func (h *Handler) importData(c *gin.Context) {
+	middleware.ClearDeadlines(c)
+	report, err := h.Service.Import(c.Request.Context(), c.Request.Body)
+	if err != nil {
+		status := apperrors.StatusCode(err)
+		if status == http.StatusInternalServerError {
+			logging.FromContext(c.Request.Context()).Error("import failed",
+				"error", err)
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,138 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func setupRouter(t *testing.T) (*gin.Engine, *MockService) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockService := NewMockService(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewHandler(mockService).RegisterRoutes(router.Group("/admin"))
+	return router, mockService
+}
+
+func TestHandler_Export(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantType      string
+		wantBody      string
+	}{
+		{
+			name: "success",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().Export(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, w io.Writer) error {
+						_, err := io.WriteString(w,
+							`{"type":"header","schema_version":1}`+"\n")
+						return err
+					})
+			},
+			wantStatus: http.StatusOK,
+			wantType:   "application/x-ndjson",
+			wantBody:   `{"type":"header","schema_version":1}`,
+		},
+		{
+			name: "fails before writing",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().Export(gomock.Any(), gomock.Any()).
+					Return(errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantType:   "application/json",
+			wantBody:   "db error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupRouter(t)
+			test.mockBehaviour(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/admin/export", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), test.wantType)
+			assert.Equal(t, "2", w.Header().Get(SchemaVersionHeader))
+			assert.Contains(t, w.Body.String(), test.wantBody)
+		})
+	}
+}
+
+func TestHandler_Import(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name: "success",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().Import(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context,
+						r io.Reader) (*ImportReport, error) {
+						body, _ := io.ReadAll(r)
+						assert.Equal(t, "export", string(body))
+						return &ImportReport{Users: 2, Posts: 3,
+							Errors: []*RecordError{{Line: 4,
+								Error: "invalid json"}}}, nil
+					})
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"users":2,"posts":3,"follows":0,"reactions":0,` +
+				`"reading_lists":0,"reading_list_items":0,"post_views":0,"errors":[` +
+				`{"line":4,"error":"invalid json"}]}`,
+		},
+		{
+			name: "invalid header",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().Import(gomock.Any(), gomock.Any()).
+					Return(nil, apperrors.NewInvalidInputError(
+						"unsupported schema version 3"))
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"unsupported schema version 3"}`,
+		},
+		{
+			name: "read failure",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().Import(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("read import at line 1: EOF"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":"read import at line 1: EOF"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupRouter(t)
+			test.mockBehaviour(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/admin/import",
+				strings.NewReader("export"))
+			req.Header.Set("Content-Type", "application/x-ndjson")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.JSONEq(t, test.wantBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package transfer
+
+import (
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockgen -source=repository.go -destination=repository_mock.go -package=transfer
+
+type Repository interface {
+	// Snapshot runs fn with a repository whose queries all read the same
+	// snapshot of the database in one read-only transaction.
+	Snapshot(ctx context.Context, fn func(repo Repository) error) error
+	FindUsersAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.User, error)
+	FindPostsAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.Post, error)
+	// The Find*After methods of entities with a composite key read the rows
+	// after the given one, or from the start when it is nil.
+	FindFollowsAfter(ctx context.Context, after *model.Follow, limit int) ([]*model.Follow, error)
+	FindReactionsAfter(ctx context.Context, after *model.Reaction, limit int) ([]*model.Reaction, error)
+	FindReadingListsAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.ReadingList, error)
+	FindReadingListItemsAfter(ctx context.Context, after *model.ReadingListItem, limit int) ([]*model.ReadingListItem, error)
+	FindPostViewsAfter(ctx context.Context, after *model.PostView, limit int) ([]*model.PostView, error)
+	UpsertUser(ctx context.Context, user *model.User) error
+	UpsertPost(ctx context.Context, post *model.Post) error
+	UpsertFollow(ctx context.Context, follow *model.Follow) error
+	UpsertReaction(ctx context.Context, reaction *model.Reaction) error
+	UpsertReadingList(ctx context.Context, list *model.ReadingList) error
+	UpsertReadingListItem(ctx context.Context, item *model.ReadingListItem) error
+	UpsertPostView(ctx context.Context, view *model.PostView) error
+	// RecountReactions rebuilds the reaction counts of every post from the
+	// reactions.
+	RecountReactions(ctx context.Context) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) Snapshot(ctx context.Context, fn func(repo Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repository{db: tx})
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
+
+func (r *repository) FindUsersAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.User, error) {
+	var users []*model.User
+	err := r.db.WithContext(ctx).Where("id > ?", cursor).Order("id").Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+func (r *repository) FindPostsAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.Post, error) {
+	var posts []*model.Post
+	err := r.db.WithContext(ctx).Where("id > ?", cursor).Order("id").Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *repository) FindFollowsAfter(ctx context.Context, after *model.Follow, limit int) ([]*model.Follow, error) {
+	db := r.db.WithContext(ctx)
+	if after != nil {
+		db = db.Where("(follower_id, followee_id) > (?, ?)",
+			after.FollowerID, after.FolloweeID)
+	}
+	var follows []*model.Follow
+	err := db.Order("follower_id, followee_id").Limit(limit).Find(&follows).Error
+	return follows, err
+}
+
+func (r *repository) FindReactionsAfter(ctx context.Context, after *model.Reaction, limit int) ([]*model.Reaction, error) {
+	db := r.db.WithContext(ctx)
+	if after != nil {
+		db = db.Where("(post_id, user_id, kind) > (?, ?, ?)",
+			after.PostID, after.UserID, after.Kind)
+	}
+	var reactions []*model.Reaction
+	err := db.Order("post_id, user_id, kind").Limit(limit).Find(&reactions).Error
+	return reactions, err
+}
+
+func (r *repository) FindReadingListsAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.ReadingList, error) {
+	var lists []*model.ReadingList
+	err := r.db.WithContext(ctx).Where("id > ?", cursor).Order("id").Limit(limit).
+		Find(&lists).Error
+	return lists, err
+}
+
+func (r *repository) FindReadingListItemsAfter(ctx context.Context, after *model.ReadingListItem, limit int) ([]*model.ReadingListItem, error) {
+	db := r.db.WithContext(ctx)
+	if after != nil {
+		db = db.Where("(list_id, post_id) > (?, ?)", after.ListID, after.PostID)
+	}
+	var items []*model.ReadingListItem
+	err := db.Order("list_id, post_id").Limit(limit).Find(&items).Error
+	return items, err
+}
+
+func (r *repository) FindPostViewsAfter(ctx context.Context, after *model.PostView, limit int) ([]*model.PostView, error) {
+	db := r.db.WithContext(ctx)
+	if after != nil {
+		db = db.Where("(post_id, day) > (?, CAST(? AS date))",
+			after.PostID, after.Day)
+	}
+	var views []*model.PostView
+	err := db.Order("post_id, day").Limit(limit).Find(&views).Error
+	return views, err
+}
+
+// UpsertUser inserts the user or overwrites the row with the same ID. The
+// columns are listed explicitly so created_at is kept from the export.
+func (r *repository) UpsertUser(ctx context.Context, user *model.User) error {
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns(
+			[]string{"username", "email", "created_at"}),
+	}).Create(user).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) UpsertPost(ctx context.Context, post *model.Post) error {
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"title", "content", "user_id", "created_at", "updated_at"}),
+	}).Create(post).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) UpsertFollow(ctx context.Context, follow *model.Follow) error {
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "follower_id"}, {Name: "followee_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"created_at"}),
+	}).Create(follow).Error
+	return database.TranslateError(err)
+}
+
+// UpsertReaction inserts the reaction or updates the time of the existing
+// one. Only inserts change the reaction counts.
+func (r *repository) UpsertReaction(ctx context.Context, reaction *model.Reaction) error {
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "post_id"}, {Name: "user_id"}, {Name: "kind"}},
+		DoUpdates: clause.AssignmentColumns([]string{"created_at"}),
+	}).Create(reaction).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) UpsertReadingList(ctx context.Context, list *model.ReadingList) error {
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "name",
+			"is_default", "share_token", "created_at", "updated_at"}),
+	}).Create(list).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) UpsertReadingListItem(ctx context.Context, item *model.ReadingListItem) error {
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "list_id"}, {Name: "post_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"position", "created_at"}),
+	}).Create(item).Error
+	return database.TranslateError(err)
+}
+
+// UpsertPostView sets the views of the post on the day, replacing rather
+// than adding to what is stored so importing twice counts them once.
+func (r *repository) UpsertPostView(ctx context.Context, view *model.PostView) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}, {Name: "day"}},
+		DoUpdates: clause.AssignmentColumns([]string{"views"}),
+	}).Create(view).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) RecountReactions(ctx context.Context) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Hold off new reactions so their triggers do not count them twice.
+		if err := tx.Exec("LOCK TABLE reactions IN SHARE MODE").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM post_reaction_counts").Error; err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO post_reaction_counts (post_id, kind, count)
+			SELECT post_id, kind, COUNT(*) FROM reactions
+			GROUP BY post_id, kind`).Error
+	})
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
@@ -0,0 +1,268 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package transfer is a generated GoMock package.
+package transfer
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	model "github.com/pandahawk/blog-api/internal/shared/model"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindFollowsAfter mocks base method.
+func (m *MockRepository) FindFollowsAfter(ctx context.Context, after *model.Follow, limit int) ([]*model.Follow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowsAfter", ctx, after, limit)
+	ret0, _ := ret[0].([]*model.Follow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFollowsAfter indicates an expected call of FindFollowsAfter.
+func (mr *MockRepositoryMockRecorder) FindFollowsAfter(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowsAfter", reflect.TypeOf((*MockRepository)(nil).FindFollowsAfter), ctx, after, limit)
+}
+
+// FindPostViewsAfter mocks base method.
+func (m *MockRepository) FindPostViewsAfter(ctx context.Context, after *model.PostView, limit int) ([]*model.PostView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPostViewsAfter", ctx, after, limit)
+	ret0, _ := ret[0].([]*model.PostView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPostViewsAfter indicates an expected call of FindPostViewsAfter.
+func (mr *MockRepositoryMockRecorder) FindPostViewsAfter(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPostViewsAfter", reflect.TypeOf((*MockRepository)(nil).FindPostViewsAfter), ctx, after, limit)
+}
+
+// FindPostsAfter mocks base method.
+func (m *MockRepository) FindPostsAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPostsAfter", ctx, cursor, limit)
+	ret0, _ := ret[0].([]*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPostsAfter indicates an expected call of FindPostsAfter.
+func (mr *MockRepositoryMockRecorder) FindPostsAfter(ctx, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPostsAfter", reflect.TypeOf((*MockRepository)(nil).FindPostsAfter), ctx, cursor, limit)
+}
+
+// FindReactionsAfter mocks base method.
+func (m *MockRepository) FindReactionsAfter(ctx context.Context, after *model.Reaction, limit int) ([]*model.Reaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReactionsAfter", ctx, after, limit)
+	ret0, _ := ret[0].([]*model.Reaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReactionsAfter indicates an expected call of FindReactionsAfter.
+func (mr *MockRepositoryMockRecorder) FindReactionsAfter(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReactionsAfter", reflect.TypeOf((*MockRepository)(nil).FindReactionsAfter), ctx, after, limit)
+}
+
+// FindReadingListItemsAfter mocks base method.
+func (m *MockRepository) FindReadingListItemsAfter(ctx context.Context, after *model.ReadingListItem, limit int) ([]*model.ReadingListItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReadingListItemsAfter", ctx, after, limit)
+	ret0, _ := ret[0].([]*model.ReadingListItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReadingListItemsAfter indicates an expected call of FindReadingListItemsAfter.
+func (mr *MockRepositoryMockRecorder) FindReadingListItemsAfter(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReadingListItemsAfter", reflect.TypeOf((*MockRepository)(nil).FindReadingListItemsAfter), ctx, after, limit)
+}
+
+// FindReadingListsAfter mocks base method.
+func (m *MockRepository) FindReadingListsAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.ReadingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReadingListsAfter", ctx, cursor, limit)
+	ret0, _ := ret[0].([]*model.ReadingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReadingListsAfter indicates an expected call of FindReadingListsAfter.
+func (mr *MockRepositoryMockRecorder) FindReadingListsAfter(ctx, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReadingListsAfter", reflect.TypeOf((*MockRepository)(nil).FindReadingListsAfter), ctx, cursor, limit)
+}
+
+// FindUsersAfter mocks base method.
+func (m *MockRepository) FindUsersAfter(ctx context.Context, cursor uuid.UUID, limit int) ([]*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUsersAfter", ctx, cursor, limit)
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUsersAfter indicates an expected call of FindUsersAfter.
+func (mr *MockRepositoryMockRecorder) FindUsersAfter(ctx, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUsersAfter", reflect.TypeOf((*MockRepository)(nil).FindUsersAfter), ctx, cursor, limit)
+}
+
+// RecountReactions mocks base method.
+func (m *MockRepository) RecountReactions(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecountReactions", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecountReactions indicates an expected call of RecountReactions.
+func (mr *MockRepositoryMockRecorder) RecountReactions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecountReactions", reflect.TypeOf((*MockRepository)(nil).RecountReactions), ctx)
+}
+
+// Snapshot mocks base method.
+func (m *MockRepository) Snapshot(ctx context.Context, fn func(Repository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockRepositoryMockRecorder) Snapshot(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockRepository)(nil).Snapshot), ctx, fn)
+}
+
+// UpsertFollow mocks base method.
+func (m *MockRepository) UpsertFollow(ctx context.Context, follow *model.Follow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertFollow", ctx, follow)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertFollow indicates an expected call of UpsertFollow.
+func (mr *MockRepositoryMockRecorder) UpsertFollow(ctx, follow interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertFollow", reflect.TypeOf((*MockRepository)(nil).UpsertFollow), ctx, follow)
+}
+
+// UpsertPost mocks base method.
+func (m *MockRepository) UpsertPost(ctx context.Context, post *model.Post) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPost", ctx, post)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertPost indicates an expected call of UpsertPost.
+func (mr *MockRepositoryMockRecorder) UpsertPost(ctx, post interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPost", reflect.TypeOf((*MockRepository)(nil).UpsertPost), ctx, post)
+}
+
+// UpsertPostView mocks base method.
+func (m *MockRepository) UpsertPostView(ctx context.Context, view *model.PostView) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPostView", ctx, view)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertPostView indicates an expected call of UpsertPostView.
+func (mr *MockRepositoryMockRecorder) UpsertPostView(ctx, view interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPostView", reflect.TypeOf((*MockRepository)(nil).UpsertPostView), ctx, view)
+}
+
+// UpsertReaction mocks base method.
+func (m *MockRepository) UpsertReaction(ctx context.Context, reaction *model.Reaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertReaction", ctx, reaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertReaction indicates an expected call of UpsertReaction.
+func (mr *MockRepositoryMockRecorder) UpsertReaction(ctx, reaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertReaction", reflect.TypeOf((*MockRepository)(nil).UpsertReaction), ctx, reaction)
+}
+
+// UpsertReadingList mocks base method.
+func (m *MockRepository) UpsertReadingList(ctx context.Context, list *model.ReadingList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertReadingList", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertReadingList indicates an expected call of UpsertReadingList.
+func (mr *MockRepositoryMockRecorder) UpsertReadingList(ctx, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertReadingList", reflect.TypeOf((*MockRepository)(nil).UpsertReadingList), ctx, list)
+}
+
+// UpsertReadingListItem mocks base method.
+func (m *MockRepository) UpsertReadingListItem(ctx context.Context, item *model.ReadingListItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertReadingListItem", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertReadingListItem indicates an expected call of UpsertReadingListItem.
+func (mr *MockRepositoryMockRecorder) UpsertReadingListItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertReadingListItem", reflect.TypeOf((*MockRepository)(nil).UpsertReadingListItem), ctx, item)
+}
+
+// UpsertUser mocks base method.
+func (m *MockRepository) UpsertUser(ctx context.Context, user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUser", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertUser indicates an expected call of UpsertUser.
+func (mr *MockRepositoryMockRecorder) UpsertUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUser", reflect.TypeOf((*MockRepository)(nil).UpsertUser), ctx, user)
+}
@@ -0,0 +1,402 @@
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"io"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock.go -package=transfer
+
+const (
+	batchSize = 500
+	// maxLineSize bounds a single NDJSON line, which has to hold the
+	// longest post content.
+	maxLineSize = 16 << 20
+)
+
+type Service interface {
+	Export(ctx context.Context, w io.Writer) error
+	Import(ctx context.Context, r io.Reader) (*ImportReport, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func writeRecord(enc *json.Encoder, recordType string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return enc.Encode(&Record{Type: recordType, Data: raw})
+}
+
+// exportAll writes a record for every row find returns. Rows are read in
+// batches, find gets the last row of the previous batch, or nil for the
+// first one, so memory use does not grow with the size of the database.
+func exportAll[T any](enc *json.Encoder, recordType string,
+	find func(after *T) ([]*T, error), record func(row *T) any) error {
+	var after *T
+	for {
+		rows, err := find(after)
+		if err != nil {
+			return fmt.Errorf("export %ss: %w", recordType, err)
+		}
+		for _, row := range rows {
+			if err := writeRecord(enc, recordType, record(row)); err != nil {
+				return err
+			}
+		}
+		if len(rows) < batchSize {
+			return nil
+		}
+		after = rows[len(rows)-1]
+	}
+}
+
+// Export writes a header record followed by every entity, each type after
+// the types it references. All tables are read from one snapshot, so the
+// references hold even while the blog is written to.
+func (s *service) Export(ctx context.Context, w io.Writer) error {
+	return s.repo.Snapshot(ctx, func(repo Repository) error {
+		return (&service{repo: repo}).export(ctx, w)
+	})
+}
+
+func (s *service) export(ctx context.Context, w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+
+	now := time.Now().UTC()
+	if err := enc.Encode(&Record{Type: RecordTypeHeader,
+		SchemaVersion: SchemaVersion, ExportedAt: &now,
+		Omitted: Omitted}); err != nil {
+		return err
+	}
+
+	if err := exportAll(enc, RecordTypeUser,
+		func(after *model.User) ([]*model.User, error) {
+			cursor := uuid.Nil
+			if after != nil {
+				cursor = after.ID
+			}
+			return s.repo.FindUsersAfter(ctx, cursor, batchSize)
+		},
+		func(u *model.User) any {
+			return &UserRecord{
+				ID:        u.ID,
+				Username:  u.Username,
+				Email:     u.Email,
+				CreatedAt: u.CreatedAt,
+			}
+		}); err != nil {
+		return err
+	}
+	if err := exportAll(enc, RecordTypePost,
+		func(after *model.Post) ([]*model.Post, error) {
+			cursor := uuid.Nil
+			if after != nil {
+				cursor = after.ID
+			}
+			return s.repo.FindPostsAfter(ctx, cursor, batchSize)
+		},
+		func(p *model.Post) any {
+			return &PostRecord{
+				ID:        p.ID,
+				Title:     p.Title,
+				Content:   p.Content,
+				UserID:    p.UserID,
+				CreatedAt: p.CreatedAt,
+				UpdatedAt: p.UpdatedAt,
+			}
+		}); err != nil {
+		return err
+	}
+	if err := exportAll(enc, RecordTypeFollow,
+		func(after *model.Follow) ([]*model.Follow, error) {
+			return s.repo.FindFollowsAfter(ctx, after, batchSize)
+		},
+		func(f *model.Follow) any {
+			return &FollowRecord{
+				FollowerID: f.FollowerID,
+				FolloweeID: f.FolloweeID,
+				CreatedAt:  f.CreatedAt,
+			}
+		}); err != nil {
+		return err
+	}
+	if err := exportAll(enc, RecordTypeReaction,
+		func(after *model.Reaction) ([]*model.Reaction, error) {
+			return s.repo.FindReactionsAfter(ctx, after, batchSize)
+		},
+		func(r *model.Reaction) any {
+			return &ReactionRecord{
+				PostID:    r.PostID,
+				UserID:    r.UserID,
+				Kind:      r.Kind,
+				CreatedAt: r.CreatedAt,
+			}
+		}); err != nil {
+		return err
+	}
+	if err := exportAll(enc, RecordTypeReadingList,
+		func(after *model.ReadingList) ([]*model.ReadingList, error) {
+			cursor := uuid.Nil
+			if after != nil {
+				cursor = after.ID
+			}
+			return s.repo.FindReadingListsAfter(ctx, cursor, batchSize)
+		},
+		func(l *model.ReadingList) any {
+			return &ReadingListRecord{
+				ID:         l.ID,
+				UserID:     l.UserID,
+				Name:       l.Name,
+				IsDefault:  l.IsDefault,
+				ShareToken: l.ShareToken,
+				CreatedAt:  l.CreatedAt,
+				UpdatedAt:  l.UpdatedAt,
+			}
+		}); err != nil {
+		return err
+	}
+	if err := exportAll(enc, RecordTypeReadingListItem,
+		func(after *model.ReadingListItem) ([]*model.ReadingListItem, error) {
+			return s.repo.FindReadingListItemsAfter(ctx, after, batchSize)
+		},
+		func(i *model.ReadingListItem) any {
+			return &ReadingListItemRecord{
+				ListID:    i.ListID,
+				PostID:    i.PostID,
+				Position:  i.Position,
+				CreatedAt: i.CreatedAt,
+			}
+		}); err != nil {
+		return err
+	}
+	if err := exportAll(enc, RecordTypePostView,
+		func(after *model.PostView) ([]*model.PostView, error) {
+			return s.repo.FindPostViewsAfter(ctx, after, batchSize)
+		},
+		func(v *model.PostView) any {
+			return &PostViewRecord{PostID: v.PostID, Day: v.Day, Views: v.Views}
+		}); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+// Import upserts every record by ID, keeping the IDs and timestamps from the
+// export. A record that cannot be stored is reported and skipped; only an
+// unreadable stream, a missing or unsupported header or a canceled ctx aborts
+// the import. The reaction counts are rebuilt once reactions were imported.
+func (s *service) Import(ctx context.Context, r io.Reader) (*ImportReport, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	report := &ImportReport{Errors: []*RecordError{}}
+	line := 0
+	headerSeen := false
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			if !headerSeen {
+				return nil, apperrors.NewInvalidInputError(
+					"first line must be an export header")
+			}
+			report.Errors = append(report.Errors,
+				&RecordError{Line: line, Error: "invalid json"})
+			continue
+		}
+
+		if !headerSeen {
+			if rec.Type != RecordTypeHeader {
+				return nil, apperrors.NewInvalidInputError(
+					"first line must be an export header")
+			}
+			if rec.SchemaVersion < MinSchemaVersion ||
+				rec.SchemaVersion > SchemaVersion {
+				return nil, apperrors.NewInvalidInputError(fmt.Sprintf(
+					"unsupported schema version %d", rec.SchemaVersion))
+			}
+			headerSeen = true
+			continue
+		}
+
+		id, err := s.importRecord(ctx, &rec)
+		if err != nil {
+			report.Errors = append(report.Errors, &RecordError{
+				Line: line, Type: rec.Type, ID: id, Error: err.Error()})
+			continue
+		}
+		switch rec.Type {
+		case RecordTypeUser:
+			report.Users++
+		case RecordTypePost:
+			report.Posts++
+		case RecordTypeFollow:
+			report.Follows++
+		case RecordTypeReaction:
+			report.Reactions++
+		case RecordTypeReadingList:
+			report.ReadingLists++
+		case RecordTypeReadingListItem:
+			report.ReadingListItems++
+		case RecordTypePostView:
+			report.PostViews++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read import at line %d: %w", line+1, err)
+	}
+	if !headerSeen {
+		return nil, apperrors.NewInvalidInputError("import is empty")
+	}
+	if report.Reactions > 0 {
+		if err := s.repo.RecountReactions(ctx); err != nil {
+			return nil, fmt.Errorf("recount reactions: %w", err)
+		}
+	}
+	return report, nil
+}
+
+func (s *service) importRecord(ctx context.Context, rec *Record) (string, error) {
+	switch rec.Type {
+	case RecordTypeUser:
+		var u UserRecord
+		if err := json.Unmarshal(rec.Data, &u); err != nil {
+			return "", apperrors.NewInvalidInputError("invalid user record")
+		}
+		if u.ID == uuid.Nil || u.Username == "" || u.Email == "" {
+			return u.ID.String(), apperrors.NewInvalidInputError(
+				"user record needs id, username and email")
+		}
+		return u.ID.String(), s.repo.UpsertUser(ctx, &model.User{
+			ID:        u.ID,
+			Username:  u.Username,
+			Email:     u.Email,
+			CreatedAt: u.CreatedAt,
+		})
+	case RecordTypePost:
+		var p PostRecord
+		if err := json.Unmarshal(rec.Data, &p); err != nil {
+			return "", apperrors.NewInvalidInputError("invalid post record")
+		}
+		if p.ID == uuid.Nil || p.UserID == uuid.Nil || p.Title == "" {
+			return p.ID.String(), apperrors.NewInvalidInputError(
+				"post record needs id, user_id and title")
+		}
+		return p.ID.String(), s.repo.UpsertPost(ctx, &model.Post{
+			ID:        p.ID,
+			Title:     p.Title,
+			Content:   p.Content,
+			UserID:    p.UserID,
+			CreatedAt: p.CreatedAt,
+			UpdatedAt: p.UpdatedAt,
+		})
+	case RecordTypeFollow:
+		var f FollowRecord
+		if err := json.Unmarshal(rec.Data, &f); err != nil {
+			return "", apperrors.NewInvalidInputError("invalid follow record")
+		}
+		id := f.FollowerID.String() + "/" + f.FolloweeID.String()
+		if f.FollowerID == uuid.Nil || f.FolloweeID == uuid.Nil {
+			return id, apperrors.NewInvalidInputError(
+				"follow record needs follower_id and followee_id")
+		}
+		return id, s.repo.UpsertFollow(ctx, &model.Follow{
+			FollowerID: f.FollowerID,
+			FolloweeID: f.FolloweeID,
+			CreatedAt:  f.CreatedAt,
+		})
+	case RecordTypeReaction:
+		var r ReactionRecord
+		if err := json.Unmarshal(rec.Data, &r); err != nil {
+			return "", apperrors.NewInvalidInputError("invalid reaction record")
+		}
+		id := r.PostID.String() + "/" + r.UserID.String() + "/" + r.Kind
+		if r.PostID == uuid.Nil || r.UserID == uuid.Nil ||
+			!model.IsReactionKind(r.Kind) {
+			return id, apperrors.NewInvalidInputError(
+				"reaction record needs post_id, user_id and a known kind")
+		}
+		return id, s.repo.UpsertReaction(ctx, &model.Reaction{
+			PostID:    r.PostID,
+			UserID:    r.UserID,
+			Kind:      r.Kind,
+			CreatedAt: r.CreatedAt,
+		})
+	case RecordTypeReadingList:
+		var l ReadingListRecord
+		if err := json.Unmarshal(rec.Data, &l); err != nil {
+			return "", apperrors.NewInvalidInputError(
+				"invalid reading list record")
+		}
+		if l.ID == uuid.Nil || l.UserID == uuid.Nil || l.Name == "" {
+			return l.ID.String(), apperrors.NewInvalidInputError(
+				"reading list record needs id, user_id and name")
+		}
+		return l.ID.String(), s.repo.UpsertReadingList(ctx, &model.ReadingList{
+			ID:         l.ID,
+			UserID:     l.UserID,
+			Name:       l.Name,
+			IsDefault:  l.IsDefault,
+			ShareToken: l.ShareToken,
+			CreatedAt:  l.CreatedAt,
+			UpdatedAt:  l.UpdatedAt,
+		})
+	case RecordTypeReadingListItem:
+		var i ReadingListItemRecord
+		if err := json.Unmarshal(rec.Data, &i); err != nil {
+			return "", apperrors.NewInvalidInputError(
+				"invalid reading list item record")
+		}
+		id := i.ListID.String() + "/" + i.PostID.String()
+		if i.ListID == uuid.Nil || i.PostID == uuid.Nil {
+			return id, apperrors.NewInvalidInputError(
+				"reading list item record needs list_id and post_id")
+		}
+		return id, s.repo.UpsertReadingListItem(ctx, &model.ReadingListItem{
+			ListID:    i.ListID,
+			PostID:    i.PostID,
+			Position:  i.Position,
+			CreatedAt: i.CreatedAt,
+		})
+	case RecordTypePostView:
+		var v PostViewRecord
+		if err := json.Unmarshal(rec.Data, &v); err != nil {
+			return "", apperrors.NewInvalidInputError("invalid post view record")
+		}
+		id := v.PostID.String() + "/" + v.Day.Format(time.DateOnly)
+		if v.PostID == uuid.Nil || v.Day.IsZero() || v.Views < 1 {
+			return id, apperrors.NewInvalidInputError(
+				"post view record needs post_id, day and positive views")
+		}
+		return id, s.repo.UpsertPostView(ctx, &model.PostView{
+			PostID: v.PostID,
+			Day:    v.Day.UTC().Truncate(24 * time.Hour),
+			Views:  v.Views,
+		})
+	}
+	return "", apperrors.NewInvalidInputError(
+		fmt.Sprintf("unknown record type %q", rec.Type))
+}
+
+func NewService(r Repository) Service {
+	return &service{repo: r}
+}
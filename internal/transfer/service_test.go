@@ -0,0 +1,259 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/shared/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setup(t *testing.T) (*MockRepository, Service) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockRepo := NewMockRepository(ctrl)
+	return mockRepo, NewService(mockRepo)
+}
+
+// expectSnapshot lets the export read through mockRepo as if it were the
+// snapshot.
+func expectSnapshot(mockRepo *MockRepository) {
+	mockRepo.EXPECT().Snapshot(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(Repository) error) error {
+			return fn(mockRepo)
+		})
+}
+
+func TestService_ExportImportRoundTrip(t *testing.T) {
+	mockRepo, service := setup(t)
+	created := time.Date(2025, 7, 1, 8, 0, 0, 0, time.UTC)
+	token := "c2hhcmVk"
+	follows := []*model.Follow{{FollowerID: testdata.Alice.ID,
+		FolloweeID: testdata.Bob.ID, CreatedAt: created}}
+	reactions := []*model.Reaction{{PostID: testdata.Post1.ID,
+		UserID: testdata.Bob.ID, Kind: model.ReactionLike, CreatedAt: created}}
+	lists := []*model.ReadingList{{ID: uuid.New(), UserID: testdata.Alice.ID,
+		Name: model.DefaultReadingListName, IsDefault: true,
+		ShareToken: &token, CreatedAt: created, UpdatedAt: created}}
+	items := []*model.ReadingListItem{{ListID: lists[0].ID,
+		PostID: testdata.Post2.ID, Position: 1, CreatedAt: created}}
+	views := []*model.PostView{{PostID: testdata.Post1.ID,
+		Day: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), Views: 42}}
+	expectSnapshot(mockRepo)
+	mockRepo.EXPECT().FindUsersAfter(gomock.Any(), uuid.Nil, batchSize).
+		Return(testdata.SampleUsers, nil)
+	mockRepo.EXPECT().FindPostsAfter(gomock.Any(), uuid.Nil, batchSize).
+		Return(testdata.SamplePosts, nil)
+	mockRepo.EXPECT().FindFollowsAfter(gomock.Any(), nil, batchSize).
+		Return(follows, nil)
+	mockRepo.EXPECT().FindReactionsAfter(gomock.Any(), nil, batchSize).
+		Return(reactions, nil)
+	mockRepo.EXPECT().FindReadingListsAfter(gomock.Any(), uuid.Nil, batchSize).
+		Return(lists, nil)
+	mockRepo.EXPECT().FindReadingListItemsAfter(gomock.Any(), nil, batchSize).
+		Return(items, nil)
+	mockRepo.EXPECT().FindPostViewsAfter(gomock.Any(), nil, batchSize).
+		Return(views, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.Export(context.Background(), &buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines,
+		1+len(testdata.SampleUsers)+len(testdata.SamplePosts)+5)
+	assert.Contains(t, lines[0], `"type":"header"`)
+	assert.Contains(t, lines[0],
+		`"omitted":["api_keys","markdown_posts","wordpress_mappings"]`)
+
+	var users []*model.User
+	var posts []*model.Post
+	mockRepo.EXPECT().UpsertUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, u *model.User) error {
+			users = append(users, u)
+			return nil
+		}).Times(len(testdata.SampleUsers))
+	mockRepo.EXPECT().UpsertPost(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, p *model.Post) error {
+			posts = append(posts, p)
+			return nil
+		}).Times(len(testdata.SamplePosts))
+	mockRepo.EXPECT().UpsertFollow(gomock.Any(), follows[0]).Return(nil)
+	mockRepo.EXPECT().UpsertReaction(gomock.Any(), reactions[0]).Return(nil)
+	mockRepo.EXPECT().UpsertReadingList(gomock.Any(), lists[0]).Return(nil)
+	mockRepo.EXPECT().UpsertReadingListItem(gomock.Any(), items[0]).Return(nil)
+	mockRepo.EXPECT().UpsertPostView(gomock.Any(), views[0]).Return(nil)
+	mockRepo.EXPECT().RecountReactions(gomock.Any()).Return(nil)
+
+	report, err := service.Import(context.Background(), &buf)
+
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, len(testdata.SampleUsers), report.Users)
+	assert.Equal(t, len(testdata.SamplePosts), report.Posts)
+	assert.Equal(t, 1, report.Follows)
+	assert.Equal(t, 1, report.Reactions)
+	assert.Equal(t, 1, report.ReadingLists)
+	assert.Equal(t, 1, report.ReadingListItems)
+	assert.Equal(t, 1, report.PostViews)
+	for i, u := range users {
+		assert.Equal(t, testdata.SampleUsers[i].ID, u.ID)
+		assert.Equal(t, testdata.SampleUsers[i].Username, u.Username)
+	}
+	for i, p := range posts {
+		assert.Equal(t, testdata.SamplePosts[i].ID, p.ID)
+		assert.Equal(t, testdata.SamplePosts[i].UserID, p.UserID)
+	}
+}
+
+func TestService_ExportBatches(t *testing.T) {
+	mockRepo, service := setup(t)
+	full := make([]*model.User, batchSize)
+	for i := range full {
+		full[i] = &model.User{ID: uuid.New()}
+	}
+	expectSnapshot(mockRepo)
+	gomock.InOrder(
+		mockRepo.EXPECT().FindUsersAfter(gomock.Any(), uuid.Nil, batchSize).Return(full, nil),
+		mockRepo.EXPECT().FindUsersAfter(gomock.Any(), full[batchSize-1].ID, batchSize).
+			Return(nil, nil),
+	)
+	mockRepo.EXPECT().FindPostsAfter(gomock.Any(), uuid.Nil, batchSize).Return(nil, nil)
+	mockRepo.EXPECT().FindFollowsAfter(gomock.Any(), nil, batchSize).Return(nil, nil)
+	mockRepo.EXPECT().FindReactionsAfter(gomock.Any(), nil, batchSize).Return(nil, nil)
+	mockRepo.EXPECT().FindReadingListsAfter(gomock.Any(), uuid.Nil, batchSize).
+		Return(nil, nil)
+	mockRepo.EXPECT().FindReadingListItemsAfter(gomock.Any(), nil, batchSize).
+		Return(nil, nil)
+	mockRepo.EXPECT().FindPostViewsAfter(gomock.Any(), nil, batchSize).
+		Return(nil, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.Export(context.Background(), &buf))
+	assert.Equal(t, 1+batchSize, strings.Count(buf.String(), "\n"))
+}
+
+func TestService_ExportSnapshotFails(t *testing.T) {
+	mockRepo, service := setup(t)
+	mockRepo.EXPECT().Snapshot(gomock.Any(), gomock.Any()).
+		Return(errors.New("connection refused"))
+
+	var buf bytes.Buffer
+	err := service.Export(context.Background(), &buf)
+
+	assert.EqualError(t, err, "connection refused")
+	assert.Zero(t, buf.Len())
+}
+
+func TestService_Import(t *testing.T) {
+	header := `{"type":"header","schema_version":1}`
+	user := `{"type":"user","data":{"id":"` + testdata.Alice.ID.String() +
+		`","username":"alice","email":"alice@example.com"}}`
+	tests := []struct {
+		name       string
+		body       string
+		expectMock func(repo *MockRepository)
+		wantErr    string
+		wantErrors []*RecordError
+		wantUsers  int
+	}{
+		{
+			name:    "missing header",
+			body:    user,
+			wantErr: "first line must be an export header",
+		},
+		{
+			name:    "unsupported schema version",
+			body:    `{"type":"header","schema_version":99}`,
+			wantErr: "unsupported schema version 99",
+		},
+		{
+			name:    "empty",
+			body:    "",
+			wantErr: "import is empty",
+		},
+		{
+			name: "per record errors",
+			body: strings.Join([]string{header, user, "{", "",
+				`{"type":"comment","data":{}}`,
+				`{"type":"post","data":{"id":"` + testdata.Post1.ID.String() +
+					`","title":"t","user_id":"` + uuid.New().String() + `"}}`,
+			}, "\n"),
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().UpsertUser(gomock.Any(), gomock.Any()).Return(nil)
+				repo.EXPECT().UpsertPost(gomock.Any(), gomock.Any()).
+					Return(apperrors.NewReferenceNotFoundError("author_id"))
+			},
+			wantUsers: 1,
+			wantErrors: []*RecordError{
+				{Line: 3, Error: "invalid json"},
+				{Line: 5, Type: "comment",
+					Error: `unknown record type "comment"`},
+				{Line: 6, Type: "post", ID: testdata.Post1.ID.String(),
+					Error: "author_id references a resource that does not exist"},
+			},
+		},
+		{
+			name: "invalid reaction kind",
+			body: header + "\n" + `{"type":"reaction","data":{"post_id":"` +
+				testdata.Post1.ID.String() + `","user_id":"` +
+				testdata.Bob.ID.String() + `","kind":"angry"}}`,
+			wantErrors: []*RecordError{
+				{Line: 2, Type: "reaction", ID: testdata.Post1.ID.String() +
+					"/" + testdata.Bob.ID.String() + "/angry",
+					Error: "reaction record needs post_id, user_id and a known kind"},
+			},
+		},
+		{
+			name: "reaction counts are rebuilt",
+			body: `{"type":"header","schema_version":2}` + "\n" +
+				`{"type":"reaction","data":{"post_id":"` +
+				testdata.Post1.ID.String() + `","user_id":"` +
+				testdata.Bob.ID.String() + `","kind":"like"}}`,
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().UpsertReaction(gomock.Any(), gomock.Any()).Return(nil)
+				repo.EXPECT().RecountReactions(gomock.Any()).
+					Return(errors.New("connection refused"))
+			},
+			wantErr: "recount reactions: connection refused",
+		},
+		{
+			name: "upsert failure is reported",
+			body: header + "\n" + user,
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().UpsertUser(gomock.Any(), gomock.Any()).
+					Return(errors.New("db error"))
+			},
+			wantErrors: []*RecordError{
+				{Line: 2, Type: "user", ID: testdata.Alice.ID.String(),
+					Error: "db error"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setup(t)
+			if test.expectMock != nil {
+				test.expectMock(mockRepo)
+			}
+
+			report, err := service.Import(context.Background(),
+				strings.NewReader(test.body))
+
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantUsers, report.Users)
+			assert.Equal(t, test.wantErrors, report.Errors)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package trending
+
+import (
+	"github.com/pandahawk/blog-api/internal/post"
+	"time"
+)
+
+// sortable lists what sort= may name in the ranking.
+var sortable = []string{"score"}
+
+// ItemResponse is a post in the ranking with its place and score.
+type ItemResponse struct {
+	*post.Response
+	Rank  int     `json:"rank" example:"1"`
+	Score float64 `json:"score" example:"42.5"`
+}
+
+// PageResponse is one page of the ranking. ComputedAt is when it was last
+// refreshed and is left out while it is empty.
+type PageResponse struct {
+	Items      []*ItemResponse `json:"items"`
+	Page       int             `json:"page" example:"1"`
+	PerPage    int             `json:"per_page" example:"20"`
+	Total      int64           `json:"total" example:"42"`
+	ComputedAt *time.Time      `json:"computed_at,omitempty"`
+}
@@ -0,0 +1,78 @@
+package trending
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/post"
+	"github.com/pandahawk/blog-api/internal/query"
+	"net/http"
+)
+
+type Handler struct {
+	Service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{Service: service}
+}
+
+func handleError(c *gin.Context, err error) {
+	status := apperrors.StatusCode(err)
+	if status == http.StatusInternalServerError {
+		logging.FromContext(c.Request.Context()).Error("request failed",
+			"error", err)
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// RegisterPostRoutes adds the ranking to the posts group.
+func (h *Handler) RegisterPostRoutes(r *gin.RouterGroup) {
+	r.GET("/trending", h.getTrending)
+}
+
+// @Summary Get trending posts
+// @Description Get one page of the posts ranked by their recent views and
+// @Description reactions. Each view and reaction counts less the older it
+// @Description is, and only those within the configured window count. The
+// @Description ranking is recomputed periodically, computed_at tells when.
+// @Tags posts
+// @Produce json
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param per_page query int false "Posts per page" minimum(1) maximum(100) default(20)
+// @Param sort query string false "score, - prefix for descending" default(-score)
+// @Success 200 {object} PageResponse
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Router /posts/trending [get]
+// @Security ApiKeyAuth
+func (h *Handler) getTrending(c *gin.Context) {
+	page, err := query.ParsePage(c.Request.URL.Query(), sortable, "-score")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	posts, total, err := h.Service.GetTrending(c.Request.Context(), page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := &PageResponse{Items: make([]*ItemResponse, 0, len(posts)),
+		Page: page.Number, PerPage: page.PerPage, Total: total}
+	for i, p := range posts {
+		if resp.ComputedAt == nil {
+			resp.ComputedAt = &p.ComputedAt
+		}
+		if p.Post == nil {
+			continue
+		}
+		rank := page.Offset() + i + 1
+		if !page.Desc {
+			rank = int(total) - page.Offset() - i
+		}
+		resp.Items = append(resp.Items, &ItemResponse{
+			Response: post.NewSummaryResponse(p.Post), Rank: rank,
+			Score: p.Score})
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,111 @@
+package trending
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/shared/testdata"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupTestRouter(t *testing.T) (*gin.Engine, *MockService) {
+	ctrl := gomock.NewController(t)
+	mockService := NewMockService(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewHandler(mockService)
+	handler.RegisterPostRoutes(router.Group("/posts"))
+	return router, mockService
+}
+
+func TestHandler_GetTrending(t *testing.T) {
+	computedAt := time.Date(2025, 7, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		query         string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantItems     []string
+		wantRanks     []float64
+	}{
+		{
+			name:  "second page",
+			query: "?page=2&per_page=2",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetTrending(gomock.Any(), query.Page{
+					Number: 2, PerPage: 2, Sort: "score", Desc: true}).
+					Return([]*model.TrendingPost{
+						{PostID: testdata.Post2.ID, Score: 4,
+							ComputedAt: computedAt, Post: testdata.Post2},
+						{PostID: testdata.Post1.ID, Score: 3,
+							ComputedAt: computedAt, Post: testdata.Post1},
+					}, int64(5), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantItems:  []string{testdata.Post2.Title, testdata.Post1.Title},
+			wantRanks:  []float64{3, 4},
+		},
+		{
+			name:  "ascending",
+			query: "?sort=score",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().GetTrending(gomock.Any(), query.Page{
+					Number: 1, PerPage: query.DefaultPerPage, Sort: "score"}).
+					Return([]*model.TrendingPost{
+						{PostID: testdata.Post1.ID, Score: 3,
+							ComputedAt: computedAt, Post: testdata.Post1},
+					}, int64(5), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantItems:  []string{testdata.Post1.Title},
+			wantRanks:  []float64{5},
+		},
+		{
+			name:       "unknown sort",
+			query:      "?sort=title",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupTestRouter(t)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockService)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet,
+				"/posts/trending"+test.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			if test.wantStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				Items []struct {
+					Title string  `json:"title"`
+					Rank  float64 `json:"rank"`
+				} `json:"items"`
+				ComputedAt time.Time `json:"computed_at"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			var titles []string
+			var ranks []float64
+			for _, item := range body.Items {
+				titles = append(titles, item.Title)
+				ranks = append(ranks, item.Rank)
+			}
+			assert.Equal(t, test.wantItems, titles)
+			assert.Equal(t, test.wantRanks, ranks)
+			assert.True(t, computedAt.Equal(body.ComputedAt))
+		})
+	}
+}
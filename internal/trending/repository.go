@@ -0,0 +1,110 @@
+package trending
+
+import (
+	"context"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+//go:generate mockgen -source=repository.go -destination=repository_mock.go -package=trending
+
+type Repository interface {
+	// Refresh replaces the ranking with the scores as of now and returns
+	// the number of posts ranked.
+	Refresh(ctx context.Context, now time.Time, p Params) (int64, error)
+	// FindPage returns one page of the ranking with the posts and their
+	// authors loaded, and the number of ranked posts.
+	FindPage(ctx context.Context, page query.Page) ([]*model.TrendingPost, int64, error)
+}
+
+// Params are the settings of a refresh.
+type Params struct {
+	Window   time.Duration
+	HalfLife time.Duration
+	// ReactionWeight is how many views one reaction is worth.
+	ReactionWeight float64
+	// Size is the most posts the ranking keeps.
+	Size int
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// refreshLock keeps replicas from refreshing the ranking at the same time.
+const refreshLock = "trending_posts"
+
+func (r *repository) Refresh(ctx context.Context, now time.Time,
+	p Params) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))",
+			refreshLock).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM trending_posts").Error; err != nil {
+			return err
+		}
+		// Each view and reaction counts 0.5^(age / half life). Views are
+		// kept per day, so their age is taken from the start of the day.
+		// The exponent is capped at 1000 because float8 underflows past
+		// about 1075 half lives, which a long window and short half life
+		// reach; 0.5^1000 is as good as nothing either way.
+		result := tx.Exec(`
+			WITH p AS (SELECT CAST(@now AS timestamptz) AS now,
+			                  CAST(@since AS timestamptz) AS since,
+			                  CAST(@half_life AS float8) AS half_life,
+			                  CAST(@reaction_weight AS float8) AS reaction_weight)
+			INSERT INTO trending_posts (post_id, score, computed_at)
+			SELECT s.post_id, SUM(s.score), p.now
+			FROM (SELECT v.post_id,
+			             v.views * POWER(0.5, LEAST(EXTRACT(EPOCH FROM p.now -
+			                 (v.day::timestamp AT TIME ZONE 'UTC')) / p.half_life,
+			                 1000)) AS score
+			      FROM post_views v, p
+			      WHERE v.day >= (p.since AT TIME ZONE 'UTC')::date
+			      UNION ALL
+			      SELECT r.post_id,
+			             p.reaction_weight * POWER(0.5, LEAST(EXTRACT(EPOCH FROM
+			                 p.now - r.created_at) / p.half_life, 1000))
+			      FROM reactions r, p
+			      WHERE r.created_at >= p.since) s, p
+			GROUP BY s.post_id, p.now
+			ORDER BY SUM(s.score) DESC, s.post_id
+			LIMIT CAST(@size AS integer)`, map[string]any{
+			"now":             now,
+			"since":           now.Add(-p.Window),
+			"half_life":       p.HalfLife.Seconds(),
+			"reaction_weight": p.ReactionWeight,
+			"size":            p.Size,
+		})
+		n = result.RowsAffected
+		return result.Error
+	})
+	return n, err
+}
+
+func (r *repository) FindPage(ctx context.Context,
+	page query.Page) ([]*model.TrendingPost, int64, error) {
+	db := r.db.WithContext(ctx).Model(&model.TrendingPost{})
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var posts []*model.TrendingPost
+	err := db.Preload("Post.User").Preload("Post.ReactionCounts").
+		Order(clause.OrderByColumn{
+			Column: clause.Column{Name: "score"}, Desc: page.Desc}).
+		Order("post_id").
+		Limit(page.PerPage).
+		Offset(page.Offset()).
+		Find(&posts).Error
+	return posts, total, err
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
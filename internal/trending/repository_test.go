@@ -0,0 +1,89 @@
+package trending
+
+import (
+	"context"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/shared/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRepository_Refresh(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	now := time.Date(2025, 7, 10, 1, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, db.Create([]*model.PostView{
+		{PostID: testdata.Post1.ID, Day: today, Views: 10},
+		// Outside the window, so it is not ranked however many views.
+		{PostID: testdata.Post2.ID, Day: today.AddDate(0, 0, -8), Views: 100},
+	}).Error)
+	for _, user := range []*model.User{testdata.Alice, testdata.Bob,
+		testdata.Caren} {
+		reaction := model.NewReaction(testdata.Post3.ID, user.ID,
+			model.ReactionLike)
+		reaction.CreatedAt = now
+		require.NoError(t, db.Create(reaction).Error)
+	}
+
+	n, err := repo.Refresh(ctx, now, Params{Window: 7 * 24 * time.Hour,
+		HalfLife: 24 * time.Hour, ReactionWeight: 5, Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	posts, total, err := repo.FindPage(ctx, query.Page{Number: 1,
+		PerPage: 10, Sort: "score", Desc: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, posts, 2)
+	assert.Equal(t, testdata.Post3.ID, posts[0].PostID)
+	assert.InDelta(t, 15, posts[0].Score, 0.001)
+	assert.Equal(t, testdata.Post1.ID, posts[1].PostID)
+	// An hour old at a half life of a day.
+	assert.InDelta(t, 9.715, posts[1].Score, 0.001)
+	require.NotNil(t, posts[1].Post)
+	assert.Equal(t, testdata.Post1.Title, posts[1].Post.Title)
+
+	// A later refresh replaces the ranking instead of adding to it.
+	n, err = repo.Refresh(ctx, now.Add(48*time.Hour), Params{
+		Window: 24 * time.Hour, HalfLife: 24 * time.Hour, ReactionWeight: 5,
+		Size: 10})
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestRepository_Refresh_ShortHalfLife(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	now := time.Date(2025, 7, 10, 1, 0, 0, 0, time.UTC)
+
+	require.NoError(t, db.Create([]*model.PostView{
+		{PostID: testdata.Post1.ID, Day: now.AddDate(0, 0, -6), Views: 10},
+	}).Error)
+	reaction := model.NewReaction(testdata.Post2.ID, testdata.Alice.ID,
+		model.ReactionLike)
+	reaction.CreatedAt = now.Add(-6 * 24 * time.Hour)
+	require.NoError(t, db.Create(reaction).Error)
+
+	// Six days are over 1700 half lives of five minutes, far past where
+	// 0.5^x underflows a float8.
+	n, err := repo.Refresh(ctx, now, Params{Window: 7 * 24 * time.Hour,
+		HalfLife: 5 * time.Minute, ReactionWeight: 5, Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	posts, _, err := repo.FindPage(ctx, query.Page{Number: 1, PerPage: 10,
+		Sort: "score", Desc: true})
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	for _, p := range posts {
+		assert.InDelta(t, 0, p.Score, 1e-290)
+	}
+}
@@ -0,0 +1,88 @@
+package trending
+
+import (
+	"context"
+	"errors"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock.go -package=trending
+
+const (
+	// reactionWeight is how many views one reaction is worth, since
+	// reacting takes more interest than reading.
+	reactionWeight = 5
+	// rankingSize is the most posts the ranking keeps.
+	rankingSize = 1000
+)
+
+type Service interface {
+	// Refresh recomputes the ranking and returns the number of posts in it.
+	Refresh(ctx context.Context) (int64, error)
+	// GetTrending returns one page of the ranking as of the last refresh
+	// and the number of posts in it.
+	GetTrending(ctx context.Context, page query.Page) ([]*model.TrendingPost, int64, error)
+}
+
+type service struct {
+	repo Repository
+	cfg  config.TrendingConfig
+	now  func() time.Time
+}
+
+func (s *service) Refresh(ctx context.Context) (int64, error) {
+	return s.repo.Refresh(ctx, s.now(), Params{
+		Window:         s.cfg.Window,
+		HalfLife:       s.cfg.HalfLife,
+		ReactionWeight: reactionWeight,
+		Size:           rankingSize,
+	})
+}
+
+func (s *service) GetTrending(ctx context.Context,
+	page query.Page) ([]*model.TrendingPost, int64, error) {
+	posts, total, err := s.repo.FindPage(ctx, page)
+	if err != nil {
+		logging.FromContext(ctx).Error("find trending posts", "error", err)
+		return nil, 0, errors.New("db error")
+	}
+	return posts, total, nil
+}
+
+func NewService(repo Repository, cfg config.TrendingConfig) Service {
+	return &service{repo: repo, cfg: cfg, now: time.Now}
+}
+
+// RefreshWorker refreshes the ranking right away and then every interval
+// until ctx is cancelled.
+func RefreshWorker(s Service, interval time.Duration) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		refresh := func() {
+			n, err := s.Refresh(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					logging.FromContext(ctx).Error(
+						"refresh trending posts", "error", err)
+				}
+				return
+			}
+			logging.FromContext(ctx).Debug("refreshed trending posts",
+				"count", n)
+		}
+		refresh()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package trending
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/shared/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var testConfig = config.TrendingConfig{Window: 7 * 24 * time.Hour,
+	HalfLife: 24 * time.Hour, RefreshInterval: time.Minute}
+
+func setupMockRepoAndService(t *testing.T) (*MockRepository, *service) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockRepo := NewMockRepository(ctrl)
+	s := NewService(mockRepo, testConfig).(*service)
+	s.now = func() time.Time {
+		return time.Date(2025, 7, 31, 12, 0, 0, 0, time.UTC)
+	}
+	return mockRepo, s
+}
+
+func TestService_Refresh(t *testing.T) {
+	mockRepo, s := setupMockRepoAndService(t)
+	mockRepo.EXPECT().Refresh(gomock.Any(), s.now(), Params{
+		Window: testConfig.Window, HalfLife: testConfig.HalfLife,
+		ReactionWeight: reactionWeight, Size: rankingSize,
+	}).Return(int64(3), nil)
+
+	n, err := s.Refresh(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
+func TestService_GetTrending(t *testing.T) {
+	page := query.Page{Number: 1, PerPage: 20, Sort: "score", Desc: true}
+	tests := []struct {
+		name       string
+		expectMock func(mockRepo *MockRepository)
+		wantTotal  int64
+		wantErr    string
+	}{
+		{
+			name: "success",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindPage(gomock.Any(), page).
+					Return([]*model.TrendingPost{{PostID: testdata.Post1.ID,
+						Score: 2, Post: testdata.Post1}}, int64(1), nil)
+			},
+			wantTotal: 1,
+		},
+		{
+			name: "db error",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindPage(gomock.Any(), page).
+					Return(nil, int64(0), errors.New("connection refused"))
+			},
+			wantErr: "db error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, s := setupMockRepoAndService(t)
+			test.expectMock(mockRepo)
+
+			posts, total, err := s.GetTrending(context.Background(), page)
+
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, posts, 1)
+			assert.Equal(t, test.wantTotal, total)
+		})
+	}
+}
+
+func TestRefreshWorker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := NewMockService(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	// The first refresh runs right away, the second on the first tick.
+	gomock.InOrder(
+		mockService.EXPECT().Refresh(gomock.Any()).
+			Return(int64(0), errors.New("connection refused")),
+		mockService.EXPECT().Refresh(gomock.Any()).
+			DoAndReturn(func(context.Context) (int64, error) {
+				cancel()
+				close(done)
+				return 1, nil
+			}),
+	)
+
+	go RefreshWorker(mockService, time.Millisecond)(ctx)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not refresh on tick")
+	}
+}
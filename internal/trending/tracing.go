@@ -0,0 +1,39 @@
+package trending
+
+import (
+	"context"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("trending")
+
+type tracedService struct {
+	next Service
+}
+
+// NewTracedService wraps a Service so every call runs in its own span.
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next}
+}
+
+func (s *tracedService) Refresh(ctx context.Context) (int64, error) {
+	ctx, span := tracer.Start(ctx, "trending.Service.Refresh")
+	n, err := s.next.Refresh(ctx)
+	span.SetAttributes(attribute.Int64("post.count", n))
+	tracing.End(span, err)
+	return n, err
+}
+
+func (s *tracedService) GetTrending(ctx context.Context,
+	page query.Page) ([]*model.TrendingPost, int64, error) {
+	ctx, span := tracer.Start(ctx, "trending.Service.GetTrending",
+		trace.WithAttributes(attribute.Int("page.number", page.Number),
+			attribute.Int("page.per_page", page.PerPage)))
+	posts, total, err := s.next.GetTrending(ctx, page)
+	tracing.End(span, err)
+	return posts, total, err
+}
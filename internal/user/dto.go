@@ -2,9 +2,22 @@ package user
 
 import (
 	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/query"
 	"time"
 )
 
+// expandPosts is the name of a user's posts in expand=.
+const expandPosts = "posts"
+
+// responseSpec lists what fields= and expand= may name for Response.
+var responseSpec = query.Spec{
+	Fields: []string{"user_id", "username", "email", "joined_at"},
+	Relations: map[string][]string{
+		expandPosts: {"post_id", "title"},
+	},
+	DefaultExpand: []string{expandPosts},
+}
+
 type CreateUserRequest struct {
 	Username string `json:"username" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
@@ -27,3 +40,21 @@ type PostSummaryResponse struct {
 	PostID uuid.UUID `json:"post_id" format:"uuid"`
 	Title  string    `json:"title" example:"My First Post"`
 }
+
+// followSortable lists what sort= may name in follower lists.
+var followSortable = []string{"followed_at"}
+
+// FollowResponse is a user on the other side of a follow.
+type FollowResponse struct {
+	UserID     uuid.UUID `json:"user_id" swaggertype:"string"`
+	Username   string    `json:"username" example:"mike"`
+	FollowedAt time.Time `json:"followed_at" example:"2025-07-18T15:04:05Z"`
+}
+
+// FollowPageResponse is one page of followers or followed users.
+type FollowPageResponse struct {
+	Items   []*FollowResponse `json:"items"`
+	Page    int               `json:"page" example:"1"`
+	PerPage int               `json:"per_page" example:"20"`
+	Total   int64             `json:"total" example:"42"`
+}
@@ -1,10 +1,16 @@
 package user
 
 import (
-	"errors"
+	"context"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/batch"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/patch"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
 	"net/http"
 	"strings"
@@ -12,6 +18,8 @@ import (
 
 type Handler struct {
 	Service Service
+	// MaxBatchSize is the most operations a batch request may hold.
+	MaxBatchSize int
 }
 
 func buildUserResponse(u *model.User) *Response {
@@ -32,27 +40,27 @@ func buildUserResponse(u *model.User) *Response {
 }
 
 func NewHandler(service Service) *Handler {
-	return &Handler{Service: service}
+	return &Handler{Service: service, MaxBatchSize: batch.DefaultMaxItems}
 }
 
 func handleError(c *gin.Context, err error) {
-	var de *apperrors.DuplicateError
-	if errors.As(err, &de) {
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-		return
-	}
-
-	var ne *apperrors.NotFoundError
-	if errors.As(err, &ne) {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		return
+	status := apperrors.StatusCode(err)
+	if status == http.StatusInternalServerError {
+		logging.FromContext(c.Request.Context()).Error("request failed",
+			"error", err)
 	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
 
-	var ie *apperrors.InvalidInputError
-	if errors.As(err, &ie) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// render writes resp trimmed to the fields and relations in opts.
+func render(c *gin.Context, status int, resp any, spec query.Spec,
+	opts query.Options) {
+	body, err := spec.Render(resp, opts)
+	if err != nil {
+		handleError(c, err)
 		return
 	}
+	c.JSON(status, body)
 }
 
 func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
@@ -61,17 +69,29 @@ func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 	r.POST("", h.createUser)
 	r.PATCH("/:id", h.updateUser)
 	r.DELETE("/:id", h.deleteUser)
+	r.POST("/:id/follow", h.follow)
+	r.DELETE("/:id/follow", h.unfollow)
+	r.GET("/:id/followers", h.getFollowers)
+	r.GET("/:id/following", h.getFollowing)
 }
 
 // @Summary Get all users
 // @Description Get all users in the system
 // @Tags users
 // @Produce json
+// @Param fields query string false "Comma separated attributes to return, posts.<attr> for post attributes"
+// @Param expand query string false "Comma separated relations to load: posts (default)"
 // @Success 200 {array} Response
+// @Failure 400 {object} apperrors.InvalidInputError
 // @Router /users [get]
 // @Security ApiKeyAuth
 func (h *Handler) getUsers(c *gin.Context) {
-	users, err := h.Service.GetUsers()
+	opts, err := responseSpec.Parse(c.Request.URL.Query())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	users, err := h.Service.GetUsers(c.Request.Context(), opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -81,7 +101,7 @@ func (h *Handler) getUsers(c *gin.Context) {
 	for i, u := range users {
 		resp[i] = buildUserResponse(u)
 	}
-	c.JSON(http.StatusOK, resp)
+	render(c, http.StatusOK, resp, responseSpec, opts)
 }
 
 // @Summary Get user by ID
@@ -89,6 +109,8 @@ func (h *Handler) getUsers(c *gin.Context) {
 // @Tags users
 // @Produce json
 // @Param id path string true "User ID" format:"uuid"
+// @Param fields query string false "Comma separated attributes to return, posts.<attr> for post attributes"
+// @Param expand query string false "Comma separated relations to load: posts (default)"
 // @Success 200 {object} Response
 // @Failure 404 {object} apperrors.NotFoundError
 // @Failure 400 {object} apperrors.InvalidInputError
@@ -101,13 +123,18 @@ func (h *Handler) getUser(c *gin.Context) {
 		handleError(c, apperrors.NewInvalidInputError("ID must be a uuid"))
 		return
 	}
-	u, err := h.Service.GetUser(id)
+	opts, err := responseSpec.Parse(c.Request.URL.Query())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	u, err := h.Service.GetUser(c.Request.Context(), id, opts)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 	resp := buildUserResponse(u)
-	c.JSON(http.StatusOK, resp)
+	render(c, http.StatusOK, resp, responseSpec, opts)
 }
 
 // @Summary Create a new user
@@ -116,6 +143,7 @@ func (h *Handler) getUser(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param user body user.CreateUserRequest true "User data"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
 // @Success 201 {object} Response
 // @Failure 400 {object} apperrors.InvalidInputError
 // @Failure 409 {object} apperrors.DuplicateError
@@ -128,7 +156,7 @@ func (h *Handler) createUser(c *gin.Context) {
 		handleError(c, apperrors.NewInvalidInputError("invalid request body"))
 		return
 	}
-	u, err := h.Service.CreateUser(&req)
+	u, err := h.Service.CreateUser(c.Request.Context(), &req)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -139,9 +167,11 @@ func (h *Handler) createUser(c *gin.Context) {
 }
 
 // @Summary Update user by ID
-// @Description Updates an existing user and returns the updated resource
+// @Description Updates an existing user and returns the updated resource.
+// @Description Besides the partial update below, the body may be a JSON merge
+// @Description patch or JSON patch document against the user representation.
 // @Tags users
-// @Accept json
+// @Accept json,application/merge-patch+json,application/json-patch+json
 // @Produce json
 // @Param id path string true "User ID" format(uuid)
 // @Param user body user.UpdateUserRequest true "User update data"
@@ -149,6 +179,8 @@ func (h *Handler) createUser(c *gin.Context) {
 // @Failure 400 {object} apperrors.InvalidInputError
 // @Failure 404 {object} apperrors.NotFoundError
 // @Failure 400 {object} apperrors.DuplicateError
+// @Failure 409 {object} apperrors.ConflictError
+// @Failure 403 {object} apperrors.ForbiddenError
 // @Router /users/{id} [patch]
 // @Security ApiKeyAuth
 func (h *Handler) updateUser(c *gin.Context) {
@@ -159,7 +191,12 @@ func (h *Handler) updateUser(c *gin.Context) {
 		return
 	}
 	var req UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if patch.IsPatch(c.ContentType()) {
+		if err := h.bindPatch(c, id, &req); err != nil {
+			handleError(c, err)
+			return
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
 		if strings.Contains(err.Error(), "Email") {
 			handleError(c, apperrors.NewInvalidInputError("invalid email"))
 			return
@@ -167,7 +204,7 @@ func (h *Handler) updateUser(c *gin.Context) {
 		handleError(c, apperrors.NewInvalidInputError("invalid request body"))
 		return
 	}
-	u, err := h.Service.UpdateUser(id, &req)
+	u, err := h.Service.UpdateUser(c.Request.Context(), id, &req)
 
 	if err != nil {
 		handleError(c, err)
@@ -178,6 +215,45 @@ func (h *Handler) updateUser(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// bindPatch applies the patch document in the request body to the current
+// user and fills req with the fields that changed. Removed or nulled fields
+// become empty strings, which validation then rejects.
+func (h *Handler) bindPatch(c *gin.Context, id uuid.UUID,
+	req *UpdateUserRequest) error {
+	body, err := c.GetRawData()
+	if err != nil {
+		return apperrors.NewInvalidInputError("invalid request body")
+	}
+	current, err := h.Service.GetUser(c.Request.Context(), id,
+		query.Options{Expand: responseSpec.DefaultExpand})
+	if err != nil {
+		return err
+	}
+
+	var patched UpdateUserRequest
+	if err := patch.Apply(c.ContentType(), buildUserResponse(current), body,
+		[]string{"username", "email"}, &patched); err != nil {
+		return err
+	}
+	if username := deref(patched.Username); username != current.Username {
+		req.Username = &username
+	}
+	if email := deref(patched.Email); email != current.Email {
+		req.Email = &email
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return apperrors.NewInvalidInputError("invalid email")
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // @Summary Delete user by ID
 // @Description Deletes an existing user
 // @Tags users
@@ -187,6 +263,7 @@ func (h *Handler) updateUser(c *gin.Context) {
 // @Success 204
 // @Failure 404 {object} apperrors.NotFoundError
 // @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
 // @Router /users/{id} [delete]
 // @Security ApiKeyAuth
 func (h *Handler) deleteUser(c *gin.Context) {
@@ -197,9 +274,189 @@ func (h *Handler) deleteUser(c *gin.Context) {
 		return
 	}
 
-	if err = h.Service.DeleteUser(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err = h.Service.DeleteUser(c.Request.Context(), id); err != nil {
+		handleError(c, err)
 		return
 	}
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// Batch serves POST /users:batch. It is exported because the route is
+// registered by the router rather than by RegisterRoutes.
+//
+// @Summary Create, update and delete users in one request
+// @Description Applies up to the configured number of operations in order.
+// @Description In atomic mode (the default) one failure rolls back the whole
+// @Description batch and the other operations report 424; in best_effort
+// @Description mode every operation stands on its own. Each result carries
+// @Description the status a single request would have returned.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param batch body batch.Request true "Operations"
+// @Success 200 {object} batch.Response{results=[]batch.Result{data=Response}}
+// @Success 207 {object} batch.Response{results=[]batch.Result{data=Response}}
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Router /users:batch [post]
+// @Security ApiKeyAuth
+func (h *Handler) Batch(c *gin.Context) {
+	var req batch.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewInvalidInputError("invalid request body"))
+		return
+	}
+	if err := req.Validate(h.MaxBatchSize); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	ops := make([]*BatchOperation, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i] = newBatchOperation(op)
+	}
+	ctx := c.Request.Context()
+	results, err := h.Service.ApplyBatch(ctx, ops, req.Atomic())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := &batch.Response{Results: make([]*batch.Result, 0, len(results))}
+	for i, r := range results {
+		var data any
+		if r.User != nil {
+			data = buildUserResponse(r.User)
+		}
+		result := resp.Add(i, ops[i].Op, data, r.Err)
+		if result.Status == http.StatusInternalServerError {
+			logging.FromContext(ctx).Error("batch operation failed",
+				"index", i, "error", r.Err)
+		}
+	}
+	c.JSON(resp.StatusCode(), resp)
+}
+
+// @Summary Follow a user
+// @Description The user the API key is linked to follows the user with the
+// @Description specified ID. Following a user twice is not an error.
+// @Tags users
+// @Param id path string true "User ID" Format(uuid)
+// @Success 204
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /users/{id}/follow [post]
+// @Security ApiKeyAuth
+func (h *Handler) follow(c *gin.Context) {
+	h.changeFollow(c, h.Service.Follow)
+}
+
+// @Summary Unfollow a user
+// @Description The user the API key is linked to stops following the user
+// @Description with the specified ID.
+// @Tags users
+// @Param id path string true "User ID" Format(uuid)
+// @Success 204
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /users/{id}/follow [delete]
+// @Security ApiKeyAuth
+func (h *Handler) unfollow(c *gin.Context) {
+	h.changeFollow(c, h.Service.Unfollow)
+}
+
+func (h *Handler) changeFollow(c *gin.Context, change func(ctx context.Context,
+	followerID uuid.UUID, followeeID uuid.UUID) error) {
+	followeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleError(c, apperrors.NewInvalidInputError("ID must be a uuid"))
+		return
+	}
+	followerID, err := auth.RequireUser(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if err := change(c.Request.Context(), followerID, followeeID); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get the followers of a user
+// @Description Get one page of the users following the user, most recent
+// @Description follow first
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID" Format(uuid)
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param per_page query int false "Users per page" minimum(1) maximum(100) default(20)
+// @Param sort query string false "followed_at, - prefix for descending" default(-followed_at)
+// @Success 200 {object} FollowPageResponse
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /users/{id}/followers [get]
+// @Security ApiKeyAuth
+func (h *Handler) getFollowers(c *gin.Context) {
+	h.listFollows(c, h.Service.GetFollowers, func(f *model.Follow) *model.User {
+		return f.Follower
+	})
+}
+
+// @Summary Get the users a user follows
+// @Description Get one page of the users the user follows, most recent
+// @Description follow first
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID" Format(uuid)
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param per_page query int false "Users per page" minimum(1) maximum(100) default(20)
+// @Param sort query string false "followed_at, - prefix for descending" default(-followed_at)
+// @Success 200 {object} FollowPageResponse
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 404 {object} apperrors.NotFoundError
+// @Router /users/{id}/following [get]
+// @Security ApiKeyAuth
+func (h *Handler) getFollowing(c *gin.Context) {
+	h.listFollows(c, h.Service.GetFollowing, func(f *model.Follow) *model.User {
+		return f.Followee
+	})
+}
+
+// listFollows serves a page of follows, showing the user other returns.
+func (h *Handler) listFollows(c *gin.Context, find func(ctx context.Context,
+	id uuid.UUID, page query.Page) ([]*model.Follow, int64, error),
+	other func(f *model.Follow) *model.User) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleError(c, apperrors.NewInvalidInputError("ID must be a uuid"))
+		return
+	}
+	page, err := query.ParsePage(c.Request.URL.Query(), followSortable,
+		"-followed_at")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	follows, total, err := find(c.Request.Context(), id, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	items := make([]*FollowResponse, 0, len(follows))
+	for _, f := range follows {
+		if u := other(f); u != nil {
+			items = append(items, &FollowResponse{UserID: u.ID,
+				Username: u.Username, FollowedAt: f.CreatedAt})
+		}
+	}
+	c.JSON(http.StatusOK, &FollowPageResponse{
+		Items:   items,
+		Page:    page.Number,
+		PerPage: page.PerPage,
+		Total:   total,
+	})
+}
@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,6 +9,8 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
 	"github.com/stretchr/testify/assert"
 	"io"
@@ -15,6 +18,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func setupTestRouterWithMockService(t *testing.T) (*gin.Engine, *MockService) {
@@ -51,7 +55,7 @@ func TestHandler_GetUser(t *testing.T) {
 			path:   fmt.Sprintf("/users/%s", id.String()),
 			mockBehaviour: func(service *MockService, user *model.User) {
 				service.EXPECT().
-					GetUser(gomock.Any()).
+					GetUser(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(user, nil)
 			},
 			wantStatus: http.StatusOK,
@@ -63,7 +67,7 @@ func TestHandler_GetUser(t *testing.T) {
 			path:   fmt.Sprintf("/users/%s", id.String()),
 			mockBehaviour: func(service *MockService, user *model.User) {
 				service.EXPECT().
-					GetUser(gomock.Any()).
+					GetUser(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, apperrors.NewNotFoundError("user", id))
 			},
 			wantStatus: http.StatusNotFound,
@@ -93,6 +97,75 @@ func TestHandler_GetUser(t *testing.T) {
 	}
 }
 
+func TestHandler_GetUser_FieldsAndExpand(t *testing.T) {
+	user := &model.User{
+		ID:       uuid.Nil,
+		Username: "testuser01",
+		Email:    "testuser01@example.com",
+		Posts:    []*model.Post{{ID: uuid.Nil, Title: "title"}},
+	}
+	tests := []struct {
+		name       string
+		query      string
+		wantOpts   *query.Options
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "defaults",
+			query:      "",
+			wantOpts:   &query.Options{Expand: []string{"posts"}},
+			wantStatus: http.StatusOK,
+			wantBody:   `"posts":[{"post_id":`,
+		},
+		{
+			name:  "without posts",
+			query: "?expand=&fields=username",
+			wantOpts: &query.Options{
+				Fields: []string{"username"},
+				Expand: []string{},
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"username":"testuser01"}`,
+		},
+		{
+			name:  "post titles only",
+			query: "?fields=user_id,posts.title",
+			wantOpts: &query.Options{
+				Fields: []string{"user_id", "posts.title"},
+				Expand: []string{"posts"},
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"posts":[{"title":"title"}],` +
+				`"user_id":"00000000-0000-0000-0000-000000000000"}`,
+		},
+		{
+			name:       "unknown relation",
+			query:      "?expand=comments",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `cannot expand \"comments\"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupTestRouterWithMockService(t)
+			if test.wantOpts != nil {
+				mockService.EXPECT().
+					GetUser(gomock.Any(), uuid.Nil, gomock.Eq(*test.wantOpts)).
+					Return(user, nil)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet,
+				"/users/"+uuid.Nil.String()+test.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantBody)
+		})
+	}
+}
+
 func TestHandler_GetUsers(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -105,7 +178,7 @@ func TestHandler_GetUsers(t *testing.T) {
 		{
 			name: "success",
 			mockBehaviour: func(service *MockService, users []*model.User) {
-				service.EXPECT().GetUsers().Return(users, nil)
+				service.EXPECT().GetUsers(gomock.Any(), gomock.Any()).Return(users, nil)
 			},
 			wantUsers: []*model.User{
 				{ID: uuid.New(), Username: "testuser1", Email: "testuser1@mail.com"},
@@ -123,7 +196,7 @@ func TestHandler_GetUsers(t *testing.T) {
 			wantUsers: nil,
 			want:      nil,
 			mockBehaviour: func(service *MockService, users []*model.User) {
-				service.EXPECT().GetUsers().
+				service.EXPECT().GetUsers(gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("db error"))
 			},
 			wantStatus: 500,
@@ -168,7 +241,7 @@ func TestHandler_CreateUser(t *testing.T) {
 			name:    "success",
 			rawBody: `{"username": "testuser", "email":"testuser@example.com"}`,
 			mockBehaviour: func(service *MockService) {
-				service.EXPECT().CreateUser(gomock.Any()).
+				service.EXPECT().CreateUser(gomock.Any(), gomock.Any()).
 					Return(&model.User{
 						ID:       uuid.Nil,
 						Username: "testuser",
@@ -195,7 +268,7 @@ func TestHandler_CreateUser(t *testing.T) {
 			name:    "invalid username",
 			rawBody: `{"username":"123","email":"testuser@mail.com"}`,
 			mockBehaviour: func(service *MockService) {
-				service.EXPECT().CreateUser(gomock.Any()).
+				service.EXPECT().CreateUser(gomock.Any(), gomock.Any()).
 					Return(nil, apperrors.NewInvalidInputError(
 						"invalid username: must not be a number"))
 			},
@@ -207,7 +280,7 @@ func TestHandler_CreateUser(t *testing.T) {
 			name:    "duplicate username",
 			rawBody: `{"username":"testuser","email":"testuser@mail.com"}`,
 			mockBehaviour: func(service *MockService) {
-				service.EXPECT().CreateUser(gomock.Any()).
+				service.EXPECT().CreateUser(gomock.Any(), gomock.Any()).
 					Return(nil, apperrors.NewDuplicateError("username"))
 			},
 			want:       nil,
@@ -268,7 +341,7 @@ func TestHandler_UpdateUser(t *testing.T) {
 			},
 			mockBehaviour: func(service *MockService, id string,
 				rawBody string, user *model.User) {
-				service.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).
+				service.EXPECT().UpdateUser(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(user, nil)
 			},
 			wantStatus: http.StatusOK,
@@ -315,7 +388,7 @@ func TestHandler_UpdateUser(t *testing.T) {
 			wantUser: nil,
 			mockBehaviour: func(service *MockService, id string,
 				rawBody string, user *model.User) {
-				service.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).
+				service.EXPECT().UpdateUser(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, apperrors.NewInvalidInputError(
 						"invalid username: must not be a number"))
 			},
@@ -355,6 +428,87 @@ func TestHandler_UpdateUser(t *testing.T) {
 	}
 }
 
+func TestHandler_PatchUser(t *testing.T) {
+	current := &model.User{
+		ID:       uuid.Nil,
+		Username: "testuser01",
+		Email:    "testuser01@example.com",
+	}
+	tests := []struct {
+		name        string
+		contentType string
+		rawBody     string
+		wantReq     *UpdateUserRequest
+		wantStatus  int
+		wantErr     string
+	}{
+		{
+			name:        "merge patch",
+			contentType: "application/merge-patch+json",
+			rawBody:     `{"username":"updated"}`,
+			wantReq:     &UpdateUserRequest{Username: ptr("updated")},
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "merge patch removing email",
+			contentType: "application/merge-patch+json",
+			rawBody:     `{"email":null}`,
+			wantStatus:  http.StatusBadRequest,
+			wantErr:     "invalid email",
+		},
+		{
+			name:        "json patch",
+			contentType: "application/json-patch+json",
+			rawBody: `[{"op":"test","path":"/username","value":"testuser01"},
+				{"op":"replace","path":"/email","value":"updated@mail.com"}]`,
+			wantReq:    &UpdateUserRequest{Email: ptr("updated@mail.com")},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "failed test operation",
+			contentType: "application/json-patch+json",
+			rawBody:     `[{"op":"test","path":"/username","value":"other"}]`,
+			wantStatus:  http.StatusConflict,
+			wantErr:     "testing value /username failed",
+		},
+		{
+			name:        "read-only field",
+			contentType: "application/merge-patch+json",
+			rawBody:     `{"user_id":"5faeb0b2-43b3-4a7a-aaf2-77b71ea59d90"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantErr:     "read-only fields cannot be changed: [user_id]",
+		},
+		{
+			name:        "invalid email",
+			contentType: "application/merge-patch+json",
+			rawBody:     `{"email":"updatedmail.com"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantErr:     "invalid email",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupTestRouterWithMockService(t)
+			mockService.EXPECT().GetUser(gomock.Any(), uuid.Nil, gomock.Any()).
+				Return(current, nil)
+			if test.wantReq != nil {
+				mockService.EXPECT().
+					UpdateUser(gomock.Any(), uuid.Nil, test.wantReq).
+					Return(current, nil)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPatch,
+				"/users/"+uuid.Nil.String(), strings.NewReader(test.rawBody))
+			req.Header.Set("Content-Type", test.contentType)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantErr)
+		})
+	}
+}
+
 func TestHandler_DeleteUser(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -369,7 +523,7 @@ func TestHandler_DeleteUser(t *testing.T) {
 			wantStatus: 204,
 			wantErr:    "",
 			mockBehaviour: func(service *MockService, id string) {
-				service.EXPECT().DeleteUser(gomock.Any()).
+				service.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).
 					Return(nil)
 			},
 		},
@@ -386,7 +540,7 @@ func TestHandler_DeleteUser(t *testing.T) {
 			wantStatus: 500,
 			wantErr:    "deletion failed",
 			mockBehaviour: func(service *MockService, id string) {
-				service.EXPECT().DeleteUser(gomock.Any()).
+				service.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).
 					Return(errors.New("deletion failed"))
 			},
 		},
@@ -440,3 +594,290 @@ func TestHandler_buildUserResponse(t *testing.T) {
 	got := buildUserResponse(user)
 	assert.Equal(t, want, got)
 }
+
+func TestHandler_Batch(t *testing.T) {
+	created := &model.User{ID: uuid.Nil, Username: "testuser01",
+		Email: "testuser01@example.com"}
+	tests := []struct {
+		name          string
+		rawBody       string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name: "success",
+			rawBody: `{"operations":[{"op":"create",` +
+				`"data":{"username":"testuser01","email":"testuser01@example.com"}}]}`,
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().ApplyBatch(gomock.Any(), gomock.Any(), true).
+					DoAndReturn(func(ctx context.Context, ops []*BatchOperation,
+						atomic bool) ([]*BatchResult, error) {
+						assert.Equal(t, "testuser01", ops[0].Create.Username)
+						return []*BatchResult{{User: created}}, nil
+					})
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"results":[{"index":0,"status":201,"data":{"user_id"`,
+		},
+		{
+			name: "partial failure",
+			rawBody: `{"mode":"best_effort","operations":[` +
+				`{"op":"create","data":{"username":"testuser01"}},` +
+				`{"op":"delete","id":"5faeb0b2-43b3-4a7a-aaf2-77b71ea59d90"}]}`,
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().ApplyBatch(gomock.Any(), gomock.Any(), false).
+					DoAndReturn(func(ctx context.Context, ops []*BatchOperation,
+						atomic bool) ([]*BatchResult, error) {
+						return []*BatchResult{{Err: ops[0].Invalid}, {}}, nil
+					})
+			},
+			wantStatus: http.StatusMultiStatus,
+			wantBody: `{"index":1,"status":204}],` +
+				`"succeeded":1,"failed":1}`,
+		},
+		{
+			name:       "too many operations",
+			rawBody:    `{"operations":[{"op":"delete"},{"op":"delete"},{"op":"delete"}]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "a batch needs between 1 and 2 operations",
+		},
+		{
+			name:       "invalid json",
+			rawBody:    `{"operations":`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "invalid request body",
+		},
+		{
+			name:    "transaction failed",
+			rawBody: `{"operations":[{"op":"delete","id":"5faeb0b2-43b3-4a7a-aaf2-77b71ea59d90"}]}`,
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().ApplyBatch(gomock.Any(), gomock.Any(), true).
+					Return(nil, errors.New("commit failed"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "commit failed",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := NewMockService(ctrl)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockService)
+			}
+			handler := NewHandler(mockService)
+			handler.MaxBatchSize = 2
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/users:batch", handler.Batch)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/users:batch",
+				strings.NewReader(test.rawBody))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantBody)
+		})
+	}
+}
+
+// setupFollowRouter acts as actor in every request unless actor is nil.
+func setupFollowRouter(t *testing.T, actor *uuid.UUID) (*gin.Engine, *MockService) {
+	ctrl := gomock.NewController(t)
+	mockService := NewMockService(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if actor != nil {
+		router.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(
+				auth.WithUser(c.Request.Context(), *actor))
+		})
+	}
+	NewHandler(mockService).RegisterRoutes(router.Group("/users"))
+	return router, mockService
+}
+
+func TestHandler_Follow(t *testing.T) {
+	actor := uuid.New()
+	followee := uuid.New()
+	tests := []struct {
+		name          string
+		method        string
+		actor         *uuid.UUID
+		id            string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantErr       string
+	}{
+		{
+			name:   "follow",
+			method: http.MethodPost,
+			actor:  &actor,
+			id:     followee.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().Follow(gomock.Any(), actor, followee).
+					Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "unfollow",
+			method: http.MethodDelete,
+			actor:  &actor,
+			id:     followee.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().Unfollow(gomock.Any(), actor, followee).
+					Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "key without user",
+			method:     http.MethodPost,
+			id:         followee.String(),
+			wantStatus: http.StatusForbidden,
+			wantErr:    "the API key is not linked to a user",
+		},
+		{
+			name:       "not an uuid",
+			method:     http.MethodPost,
+			actor:      &actor,
+			id:         "abc",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "ID must be a uuid",
+		},
+		{
+			name:   "unknown followee",
+			method: http.MethodPost,
+			actor:  &actor,
+			id:     followee.String(),
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().Follow(gomock.Any(), actor, followee).
+					Return(apperrors.NewNotFoundError("user", followee))
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    "not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, mockService := setupFollowRouter(t, test.actor)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockService)
+			}
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(test.method,
+				"/users/"+test.id+"/follow", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			if test.wantErr != "" {
+				assert.Contains(t, w.Body.String(), test.wantErr)
+			} else {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_GetFollowers(t *testing.T) {
+	id := uuid.New()
+	follower := &model.User{ID: uuid.New(), Username: "dave"}
+	followedAt := time.Date(2025, 7, 18, 15, 4, 5, 0, time.UTC)
+	page := query.Page{Number: 2, PerPage: 1, Sort: "followed_at", Desc: true}
+	router, mockService := setupFollowRouter(t, nil)
+	mockService.EXPECT().GetFollowers(gomock.Any(), id, page).
+		Return([]*model.Follow{{FollowerID: follower.ID, FolloweeID: id,
+			CreatedAt: followedAt, Follower: follower}}, int64(2), nil)
+	mockService.EXPECT().GetFollowing(gomock.Any(), id, gomock.Any()).
+		Return(nil, int64(0), apperrors.NewNotFoundError("user", id))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet,
+		"/users/"+id.String()+"/followers?page=2&per_page=1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"items":[{"user_id":%q,"username":"dave",
+		"followed_at":"2025-07-18T15:04:05Z"}],"page":2,"per_page":1,
+		"total":2}`, follower.ID), w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet,
+		"/users/"+id.String()+"/following", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandler_ChangeUser_Ownership(t *testing.T) {
+	self := uuid.New()
+	other := uuid.New()
+	tests := []struct {
+		name          string
+		method        string
+		body          string
+		mockBehaviour func(repo *MockRepository)
+		id            uuid.UUID
+		wantStatus    int
+		wantErr       string
+	}{
+		{
+			name:       "update other user",
+			method:     http.MethodPatch,
+			body:       `{"username":"takenover"}`,
+			id:         other,
+			wantStatus: http.StatusForbidden,
+			wantErr:    "may only change its own user's resources",
+		},
+		{
+			name:       "delete other user",
+			method:     http.MethodDelete,
+			id:         other,
+			wantStatus: http.StatusForbidden,
+			wantErr:    "may only change its own user's resources",
+		},
+		{
+			name:   "delete self",
+			method: http.MethodDelete,
+			id:     self,
+			mockBehaviour: func(repo *MockRepository) {
+				user := &model.User{ID: self, Username: "self"}
+				repo.EXPECT().FindByID(gomock.Any(), self, gomock.Any()).
+					Return(user, nil)
+				repo.EXPECT().Delete(gomock.Any(), user).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockRepository(ctrl)
+			if test.mockBehaviour != nil {
+				test.mockBehaviour(mockRepo)
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Request = c.Request.WithContext(
+					auth.WithUser(c.Request.Context(), self))
+			})
+			NewHandler(NewService(mockRepo)).RegisterRoutes(router.Group("/users"))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(test.method, "/users/"+test.id.String(),
+				strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.wantErr)
+		})
+	}
+}
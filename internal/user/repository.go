@@ -1,63 +1,137 @@
 package user
 
 import (
+	"context"
 	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //go:generate mockgen -source=repository.go -destination=repository_mock.go -package=user
 type Repository interface {
-	FindAll() ([]*model.User, error)
-	FindByID(id uuid.UUID) (*model.User, error)
-	FindByUsername(username string) (*model.User, error)
-	FindByEmail(email string) (*model.User, error)
-	Create(user *model.User) (*model.User, error)
-	Delete(user *model.User) error
-	Update(user *model.User) (*model.User, error)
+	FindAll(ctx context.Context, opts query.Options) ([]*model.User, error)
+	FindByID(ctx context.Context, id uuid.UUID, opts query.Options) (*model.User, error)
+	FindByUsername(ctx context.Context, username string) (*model.User, error)
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	Create(ctx context.Context, user *model.User) (*model.User, error)
+	Delete(ctx context.Context, user *model.User) error
+	Update(ctx context.Context, user *model.User) (*model.User, error)
+	// CreateFollow stores f unless the follow exists already.
+	CreateFollow(ctx context.Context, f *model.Follow) error
+	DeleteFollow(ctx context.Context, followerID uuid.UUID, followeeID uuid.UUID) error
+	// FindFollowers returns one page of the follows of the user with the
+	// follower loaded and the number of followers in total.
+	FindFollowers(ctx context.Context, id uuid.UUID, page query.Page) ([]*model.Follow, int64, error)
+	// FindFollowing returns one page of the follows by the user with the
+	// followee loaded and the number of users followed in total.
+	FindFollowing(ctx context.Context, id uuid.UUID, page query.Page) ([]*model.Follow, int64, error)
+	// Transaction runs fn with a repository whose queries share one
+	// transaction, committed if fn returns nil and rolled back otherwise.
+	Transaction(ctx context.Context, fn func(repo Repository) error) error
 }
 
 type repository struct {
 	db *gorm.DB
 }
 
-func (r *repository) FindAll() ([]*model.User, error) {
+// preload loads the relations expanded in opts.
+func preload(db *gorm.DB, opts query.Options) *gorm.DB {
+	if opts.Expands(expandPosts) {
+		db = db.Preload("Posts")
+	}
+	return db
+}
+
+func (r *repository) FindAll(ctx context.Context, opts query.Options) ([]*model.User, error) {
 	var users []*model.User
-	err := r.db.Preload("Posts").Find(&users).Error
+	err := preload(r.db.WithContext(ctx), opts).Find(&users).Error
 	return users, err
 }
 
-func (r *repository) FindByID(id uuid.UUID) (*model.User, error) {
+func (r *repository) FindByID(ctx context.Context, id uuid.UUID, opts query.Options) (*model.User, error) {
 	var user model.User
-	err := r.db.Preload("Posts").First(&user, id).Error
+	err := preload(r.db.WithContext(ctx), opts).First(&user, id).Error
 	return &user, err
 }
 
-func (r *repository) FindByUsername(username string) (*model.User, error) {
+func (r *repository) FindByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
-	err := r.db.Where("username = ?", username).First(&user).Error
+	err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
 	return &user, err
 }
 
-func (r *repository) FindByEmail(email string) (*model.User, error) {
+func (r *repository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	return &user, err
 }
 
-func (r *repository) Create(user *model.User) (*model.User, error) {
-	err := r.db.Preload("Posts").Create(&user).Error
-	return user, err
+func (r *repository) Create(ctx context.Context, user *model.User) (*model.User, error) {
+	err := r.db.WithContext(ctx).Preload("Posts").Create(&user).Error
+	return user, database.TranslateError(err)
+}
+
+func (r *repository) Update(ctx context.Context, user *model.User) (*model.User, error) {
+	err := r.db.WithContext(ctx).Preload("Posts").Save(&user).Error
+	return user, database.TranslateError(err)
+}
+
+func (r *repository) Delete(ctx context.Context, user *model.User) error {
+	err := r.db.WithContext(ctx).Delete(&user).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) CreateFollow(ctx context.Context, f *model.Follow) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(f).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) DeleteFollow(ctx context.Context, followerID uuid.UUID,
+	followeeID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).
+		Delete(&model.Follow{}).Error
+}
+
+func (r *repository) FindFollowers(ctx context.Context, id uuid.UUID,
+	page query.Page) ([]*model.Follow, int64, error) {
+	return r.findFollows(ctx, "followee_id", "Follower", id, page)
+}
+
+func (r *repository) FindFollowing(ctx context.Context, id uuid.UUID,
+	page query.Page) ([]*model.Follow, int64, error) {
+	return r.findFollows(ctx, "follower_id", "Followee", id, page)
 }
 
-func (r *repository) Update(user *model.User) (*model.User, error) {
-	err := r.db.Preload("Posts").Save(&user).Error
-	return user, err
+// findFollows loads one page of the follows whose column is id, with the
+// user on the other side preloaded as relation.
+func (r *repository) findFollows(ctx context.Context, column string,
+	relation string, id uuid.UUID, page query.Page) ([]*model.Follow, int64, error) {
+	db := r.db.WithContext(ctx).Model(&model.Follow{}).
+		Where(column+" = ?", id).Session(&gorm.Session{})
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var follows []*model.Follow
+	err := db.Preload(relation).
+		Order(clause.OrderByColumn{
+			Column: clause.Column{Name: "created_at"}, Desc: page.Desc}).
+		Order("follower_id").Order("followee_id").
+		Limit(page.PerPage).
+		Offset(page.Offset()).
+		Find(&follows).Error
+	return follows, total, err
 }
 
-func (r *repository) Delete(user *model.User) error {
-	err := r.db.Delete(&user).Error
-	return err
+func (r *repository) Transaction(ctx context.Context, fn func(repo Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repository{db: tx})
+	})
 }
 
 func NewRepository(db *gorm.DB) Repository {
@@ -5,10 +5,12 @@
 package user
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
+	query "github.com/pandahawk/blog-api/internal/query"
 	model "github.com/pandahawk/blog-api/internal/shared/model"
 )
 
@@ -36,105 +38,179 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockRepository) Create(user *model.User) (*model.User, error) {
+func (m *MockRepository) Create(ctx context.Context, user *model.User) (*model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", user)
+	ret := m.ctrl.Call(m, "Create", ctx, user)
 	ret0, _ := ret[0].(*model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockRepositoryMockRecorder) Create(user interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Create(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, user)
+}
+
+// CreateFollow mocks base method.
+func (m *MockRepository) CreateFollow(ctx context.Context, f *model.Follow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFollow", ctx, f)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFollow indicates an expected call of CreateFollow.
+func (mr *MockRepositoryMockRecorder) CreateFollow(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFollow", reflect.TypeOf((*MockRepository)(nil).CreateFollow), ctx, f)
 }
 
 // Delete mocks base method.
-func (m *MockRepository) Delete(user *model.User) error {
+func (m *MockRepository) Delete(ctx context.Context, user *model.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", user)
+	ret := m.ctrl.Call(m, "Delete", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRepositoryMockRecorder) Delete(user interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, user)
+}
+
+// DeleteFollow mocks base method.
+func (m *MockRepository) DeleteFollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFollow", ctx, followerID, followeeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFollow indicates an expected call of DeleteFollow.
+func (mr *MockRepositoryMockRecorder) DeleteFollow(ctx, followerID, followeeID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFollow", reflect.TypeOf((*MockRepository)(nil).DeleteFollow), ctx, followerID, followeeID)
 }
 
 // FindAll mocks base method.
-func (m *MockRepository) FindAll() ([]*model.User, error) {
+func (m *MockRepository) FindAll(ctx context.Context, opts query.Options) ([]*model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll")
+	ret := m.ctrl.Call(m, "FindAll", ctx, opts)
 	ret0, _ := ret[0].([]*model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockRepositoryMockRecorder) FindAll() *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindAll(ctx, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepository)(nil).FindAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepository)(nil).FindAll), ctx, opts)
 }
 
 // FindByEmail mocks base method.
-func (m *MockRepository) FindByEmail(email string) (*model.User, error) {
+func (m *MockRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByEmail", email)
+	ret := m.ctrl.Call(m, "FindByEmail", ctx, email)
 	ret0, _ := ret[0].(*model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByEmail indicates an expected call of FindByEmail.
-func (mr *MockRepositoryMockRecorder) FindByEmail(email interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindByEmail(ctx, email interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockRepository)(nil).FindByEmail), email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockRepository)(nil).FindByEmail), ctx, email)
 }
 
 // FindByID mocks base method.
-func (m *MockRepository) FindByID(id uuid.UUID) (*model.User, error) {
+func (m *MockRepository) FindByID(ctx context.Context, id uuid.UUID, opts query.Options) (*model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByID", id)
+	ret := m.ctrl.Call(m, "FindByID", ctx, id, opts)
 	ret0, _ := ret[0].(*model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByID indicates an expected call of FindByID.
-func (mr *MockRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindByID(ctx, id, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockRepository)(nil).FindByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockRepository)(nil).FindByID), ctx, id, opts)
 }
 
 // FindByUsername mocks base method.
-func (m *MockRepository) FindByUsername(username string) (*model.User, error) {
+func (m *MockRepository) FindByUsername(ctx context.Context, username string) (*model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByUsername", username)
+	ret := m.ctrl.Call(m, "FindByUsername", ctx, username)
 	ret0, _ := ret[0].(*model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByUsername indicates an expected call of FindByUsername.
-func (mr *MockRepositoryMockRecorder) FindByUsername(username interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindByUsername(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUsername", reflect.TypeOf((*MockRepository)(nil).FindByUsername), ctx, username)
+}
+
+// FindFollowers mocks base method.
+func (m *MockRepository) FindFollowers(ctx context.Context, id uuid.UUID, page query.Page) ([]*model.Follow, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowers", ctx, id, page)
+	ret0, _ := ret[0].([]*model.Follow)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindFollowers indicates an expected call of FindFollowers.
+func (mr *MockRepositoryMockRecorder) FindFollowers(ctx, id, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowers", reflect.TypeOf((*MockRepository)(nil).FindFollowers), ctx, id, page)
+}
+
+// FindFollowing mocks base method.
+func (m *MockRepository) FindFollowing(ctx context.Context, id uuid.UUID, page query.Page) ([]*model.Follow, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowing", ctx, id, page)
+	ret0, _ := ret[0].([]*model.Follow)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindFollowing indicates an expected call of FindFollowing.
+func (mr *MockRepositoryMockRecorder) FindFollowing(ctx, id, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowing", reflect.TypeOf((*MockRepository)(nil).FindFollowing), ctx, id, page)
+}
+
+// Transaction mocks base method.
+func (m *MockRepository) Transaction(ctx context.Context, fn func(Repository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockRepositoryMockRecorder) Transaction(ctx, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUsername", reflect.TypeOf((*MockRepository)(nil).FindByUsername), username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockRepository)(nil).Transaction), ctx, fn)
 }
 
 // Update mocks base method.
-func (m *MockRepository) Update(user *model.User) (*model.User, error) {
+func (m *MockRepository) Update(ctx context.Context, user *model.User) (*model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", user)
+	ret := m.ctrl.Call(m, "Update", ctx, user)
 	ret0, _ := ret[0].(*model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRepositoryMockRecorder) Update(user interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, user)
 }
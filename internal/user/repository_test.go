@@ -1,7 +1,9 @@
 package user
 
 import (
+	"context"
 	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
 	"github.com/pandahawk/blog-api/internal/shared/testdata"
 	"github.com/stretchr/testify/assert"
@@ -13,7 +15,7 @@ func TestRepository_FindAll(t *testing.T) {
 	db := database.SetupTestDB(t)
 	repo := NewRepository(db)
 
-	users, err := repo.FindAll()
+	users, err := repo.FindAll(context.Background(), query.Options{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, len(testdata.SampleUsers), len(users))
@@ -27,11 +29,26 @@ func TestRepository_FindAll(t *testing.T) {
 func TestRepository_FindByID(t *testing.T) {
 	db := database.SetupTestDB(t)
 	repo := NewRepository(db)
-	got, err := repo.FindByID(testdata.Alice.ID)
+	got, err := repo.FindByID(context.Background(), testdata.Alice.ID,
+		query.Options{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, testdata.Alice.ID, got.ID)
 	assert.Equal(t, testdata.Alice.Username, got.Username)
+	assert.Nil(t, got.Posts)
+}
+
+func TestRepository_FindByID_ExpandPosts(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	got, err := repo.FindByID(context.Background(), testdata.Alice.ID,
+		query.Options{Expand: []string{expandPosts}})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, got.Posts)
+	for _, p := range got.Posts {
+		assert.Equal(t, testdata.Alice.ID, p.UserID)
+	}
 }
 
 func TestRepository_FindByUsername(t *testing.T) {
@@ -39,7 +56,7 @@ func TestRepository_FindByUsername(t *testing.T) {
 	repo := NewRepository(db)
 	username := "bob"
 
-	got, err := repo.FindByUsername(username)
+	got, err := repo.FindByUsername(context.Background(), username)
 
 	assert.NoError(t, err)
 	assert.Equal(t, testdata.Bob.Username, got.Username)
@@ -48,7 +65,7 @@ func TestRepository_FindByUsername(t *testing.T) {
 func TestRepository_FindByEmail(t *testing.T) {
 	db := database.SetupTestDB(t)
 	repo := NewRepository(db)
-	got, err := repo.FindByEmail(testdata.Caren.Email)
+	got, err := repo.FindByEmail(context.Background(), testdata.Caren.Email)
 
 	require.NoError(t, err)
 	assert.Equal(t, testdata.Caren.Email, got.Email)
@@ -59,13 +76,13 @@ func TestRepository_Create(t *testing.T) {
 	repo := NewRepository(db)
 	user := model.NewUser("newuser", "newemail@mail.com")
 
-	got, err := repo.Create(user)
+	got, err := repo.Create(context.Background(), user)
 
 	assert.NoError(t, err)
 	assert.Equal(t, user.Username, got.Username)
 	assert.Equal(t, user.Email, got.Email)
 
-	got, err = repo.Create(user)
+	got, err = repo.Create(context.Background(), user)
 	assert.Error(t, err)
 }
 
@@ -76,7 +93,7 @@ func TestRepository_Update(t *testing.T) {
 	aliceUpdate.Username = "newname"
 	aliceUpdate.Email = "newmail@example.com"
 
-	got, err := repo.Update(&aliceUpdate)
+	got, err := repo.Update(context.Background(), &aliceUpdate)
 
 	require.NoError(t, err)
 	assert.Equal(t, aliceUpdate.Username, got.Username)
@@ -85,6 +102,38 @@ func TestRepository_Update(t *testing.T) {
 func TestRepository_Delete(t *testing.T) {
 	db := database.SetupTestDB(t)
 	repo := NewRepository(db)
-	err := repo.Delete(testdata.Alice)
+	err := repo.Delete(context.Background(), testdata.Alice)
 	assert.NoError(t, err)
 }
+
+func TestRepository_Follows(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	page := query.Page{Number: 1, PerPage: 10, Desc: true}
+
+	require.NoError(t, repo.CreateFollow(ctx,
+		model.NewFollow(testdata.Dave.ID, testdata.Alice.ID)))
+	require.NoError(t, repo.CreateFollow(ctx,
+		model.NewFollow(testdata.Dave.ID, testdata.Alice.ID)))
+	require.NoError(t, repo.CreateFollow(ctx,
+		model.NewFollow(testdata.Dave.ID, testdata.Bob.ID)))
+
+	followers, total, err := repo.FindFollowers(ctx, testdata.Alice.ID, page)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, followers, 1)
+	assert.Equal(t, testdata.Dave.Username, followers[0].Follower.Username)
+
+	following, total, err := repo.FindFollowing(ctx, testdata.Dave.ID, page)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, following, 2)
+	assert.Equal(t, testdata.Bob.ID, following[0].Followee.ID)
+
+	require.NoError(t, repo.DeleteFollow(ctx, testdata.Dave.ID,
+		testdata.Alice.ID))
+	_, total, err = repo.FindFollowers(ctx, testdata.Alice.ID, page)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
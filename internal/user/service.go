@@ -1,24 +1,41 @@
 package user
 
 import (
+	"context"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/metrics"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
 	"regexp"
 	"strconv"
-	"strings"
 	"unicode"
 )
 
 //go:generate mockgen -source=service.go -destination=service_mock.go -package=user
 
 type Service interface {
-	GetUser(id uuid.UUID) (*model.User, error)
-	CreateUser(req *CreateUserRequest) (*model.User, error)
-	GetUsers() ([]*model.User, error)
-	UpdateUser(id uuid.UUID, req *UpdateUserRequest) (*model.User, error)
-	DeleteUser(id uuid.UUID) error
+	GetUser(ctx context.Context, id uuid.UUID, opts query.Options) (*model.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*model.User, error)
+	CreateUser(ctx context.Context, req *CreateUserRequest) (*model.User, error)
+	GetUsers(ctx context.Context, opts query.Options) ([]*model.User, error)
+	UpdateUser(ctx context.Context, id uuid.UUID, req *UpdateUserRequest) (*model.User, error)
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	// ApplyBatch applies ops in order and returns the result of each. In
+	// atomic mode one failed operation rolls back all others.
+	ApplyBatch(ctx context.Context, ops []*BatchOperation, atomic bool) ([]*BatchResult, error)
+	// Follow makes followerID follow followeeID. Following twice is not an
+	// error.
+	Follow(ctx context.Context, followerID uuid.UUID, followeeID uuid.UUID) error
+	// Unfollow ends a follow. Ending one that does not exist is not an
+	// error.
+	Unfollow(ctx context.Context, followerID uuid.UUID, followeeID uuid.UUID) error
+	GetFollowers(ctx context.Context, id uuid.UUID, page query.Page) ([]*model.Follow, int64, error)
+	GetFollowing(ctx context.Context, id uuid.UUID, page query.Page) ([]*model.Follow, int64, error)
 }
 
 type service struct {
@@ -53,29 +70,30 @@ func validateUsernameFormat(username string) error {
 	return nil
 }
 
-func (s *service) CreateUser(req *CreateUserRequest) (*model.User, error) {
-
-	if err := validateUsernameFormat(req.Username); err != nil {
+func (s *service) CreateUser(ctx context.Context, req *CreateUserRequest) (*model.User, error) {
+	user, err := s.createUser(ctx, req)
+	if err != nil {
 		return nil, err
 	}
+	metrics.UsersCreated.Inc()
+	return user, nil
+}
 
-	user, err := s.repo.Create(model.NewUser(req.Username, req.Email))
-	if err != nil {
-		if strings.Contains(err.Error(),
-			`violates unique constraint "uni_users_username"`) {
-			return nil, apperrors.NewDuplicateError("username")
-		}
-		if strings.Contains(err.Error(),
-			`violates unique constraint "uni_users_email"`) {
-			return nil, apperrors.NewDuplicateError("email")
-		}
+// createUser creates the user without counting it as created, so a batch
+// can count its users once the transaction has committed.
+func (s *service) createUser(ctx context.Context, req *CreateUserRequest) (*model.User, error) {
+	if err := validateUsernameFormat(req.Username); err != nil {
 		return nil, err
 	}
-	return user, nil
+	return s.repo.Create(ctx, model.NewUser(req.Username, req.Email))
 }
 
-func (s *service) UpdateUser(id uuid.UUID, req *UpdateUserRequest) (*model.User, error) {
-	user, err := s.repo.FindByID(id)
+func (s *service) UpdateUser(ctx context.Context, id uuid.UUID, req *UpdateUserRequest) (*model.User, error) {
+	if err := auth.RequireOwner(ctx, id); err != nil {
+		return nil, err
+	}
+	user, err := s.repo.FindByID(ctx, id,
+		query.Options{Expand: []string{expandPosts}})
 	if err != nil {
 		return nil, apperrors.NewNotFoundError("user", id)
 	}
@@ -84,52 +102,135 @@ func (s *service) UpdateUser(id uuid.UUID, req *UpdateUserRequest) (*model.User,
 		if err := validateUsernameFormat(*req.Username); err != nil {
 			return nil, err
 		}
-		if _, err := s.repo.FindByUsername(*req.Username); err == nil {
-			return nil, apperrors.NewDuplicateError("username already exists")
+		if _, err := s.repo.FindByUsername(ctx, *req.Username); err == nil {
+			return nil, apperrors.NewDuplicateError("username")
 		}
 
 		user.Username = *req.Username
 	}
 
 	if req.Email != nil {
-		if _, err := s.repo.FindByEmail(*req.Email); err == nil {
+		if _, err := s.repo.FindByEmail(ctx, *req.Email); err == nil {
 			return nil, apperrors.NewDuplicateError("email")
 		}
 		user.Email = *req.Email
 	}
 
-	return s.repo.Update(user)
+	return s.repo.Update(ctx, user)
 }
 
-func (s *service) DeleteUser(id uuid.UUID) error {
-	user, err := s.repo.FindByID(id)
+func (s *service) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	if err := auth.RequireOwner(ctx, id); err != nil {
+		return err
+	}
+	user, err := s.repo.FindByID(ctx, id, query.Options{})
 	if err != nil {
 		return apperrors.NewNotFoundError("user", id)
 	}
 
-	if err := s.repo.Delete(user); err != nil {
+	if err := s.repo.Delete(ctx, user); err != nil {
+		logging.FromContext(ctx).Error("delete user",
+			"user_id", id, "error", err)
 		return errors.New("failed to delete user")
 	}
 	return nil
 }
 
-func (s *service) GetUser(id uuid.UUID) (*model.User, error) {
-	user, err := s.repo.FindByID(id)
+func (s *service) GetUser(ctx context.Context, id uuid.UUID, opts query.Options) (*model.User, error) {
+	user, err := s.repo.FindByID(ctx, id, opts)
 	if err != nil {
 		return nil, apperrors.NewNotFoundError("user", id)
 	}
 	return user, nil
 }
 
-func (s *service) GetUsers() ([]*model.User, error) {
+func (s *service) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	user, err := s.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, apperrors.NewNotFoundByNameError("user", username)
+	}
+	return user, nil
+}
+
+func (s *service) GetUsers(ctx context.Context, opts query.Options) ([]*model.User, error) {
 
-	users, err := s.repo.FindAll()
+	users, err := s.repo.FindAll(ctx, opts)
 	if err != nil {
+		logging.FromContext(ctx).Error("find users", "error", err)
 		return nil, errors.New("failed to get all users")
 	}
 	return users, nil
 }
 
+// checkUser returns a NotFoundError when the user does not exist.
+func (s *service) checkUser(ctx context.Context, id uuid.UUID) error {
+	_, err := s.repo.FindByID(ctx, id, query.Options{})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperrors.NewNotFoundError("user", id)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("find user", "user_id", id,
+			"error", err)
+		return errors.New("db error")
+	}
+	return nil
+}
+
+func (s *service) Follow(ctx context.Context, followerID uuid.UUID,
+	followeeID uuid.UUID) error {
+	if followerID == followeeID {
+		return apperrors.NewInvalidInputError("users cannot follow themselves")
+	}
+	if err := s.checkUser(ctx, followeeID); err != nil {
+		return err
+	}
+	err := s.repo.CreateFollow(ctx, model.NewFollow(followerID, followeeID))
+	var re *apperrors.ReferenceNotFoundError
+	if errors.As(err, &re) {
+		// The followee exists, so the follower was deleted meanwhile.
+		return apperrors.NewNotFoundError("user", followerID)
+	}
+	return err
+}
+
+func (s *service) Unfollow(ctx context.Context, followerID uuid.UUID,
+	followeeID uuid.UUID) error {
+	if err := s.checkUser(ctx, followeeID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteFollow(ctx, followerID, followeeID); err != nil {
+		logging.FromContext(ctx).Error("delete follow",
+			"followee_id", followeeID, "error", err)
+		return errors.New("db error")
+	}
+	return nil
+}
+
+func (s *service) GetFollowers(ctx context.Context, id uuid.UUID,
+	page query.Page) ([]*model.Follow, int64, error) {
+	return s.findFollows(ctx, id, page, s.repo.FindFollowers)
+}
+
+func (s *service) GetFollowing(ctx context.Context, id uuid.UUID,
+	page query.Page) ([]*model.Follow, int64, error) {
+	return s.findFollows(ctx, id, page, s.repo.FindFollowing)
+}
+
+func (s *service) findFollows(ctx context.Context, id uuid.UUID,
+	page query.Page, find func(context.Context, uuid.UUID,
+		query.Page) ([]*model.Follow, int64, error)) ([]*model.Follow, int64, error) {
+	if err := s.checkUser(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	follows, total, err := find(ctx, id, page)
+	if err != nil {
+		logging.FromContext(ctx).Error("find follows", "user_id", id,
+			"error", err)
+		return nil, 0, errors.New("db error")
+	}
+	return follows, total, nil
+}
+
 func NewService(r Repository) Service {
 	return &service{repo: r}
 }
@@ -1,12 +1,18 @@
 package user
 
 import (
+	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/batch"
+	"github.com/pandahawk/blog-api/internal/metrics"
+	"github.com/pandahawk/blog-api/internal/query"
 	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 	"testing"
 )
 
@@ -39,7 +45,7 @@ func TestService_CreateUser(t *testing.T) {
 			},
 			want: model.NewUser("testuser01", "testuser01@example.com"),
 			expectMock: func(repo *MockRepository, want *model.User) {
-				repo.EXPECT().Create(gomock.Any()).Return(want, nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(want, nil)
 			},
 			wantErr: "",
 		},
@@ -61,7 +67,7 @@ func TestService_CreateUser(t *testing.T) {
 			},
 			want: nil,
 			expectMock: func(repo *MockRepository, want *model.User) {
-				repo.EXPECT().Create(gomock.Any()).Return(nil, errors.New("db error"))
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
 			},
 			wantErr: "db error",
 		},
@@ -73,8 +79,8 @@ func TestService_CreateUser(t *testing.T) {
 			},
 			want: nil,
 			expectMock: func(repo *MockRepository, want *model.User) {
-				repo.EXPECT().Create(gomock.Any()).
-					Return(nil, errors.New(`violates unique constraint "uni_users_username"`))
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(nil, apperrors.NewDuplicateError("username"))
 			},
 			wantErr: "username already exists",
 		},
@@ -86,8 +92,8 @@ func TestService_CreateUser(t *testing.T) {
 			},
 			want: nil,
 			expectMock: func(repo *MockRepository, want *model.User) {
-				repo.EXPECT().Create(gomock.Any()).
-					Return(nil, errors.New(`violates unique constraint "uni_users_email"`))
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(nil, apperrors.NewDuplicateError("email"))
 			},
 			wantErr: "email already exists",
 		},
@@ -100,7 +106,7 @@ func TestService_CreateUser(t *testing.T) {
 				test.expectMock(mockRepo, test.want)
 			}
 
-			got, err := service.CreateUser(test.req)
+			got, err := service.CreateUser(context.Background(), test.req)
 
 			if test.wantErr == "" {
 				assert.NoError(t, err)
@@ -140,10 +146,10 @@ func TestService_UpdateUser(t *testing.T) {
 				Email:    "updatedtestuser01@example.com",
 			},
 			expectMock: func(mockRepo *MockRepository, old, want *model.User) {
-				mockRepo.EXPECT().FindByID(gomock.Any()).Return(old, nil)
-				mockRepo.EXPECT().FindByUsername(gomock.Any()).Return(nil, errors.New("user not found"))
-				mockRepo.EXPECT().FindByEmail(gomock.Any()).Return(nil, errors.New("email not found"))
-				mockRepo.EXPECT().Update(gomock.Any()).Return(want, nil)
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(old, nil)
+				mockRepo.EXPECT().FindByUsername(gomock.Any(), gomock.Any()).Return(nil, errors.New("user not found"))
+				mockRepo.EXPECT().FindByEmail(gomock.Any(), gomock.Any()).Return(nil, errors.New("email not found"))
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(want, nil)
 			},
 			wantErr: "",
 		},
@@ -160,7 +166,7 @@ func TestService_UpdateUser(t *testing.T) {
 			},
 			want: nil,
 			expectMock: func(mockRepo *MockRepository, old, want *model.User) {
-				mockRepo.EXPECT().FindByID(gomock.Any()).
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, apperrors.NewNotFoundError("user", old.ID))
 			},
 			wantErr: apperrors.NewNotFoundError("user", id).Error(),
@@ -178,8 +184,8 @@ func TestService_UpdateUser(t *testing.T) {
 			},
 			want: nil,
 			expectMock: func(mockRepo *MockRepository, old, want *model.User) {
-				mockRepo.EXPECT().FindByID(gomock.Any()).Return(old, nil)
-				mockRepo.EXPECT().FindByUsername(gomock.Any()).
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(old, nil)
+				mockRepo.EXPECT().FindByUsername(gomock.Any(), gomock.Any()).
 					Return(old, nil)
 			},
 			wantErr: "username already exists",
@@ -197,9 +203,9 @@ func TestService_UpdateUser(t *testing.T) {
 			},
 			want: nil,
 			expectMock: func(mockRepo *MockRepository, old, want *model.User) {
-				mockRepo.EXPECT().FindByID(gomock.Any()).Return(old, nil)
-				mockRepo.EXPECT().FindByUsername(gomock.Any()).Return(nil, errors.New("user not found"))
-				mockRepo.EXPECT().FindByEmail(gomock.Any()).
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(old, nil)
+				mockRepo.EXPECT().FindByUsername(gomock.Any(), gomock.Any()).Return(nil, errors.New("user not found"))
+				mockRepo.EXPECT().FindByEmail(gomock.Any(), gomock.Any()).
 					Return(&model.User{}, nil)
 			},
 			wantErr: "email already exists",
@@ -217,7 +223,7 @@ func TestService_UpdateUser(t *testing.T) {
 			},
 			want: nil,
 			expectMock: func(mockRepo *MockRepository, old, want *model.User) {
-				mockRepo.EXPECT().FindByID(gomock.Any()).Return(old, nil)
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(old, nil)
 			},
 			wantErr: "invalid username: must be alphanumeric, at least 3 character",
 		},
@@ -230,7 +236,7 @@ func TestService_UpdateUser(t *testing.T) {
 				test.expectMock(mockRepo, test.old, test.want)
 			}
 
-			got, err := service.UpdateUser(id, test.req)
+			got, err := service.UpdateUser(context.Background(), id, test.req)
 
 			if test.wantErr == "" {
 				assert.NoError(t, err)
@@ -259,7 +265,7 @@ func TestService_GetUser(t *testing.T) {
 				Email:    "testuser01@example.com",
 			},
 			expectMock: func(mockRepo *MockRepository, want *model.User) {
-				mockRepo.EXPECT().FindByID(gomock.Any()).Return(want, nil)
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(want, nil)
 			},
 			wantErr: "",
 		},
@@ -267,7 +273,7 @@ func TestService_GetUser(t *testing.T) {
 			name: "user not found",
 			want: nil,
 			expectMock: func(mockRepo *MockRepository, want *model.User) {
-				mockRepo.EXPECT().FindByID(gomock.Any()).
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("user not found"))
 			},
 			wantErr: apperrors.NewNotFoundError("user", id).Error(),
@@ -279,7 +285,7 @@ func TestService_GetUser(t *testing.T) {
 			if test.expectMock != nil {
 				test.expectMock(mockRepo, test.want)
 			}
-			got, err := service.GetUser(id)
+			got, err := service.GetUser(context.Background(), id, query.Options{})
 			if test.wantErr == "" {
 				assert.NoError(t, err)
 				assert.Equal(t, test.want, got)
@@ -292,6 +298,49 @@ func TestService_GetUser(t *testing.T) {
 	}
 }
 
+func TestService_GetUserByUsername(t *testing.T) {
+	want := &model.User{ID: uuid.New(), Username: "alice"}
+	tests := []struct {
+		name       string
+		expectMock func(mockRepo *MockRepository)
+		want       *model.User
+		wantErr    string
+	}{
+		{
+			name: "success",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindByUsername(gomock.Any(), "alice").
+					Return(want, nil)
+			},
+			want: want,
+		},
+		{
+			name: "user not found",
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindByUsername(gomock.Any(), "alice").
+					Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: `user "alice" not found`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setupMockRepoAndService(t)
+			test.expectMock(mockRepo)
+
+			got, err := service.GetUserByUsername(context.Background(), "alice")
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			} else {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
 func TestService_DeleteUser(t *testing.T) {
 	id := uuid.New()
 	tests := []struct {
@@ -304,8 +353,8 @@ func TestService_DeleteUser(t *testing.T) {
 			name: "success",
 			id:   id,
 			expectMock: func(mockRepo *MockRepository) {
-				mockRepo.EXPECT().Delete(gomock.Any()).Return(nil)
-				mockRepo.EXPECT().FindByID(gomock.Any()).Return(&model.User{}, nil)
+				mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(&model.User{}, nil)
 			},
 			wantErr: "",
 		},
@@ -313,7 +362,7 @@ func TestService_DeleteUser(t *testing.T) {
 			name: "user not found",
 			id:   id,
 			expectMock: func(mockRepo *MockRepository) {
-				mockRepo.EXPECT().FindByID(gomock.Any()).
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(&model.User{}, errors.New("user not found"))
 			},
 			wantErr: "not found",
@@ -322,9 +371,9 @@ func TestService_DeleteUser(t *testing.T) {
 			name: "deletion failed",
 			id:   id,
 			expectMock: func(mockRepo *MockRepository) {
-				mockRepo.EXPECT().Delete(gomock.Any()).
+				mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).
 					Return(errors.New("failed to delete user"))
-				mockRepo.EXPECT().FindByID(gomock.Any()).
+				mockRepo.EXPECT().FindByID(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(&model.User{}, nil)
 			},
 			wantErr: "failed to delete user",
@@ -337,7 +386,7 @@ func TestService_DeleteUser(t *testing.T) {
 				test.expectMock(mockRepo)
 			}
 
-			err := service.DeleteUser(id)
+			err := service.DeleteUser(context.Background(), id)
 
 			if test.wantErr == "" {
 				assert.NoError(t, err)
@@ -359,7 +408,7 @@ func TestService_GetUsers(t *testing.T) {
 			name: "success",
 			want: []*model.User{},
 			expectMock: func(mockRepo *MockRepository, users []*model.User) {
-				mockRepo.EXPECT().FindAll().Return(users, nil)
+				mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(users, nil)
 			},
 			wantErr: "",
 		},
@@ -367,7 +416,7 @@ func TestService_GetUsers(t *testing.T) {
 			name: "failed",
 			want: []*model.User{},
 			expectMock: func(mockRepo *MockRepository, users []*model.User) {
-				mockRepo.EXPECT().FindAll().Return(users, errors.New("failed"))
+				mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(users, errors.New("failed"))
 			},
 			wantErr: "failed to get all users",
 		},
@@ -380,7 +429,7 @@ func TestService_GetUsers(t *testing.T) {
 				test.expectMock(mockRepo, test.want)
 			}
 
-			got, err := service.GetUsers()
+			got, err := service.GetUsers(context.Background(), query.Options{})
 
 			if test.wantErr == "" {
 				assert.NoError(t, err)
@@ -431,3 +480,231 @@ func Test(t *testing.T) {
 		})
 	}
 }
+
+func TestService_ApplyBatch(t *testing.T) {
+	id := uuid.New()
+	created := model.NewUser("testuser01", "testuser01@example.com")
+	ops := []*BatchOperation{
+		{Op: batch.OpCreate, Create: &CreateUserRequest{
+			Username: "testuser01", Email: "testuser01@example.com"}},
+		{Op: batch.OpCreate, Create: &CreateUserRequest{
+			Username: "01", Email: "testuser02@example.com"}},
+		{Op: batch.OpDelete, ID: id},
+		{Op: "upsert", Invalid: apperrors.NewInvalidInputError("unknown op")},
+	}
+	tests := []struct {
+		name       string
+		atomic     bool
+		expectMock func(repo *MockRepository)
+		wantUsers  []*model.User
+		wantErrs   []string
+	}{
+		{
+			name:   "atomic",
+			atomic: true,
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context,
+						fn func(Repository) error) error {
+						return fn(repo)
+					})
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(created, nil)
+			},
+			wantUsers: []*model.User{nil, nil, nil, nil},
+			wantErrs: []string{batch.ErrRolledBack.Error(),
+				"invalid username", batch.ErrRolledBack.Error(),
+				batch.ErrRolledBack.Error()},
+		},
+		{
+			name:   "best effort",
+			atomic: false,
+			expectMock: func(repo *MockRepository) {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(created, nil)
+				repo.EXPECT().FindByID(gomock.Any(), id, gomock.Any()).
+					Return(&model.User{ID: id}, nil)
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantUsers: []*model.User{created, nil, nil, nil},
+			wantErrs:  []string{"", "invalid username", "", "unknown op"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setupMockRepoAndService(t)
+			test.expectMock(mockRepo)
+
+			results, err := service.ApplyBatch(context.Background(), ops,
+				test.atomic)
+
+			assert.NoError(t, err)
+			assert.Len(t, results, len(ops))
+			for i, r := range results {
+				assert.Equal(t, test.wantUsers[i], r.User)
+				if test.wantErrs[i] == "" {
+					assert.NoError(t, r.Err)
+				} else {
+					assert.ErrorContains(t, r.Err, test.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestService_ApplyBatch_CountsCreatedAfterCommit(t *testing.T) {
+	id := uuid.New()
+	created := model.NewUser("testuser01", "testuser01@example.com")
+	ops := []*BatchOperation{
+		{Op: batch.OpCreate, Create: &CreateUserRequest{
+			Username: "testuser01", Email: "testuser01@example.com"}},
+		{Op: batch.OpDelete, ID: id},
+	}
+	tests := []struct {
+		name      string
+		findErr   error
+		wantAdded float64
+	}{
+		{name: "committed", wantAdded: 1},
+		{name: "rolled back", findErr: gorm.ErrRecordNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setupMockRepoAndService(t)
+			mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context,
+					fn func(Repository) error) error {
+					return fn(mockRepo)
+				})
+			mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+				Return(created, nil)
+			mockRepo.EXPECT().FindByID(gomock.Any(), id, gomock.Any()).
+				Return(&model.User{ID: id}, test.findErr)
+			if test.findErr == nil {
+				mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).
+					Return(nil)
+			}
+			before := testutil.ToFloat64(metrics.UsersCreated)
+
+			_, err := service.ApplyBatch(context.Background(), ops, true)
+
+			assert.NoError(t, err)
+			assert.Equal(t, before+test.wantAdded,
+				testutil.ToFloat64(metrics.UsersCreated))
+		})
+	}
+}
+
+func TestService_Follow(t *testing.T) {
+	follower := uuid.New()
+	followee := uuid.New()
+	tests := []struct {
+		name       string
+		followee   uuid.UUID
+		expectMock func(mockRepo *MockRepository)
+		wantErr    string
+	}{
+		{
+			name:     "success",
+			followee: followee,
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindByID(gomock.Any(), followee, gomock.Any()).
+					Return(&model.User{ID: followee}, nil)
+				mockRepo.EXPECT().CreateFollow(gomock.Any(),
+					model.NewFollow(follower, followee)).Return(nil)
+			},
+		},
+		{
+			name:     "self",
+			followee: follower,
+			wantErr:  "users cannot follow themselves",
+		},
+		{
+			name:     "followee not found",
+			followee: followee,
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindByID(gomock.Any(), followee, gomock.Any()).
+					Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: apperrors.NewNotFoundError("user", followee).Error(),
+		},
+		{
+			name:     "follower deleted",
+			followee: followee,
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindByID(gomock.Any(), followee, gomock.Any()).
+					Return(&model.User{ID: followee}, nil)
+				mockRepo.EXPECT().CreateFollow(gomock.Any(), gomock.Any()).
+					Return(apperrors.NewReferenceNotFoundError("follower_id"))
+			},
+			wantErr: apperrors.NewNotFoundError("user", follower).Error(),
+		},
+		{
+			name:     "db error",
+			followee: followee,
+			expectMock: func(mockRepo *MockRepository) {
+				mockRepo.EXPECT().FindByID(gomock.Any(), followee, gomock.Any()).
+					Return(nil, errors.New("connection refused"))
+			},
+			wantErr: "db error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo, service := setupMockRepoAndService(t)
+			if test.expectMock != nil {
+				test.expectMock(mockRepo)
+			}
+
+			err := service.Follow(context.Background(), follower, test.followee)
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_Unfollow(t *testing.T) {
+	follower := uuid.New()
+	followee := uuid.New()
+	mockRepo, service := setupMockRepoAndService(t)
+	mockRepo.EXPECT().FindByID(gomock.Any(), followee, gomock.Any()).
+		Return(&model.User{ID: followee}, nil)
+	mockRepo.EXPECT().DeleteFollow(gomock.Any(), follower, followee).
+		Return(nil)
+
+	assert.NoError(t, service.Unfollow(context.Background(), follower,
+		followee))
+}
+
+func TestService_GetFollowers(t *testing.T) {
+	id := uuid.New()
+	page := query.Page{Number: 1, PerPage: 20, Sort: "followed_at", Desc: true}
+	t.Run("success", func(t *testing.T) {
+		mockRepo, service := setupMockRepoAndService(t)
+		mockRepo.EXPECT().FindByID(gomock.Any(), id, gomock.Any()).
+			Return(&model.User{ID: id}, nil)
+		mockRepo.EXPECT().FindFollowers(gomock.Any(), id, page).
+			Return([]*model.Follow{{FolloweeID: id}}, int64(1), nil)
+
+		follows, total, err := service.GetFollowers(context.Background(), id,
+			page)
+
+		assert.NoError(t, err)
+		assert.Len(t, follows, 1)
+		assert.Equal(t, int64(1), total)
+	})
+	t.Run("user not found", func(t *testing.T) {
+		mockRepo, service := setupMockRepoAndService(t)
+		mockRepo.EXPECT().FindByID(gomock.Any(), id, gomock.Any()).
+			Return(nil, gorm.ErrRecordNotFound)
+
+		_, _, err := service.GetFollowing(context.Background(), id, page)
+
+		assert.EqualError(t, err,
+			apperrors.NewNotFoundError("user", id).Error())
+	})
+}
@@ -0,0 +1,117 @@
+package user
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/query"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/pandahawk/blog-api/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("user")
+
+type tracedService struct {
+	next Service
+}
+
+// NewTracedService wraps a Service so every call runs in its own span.
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next}
+}
+
+func (s *tracedService) GetUser(ctx context.Context, id uuid.UUID, opts query.Options) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.GetUser",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	user, err := s.next.GetUser(ctx, id, opts)
+	tracing.End(span, err)
+	return user, err
+}
+
+func (s *tracedService) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.GetUserByUsername",
+		trace.WithAttributes(attribute.String("user.username", username)))
+	user, err := s.next.GetUserByUsername(ctx, username)
+	tracing.End(span, err)
+	return user, err
+}
+
+func (s *tracedService) CreateUser(ctx context.Context, req *CreateUserRequest) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.CreateUser")
+	user, err := s.next.CreateUser(ctx, req)
+	tracing.End(span, err)
+	return user, err
+}
+
+func (s *tracedService) GetUsers(ctx context.Context, opts query.Options) ([]*model.User, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.GetUsers")
+	users, err := s.next.GetUsers(ctx, opts)
+	span.SetAttributes(attribute.Int("user.count", len(users)))
+	tracing.End(span, err)
+	return users, err
+}
+
+func (s *tracedService) UpdateUser(ctx context.Context, id uuid.UUID, req *UpdateUserRequest) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.UpdateUser",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	user, err := s.next.UpdateUser(ctx, id, req)
+	tracing.End(span, err)
+	return user, err
+}
+
+func (s *tracedService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "user.Service.DeleteUser",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	err := s.next.DeleteUser(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) ApplyBatch(ctx context.Context, ops []*BatchOperation,
+	atomic bool) ([]*BatchResult, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.ApplyBatch",
+		trace.WithAttributes(attribute.Int("batch.size", len(ops)),
+			attribute.Bool("batch.atomic", atomic)))
+	results, err := s.next.ApplyBatch(ctx, ops, atomic)
+	tracing.End(span, err)
+	return results, err
+}
+
+func (s *tracedService) Follow(ctx context.Context, followerID uuid.UUID,
+	followeeID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "user.Service.Follow",
+		trace.WithAttributes(attribute.String("user.id", followerID.String()),
+			attribute.String("followee.id", followeeID.String())))
+	err := s.next.Follow(ctx, followerID, followeeID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) Unfollow(ctx context.Context, followerID uuid.UUID,
+	followeeID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "user.Service.Unfollow",
+		trace.WithAttributes(attribute.String("user.id", followerID.String()),
+			attribute.String("followee.id", followeeID.String())))
+	err := s.next.Unfollow(ctx, followerID, followeeID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) GetFollowers(ctx context.Context, id uuid.UUID,
+	page query.Page) ([]*model.Follow, int64, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.GetFollowers",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	follows, total, err := s.next.GetFollowers(ctx, id, page)
+	tracing.End(span, err)
+	return follows, total, err
+}
+
+func (s *tracedService) GetFollowing(ctx context.Context, id uuid.UUID,
+	page query.Page) ([]*model.Follow, int64, error) {
+	ctx, span := tracer.Start(ctx, "user.Service.GetFollowing",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	follows, total, err := s.next.GetFollowing(ctx, id, page)
+	tracing.End(span, err)
+	return follows, total, err
+}
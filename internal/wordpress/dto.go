@@ -0,0 +1,34 @@
+package wordpress
+
+import (
+	"github.com/google/uuid"
+)
+
+type ImportReport struct {
+	// Site is the URL of the imported blog that mappings are recorded for.
+	Site string `json:"site" example:"https://blog.example.com"`
+	// AuthorsCreated counts authors that became new users, AuthorsMatched
+	// the ones mapped to an existing user by email or an earlier import.
+	AuthorsCreated int `json:"authors_created"`
+	AuthorsMatched int `json:"authors_matched"`
+	PostsCreated   int `json:"posts_created"`
+	// PostsSkipped counts items that are not published posts or were
+	// imported before.
+	PostsSkipped int          `json:"posts_skipped"`
+	Redirects    []*Redirect  `json:"redirects"`
+	Errors       []*ItemError `json:"errors"`
+}
+
+// Redirect maps an old WordPress ID and URL to the ID it was imported as.
+type Redirect struct {
+	Kind        string    `json:"kind" enums:"author,post" example:"post"`
+	WordPressID int64     `json:"wordpress_id" example:"42"`
+	URL         string    `json:"url,omitempty" example:"https://blog.example.com/2019/05/hello-world/"`
+	ID          uuid.UUID `json:"id"`
+}
+
+type ItemError struct {
+	Kind        string `json:"kind" enums:"author,post"`
+	WordPressID int64  `json:"wordpress_id"`
+	Error       string `json:"error"`
+}
@@ -0,0 +1,53 @@
+package wordpress
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/middleware"
+	"net/http"
+)
+
+type Handler struct {
+	Service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{Service: service}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/import/wordpress", h.importWXR)
+}
+
+// importWXR godoc
+// @Summary Import a WordPress export
+// @Description Reads a WordPress WXR export and creates users for its
+// @Description authors, matched to existing users by email, and posts for
+// @Description its published posts with their original dates. The report
+// @Description maps old WordPress IDs and URLs to the new IDs for redirects.
+// @Description Authors and posts imported before are skipped.
+// @Tags admin
+// @Accept xml
+// @Produce json
+// @Param export body string true "WXR export"
+// @Success 200 {object} ImportReport
+// @Failure 400 {object} apperrors.InvalidInputError
+// @Failure 403 {object} apperrors.ForbiddenError
+// @Router /admin/import/wordpress [post]
+// @Security ApiKeyAuth
+func (h *Handler) importWXR(c *gin.Context) {
+	middleware.ClearDeadlines(c)
+
+	report, err := h.Service.Import(c.Request.Context(), c.Request.Body)
+	if err != nil {
+		status := apperrors.StatusCode(err)
+		if status == http.StatusInternalServerError {
+			logging.FromContext(c.Request.Context()).Error(
+				"wordpress import failed", "error", err)
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,63 @@
+package wordpress
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_ImportWXR(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockBehaviour func(service *MockService)
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name: "success",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().Import(gomock.Any(), gomock.Any()).
+					Return(&ImportReport{Site: site, PostsCreated: 1,
+						Redirects: []*Redirect{}, Errors: []*ItemError{}}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"site":"https://old.example.com","authors_created":0,` +
+				`"authors_matched":0,"posts_created":1,"posts_skipped":0,` +
+				`"redirects":[],"errors":[]}`,
+		},
+		{
+			name: "not an export",
+			mockBehaviour: func(service *MockService) {
+				service.EXPECT().Import(gomock.Any(), gomock.Any()).
+					Return(nil, apperrors.NewInvalidInputError(
+						"not a WordPress export: no authors or items"))
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"not a WordPress export: no authors or items"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := NewMockService(ctrl)
+			test.mockBehaviour(mockService)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			NewHandler(mockService).RegisterRoutes(router.Group("/admin"))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost,
+				"/admin/import/wordpress", strings.NewReader(sampleWXR))
+			req.Header.Set("Content-Type", "application/xml")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.JSONEq(t, test.wantBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package wordpress
+
+import (
+	"context"
+	"github.com/pandahawk/blog-api/internal/database"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockgen -source=repository.go -destination=repository_mock.go -package=wordpress
+
+type Repository interface {
+	FindMapping(ctx context.Context, site string, kind string,
+		wordpressID int64) (*model.WordPressMapping, error)
+	FindUserByEmail(ctx context.Context, email string) (*model.User, error)
+	UsernameExists(ctx context.Context, username string) (bool, error)
+	CreateUser(ctx context.Context, user *model.User) error
+	CreatePost(ctx context.Context, post *model.Post) error
+	CreateMapping(ctx context.Context, mapping *model.WordPressMapping) error
+	// Transaction runs fn with a repository whose queries share one
+	// transaction, committed if fn returns nil and rolled back otherwise.
+	Transaction(ctx context.Context, fn func(repo Repository) error) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) FindMapping(ctx context.Context, site string,
+	kind string, wordpressID int64) (*model.WordPressMapping, error) {
+	var mapping model.WordPressMapping
+	err := r.db.WithContext(ctx).
+		Where("site = ? AND kind = ? AND wordpress_id = ?",
+			site, kind, wordpressID).
+		First(&mapping).Error
+	return &mapping, err
+}
+
+func (r *repository) FindUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Where("lower(email) = lower(?)", email).
+		First(&user).Error
+	return &user, err
+}
+
+func (r *repository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.User{}).
+		Where("username = ?", username).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) CreateUser(ctx context.Context, user *model.User) error {
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Create(user).Error
+	return database.TranslateError(err)
+}
+
+// CreatePost inserts the post with the timestamps it already carries, GORM
+// only fills them in when they are zero.
+func (r *repository) CreatePost(ctx context.Context, post *model.Post) error {
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Create(post).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) CreateMapping(ctx context.Context, mapping *model.WordPressMapping) error {
+	err := r.db.WithContext(ctx).Create(mapping).Error
+	return database.TranslateError(err)
+}
+
+func (r *repository) Transaction(ctx context.Context, fn func(repo Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repository{db: tx})
+	})
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
@@ -0,0 +1,255 @@
+package wordpress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/metrics"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"gorm.io/gorm"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock.go -package=wordpress
+
+const (
+	postTypePost  = "post"
+	statusPublish = "publish"
+)
+
+type Service interface {
+	// Import reads a WXR export and creates users for its authors and posts
+	// for its published posts. Authors are matched to existing users by
+	// email. Every author and post is recorded as a mapping, which makes a
+	// second import of the same export skip what is already there.
+	Import(ctx context.Context, r io.Reader) (*ImportReport, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// importer holds the state of one import.
+type importer struct {
+	repo    Repository
+	site    string
+	authors map[string]uuid.UUID
+	report  *ImportReport
+}
+
+func (s *service) Import(ctx context.Context, r io.Reader) (*ImportReport, error) {
+	wxr := NewReader(r)
+	imp := &importer{
+		repo:    s.repo,
+		authors: map[string]uuid.UUID{},
+		report: &ImportReport{Redirects: []*Redirect{},
+			Errors: []*ItemError{}},
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		v, err := wxr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		imp.site = wxr.Site
+		imp.report.Site = wxr.Site
+
+		switch v := v.(type) {
+		case *Author:
+			if err := imp.importAuthor(ctx, v); err != nil {
+				imp.fail(model.WordPressAuthor, v.ID, err)
+			}
+		case *Item:
+			if err := imp.importItem(ctx, v); err != nil {
+				imp.fail(model.WordPressPost, v.PostID, err)
+			}
+		}
+	}
+	return imp.report, nil
+}
+
+func (imp *importer) fail(kind string, id int64, err error) {
+	imp.report.Errors = append(imp.report.Errors,
+		&ItemError{Kind: kind, WordPressID: id, Error: err.Error()})
+}
+
+// mapped returns the ID an earlier import mapped the entity to, or uuid.Nil.
+func (imp *importer) mapped(ctx context.Context, kind string,
+	id int64) (uuid.UUID, error) {
+	mapping, err := imp.repo.FindMapping(ctx, imp.site, kind, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return mapping.TargetID, nil
+}
+
+func (imp *importer) importAuthor(ctx context.Context, a *Author) error {
+	login := strings.TrimSpace(a.Login)
+	if login == "" {
+		return fmt.Errorf("author has no login")
+	}
+	id, err := imp.mapped(ctx, model.WordPressAuthor, a.ID)
+	if err != nil {
+		return err
+	}
+	if id != uuid.Nil {
+		imp.authors[login] = id
+		imp.report.AuthorsMatched++
+		return nil
+	}
+
+	email := strings.TrimSpace(a.Email)
+	if email == "" {
+		return fmt.Errorf("author %q has no email", login)
+	}
+	url := imp.site + "/author/" + login + "/"
+	created := false
+	err = imp.repo.Transaction(ctx, func(repo Repository) error {
+		user, err := repo.FindUserByEmail(ctx, email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err != nil {
+			username, err := uniqueUsername(ctx, repo, login, a.ID)
+			if err != nil {
+				return err
+			}
+			user = model.NewUser(username, email)
+			if err := repo.CreateUser(ctx, user); err != nil {
+				return err
+			}
+			created = true
+		}
+		id = user.ID
+		return repo.CreateMapping(ctx, &model.WordPressMapping{
+			Site:        imp.site,
+			Kind:        model.WordPressAuthor,
+			WordPressID: a.ID,
+			URL:         url,
+			TargetID:    user.ID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	imp.authors[login] = id
+	if created {
+		metrics.UsersCreated.Inc()
+		imp.report.AuthorsCreated++
+	} else {
+		imp.report.AuthorsMatched++
+	}
+	imp.report.Redirects = append(imp.report.Redirects, &Redirect{
+		Kind: model.WordPressAuthor, WordPressID: a.ID,
+		URL: url, ID: id})
+	return nil
+}
+
+func (imp *importer) importItem(ctx context.Context, it *Item) error {
+	if it.PostType != postTypePost || it.Status != statusPublish {
+		imp.report.PostsSkipped++
+		return nil
+	}
+	id, err := imp.mapped(ctx, model.WordPressPost, it.PostID)
+	if err != nil {
+		return err
+	}
+	if id != uuid.Nil {
+		imp.report.PostsSkipped++
+		return nil
+	}
+
+	authorID, ok := imp.authors[strings.TrimSpace(it.Creator)]
+	if !ok {
+		return fmt.Errorf("unknown author %q", it.Creator)
+	}
+	title := strings.TrimSpace(it.Title)
+	if title == "" {
+		return fmt.Errorf("post has no title")
+	}
+	published, err := it.Published()
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSpace(it.Link)
+	post := model.NewPost(title, it.Content, authorID)
+	post.CreatedAt = published.UTC()
+	post.UpdatedAt = it.Modified(published).UTC()
+	err = imp.repo.Transaction(ctx, func(repo Repository) error {
+		if err := repo.CreatePost(ctx, post); err != nil {
+			return err
+		}
+		return repo.CreateMapping(ctx, &model.WordPressMapping{
+			Site:        imp.site,
+			Kind:        model.WordPressPost,
+			WordPressID: it.PostID,
+			URL:         url,
+			TargetID:    post.ID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	metrics.PostsPublished.Inc()
+	imp.report.PostsCreated++
+	imp.report.Redirects = append(imp.report.Redirects, &Redirect{
+		Kind: model.WordPressPost, WordPressID: it.PostID,
+		URL: url, ID: post.ID})
+	return nil
+}
+
+// uniqueUsername turns a WordPress login into a username the user service
+// would accept, alphanumeric with at least two letters, and appends a number
+// when it is taken.
+func uniqueUsername(ctx context.Context, repo Repository, login string,
+	wordpressID int64) (string, error) {
+	base := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, login)
+	letters := 0
+	for _, r := range base {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if len(base) < 3 || letters < 2 {
+		base = "wpauthor" + strconv.FormatInt(wordpressID, 10)
+	}
+
+	for n := 1; n <= 100; n++ {
+		username := base
+		if n > 1 {
+			username += strconv.Itoa(n)
+		}
+		taken, err := repo.UsernameExists(ctx, username)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return username, nil
+		}
+	}
+	return "", fmt.Errorf("no free username for author %q", login)
+}
+
+func NewService(r Repository) Service {
+	return &service{repo: r}
+}
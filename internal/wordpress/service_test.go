@@ -0,0 +1,192 @@
+package wordpress
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+	"time"
+)
+
+const site = "https://old.example.com"
+
+func setup(t *testing.T) (*MockRepository, Service) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context,
+			fn func(repo Repository) error) error {
+			return fn(mockRepo)
+		}).AnyTimes()
+	return mockRepo, NewService(mockRepo)
+}
+
+func TestService_Import(t *testing.T) {
+	mockRepo, service := setup(t)
+	mockRepo.EXPECT().FindMapping(gomock.Any(), site, model.WordPressAuthor,
+		int64(2)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().FindUserByEmail(gomock.Any(), "jane@example.com").
+		Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().UsernameExists(gomock.Any(), "janedoe").Return(true, nil)
+	mockRepo.EXPECT().UsernameExists(gomock.Any(), "janedoe2").
+		Return(false, nil)
+	var author *model.User
+	mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, u *model.User) error {
+			u.ID = uuid.New()
+			author = u
+			return nil
+		})
+	mockRepo.EXPECT().FindMapping(gomock.Any(), site, model.WordPressPost,
+		int64(42)).Return(nil, gorm.ErrRecordNotFound)
+	var post *model.Post
+	mockRepo.EXPECT().CreatePost(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, p *model.Post) error {
+			p.ID = uuid.New()
+			post = p
+			return nil
+		})
+	var mappings []*model.WordPressMapping
+	mockRepo.EXPECT().CreateMapping(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, m *model.WordPressMapping) error {
+			mappings = append(mappings, m)
+			return nil
+		}).Times(2)
+
+	report, err := service.Import(context.Background(),
+		strings.NewReader(sampleWXR))
+
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, site, report.Site)
+	assert.Equal(t, 1, report.AuthorsCreated)
+	assert.Equal(t, 1, report.PostsCreated)
+	assert.Equal(t, 1, report.PostsSkipped)
+
+	assert.Equal(t, "janedoe2", author.Username)
+	assert.Equal(t, "jane@example.com", author.Email)
+	assert.Equal(t, "Hello & welcome", post.Title)
+	assert.Equal(t, author.ID, post.UserID)
+	assert.Equal(t, time.Date(2019, 5, 1, 8, 30, 0, 0, time.UTC),
+		post.CreatedAt)
+	assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		post.UpdatedAt)
+
+	require.Len(t, mappings, 2)
+	assert.Equal(t, &model.WordPressMapping{Site: site,
+		Kind: model.WordPressPost, WordPressID: 42,
+		URL:      "https://old.example.com/2019/05/hello/",
+		TargetID: post.ID}, mappings[1])
+	assert.Equal(t, []*Redirect{
+		{Kind: model.WordPressAuthor, WordPressID: 2,
+			URL: site + "/author/jane.doe/", ID: author.ID},
+		{Kind: model.WordPressPost, WordPressID: 42,
+			URL: "https://old.example.com/2019/05/hello/", ID: post.ID},
+	}, report.Redirects)
+}
+
+func TestService_Import_MatchesExistingUserByEmail(t *testing.T) {
+	mockRepo, service := setup(t)
+	existing := &model.User{ID: uuid.New(), Username: "jane"}
+	mockRepo.EXPECT().FindMapping(gomock.Any(), site, model.WordPressAuthor,
+		int64(2)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().FindUserByEmail(gomock.Any(), "jane@example.com").
+		Return(existing, nil)
+	mockRepo.EXPECT().CreateMapping(gomock.Any(), gomock.Any()).
+		Return(nil).Times(2)
+	mockRepo.EXPECT().FindMapping(gomock.Any(), site, model.WordPressPost,
+		int64(42)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().CreatePost(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, p *model.Post) error {
+			assert.Equal(t, existing.ID, p.UserID)
+			return nil
+		})
+
+	report, err := service.Import(context.Background(),
+		strings.NewReader(sampleWXR))
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.AuthorsCreated)
+	assert.Equal(t, 1, report.AuthorsMatched)
+	assert.Equal(t, 1, report.PostsCreated)
+}
+
+func TestService_Import_SkipsImportedEntities(t *testing.T) {
+	mockRepo, service := setup(t)
+	mockRepo.EXPECT().FindMapping(gomock.Any(), site, model.WordPressAuthor,
+		int64(2)).Return(&model.WordPressMapping{TargetID: uuid.New()}, nil)
+	mockRepo.EXPECT().FindMapping(gomock.Any(), site, model.WordPressPost,
+		int64(42)).Return(&model.WordPressMapping{TargetID: uuid.New()}, nil)
+
+	report, err := service.Import(context.Background(),
+		strings.NewReader(sampleWXR))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.AuthorsMatched)
+	assert.Equal(t, 0, report.PostsCreated)
+	assert.Equal(t, 2, report.PostsSkipped)
+	assert.Empty(t, report.Redirects)
+}
+
+func TestService_Import_ReportsItemErrors(t *testing.T) {
+	mockRepo, service := setup(t)
+	mockRepo.EXPECT().FindMapping(gomock.Any(), site, model.WordPressAuthor,
+		int64(2)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().FindUserByEmail(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("db error"))
+	mockRepo.EXPECT().FindMapping(gomock.Any(), site, model.WordPressPost,
+		int64(42)).Return(nil, gorm.ErrRecordNotFound)
+
+	report, err := service.Import(context.Background(),
+		strings.NewReader(sampleWXR))
+
+	require.NoError(t, err)
+	assert.Equal(t, []*ItemError{
+		{Kind: model.WordPressAuthor, WordPressID: 2, Error: "db error"},
+		{Kind: model.WordPressPost, WordPressID: 42,
+			Error: `unknown author "jane.doe"`},
+	}, report.Errors)
+}
+
+func TestService_Import_InvalidExport(t *testing.T) {
+	_, service := setup(t)
+
+	_, err := service.Import(context.Background(),
+		strings.NewReader("not xml"))
+
+	assert.IsType(t, &apperrors.InvalidInputError{}, err)
+}
+
+func TestUniqueUsername(t *testing.T) {
+	tests := []struct {
+		login string
+		want  string
+	}{
+		{login: "jane.doe", want: "janedoe"},
+		{login: "José", want: "Jos"},
+		{login: "j1", want: "wpauthor7"},
+		{login: "12345", want: "wpauthor7"},
+	}
+	for _, test := range tests {
+		t.Run(test.login, func(t *testing.T) {
+			mockRepo, _ := setup(t)
+			mockRepo.EXPECT().UsernameExists(gomock.Any(), test.want).
+				Return(false, nil)
+
+			got, err := uniqueUsername(context.Background(), mockRepo,
+				test.login, 7)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package wordpress
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"io"
+	"strings"
+	"time"
+)
+
+// Namespaces of the WXR elements whose local names are ambiguous. The wp:
+// namespace changes with every WXR version, so wp: elements are matched by
+// local name only.
+const (
+	nsContent = "http://purl.org/rss/1.0/modules/content/"
+	nsDC      = "http://purl.org/dc/elements/1.1/"
+)
+
+// wxrDate is the layout of wp:post_date and wp:post_date_gmt.
+const wxrDate = "2006-01-02 15:04:05"
+
+// Author is a wp:author element of a WXR export.
+type Author struct {
+	ID          int64  `xml:"author_id"`
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+// Item is an item element of a WXR export: a post, page, attachment or any
+// other post type.
+type Item struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	PubDate     string `xml:"pubDate"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PostID      int64  `xml:"post_id"`
+	PostDate    string `xml:"post_date"`
+	PostDateGMT string `xml:"post_date_gmt"`
+	ModifiedGMT string `xml:"post_modified_gmt"`
+	Status      string `xml:"status"`
+	PostType    string `xml:"post_type"`
+}
+
+// Published returns when the item was published, preferring the GMT date.
+// Drafts carry a zero GMT date, for them the local date is read as UTC.
+func (it *Item) Published() (time.Time, error) {
+	for _, v := range []string{it.PostDateGMT, it.PostDate} {
+		v = strings.TrimSpace(v)
+		if v == "" || strings.HasPrefix(v, "0000") {
+			continue
+		}
+		return time.Parse(wxrDate, v)
+	}
+	if it.PubDate != "" {
+		return time.Parse(time.RFC1123Z, strings.TrimSpace(it.PubDate))
+	}
+	return time.Time{}, fmt.Errorf("post %d has no publish date", it.PostID)
+}
+
+// Modified returns when the item was last modified, or published when the
+// export does not say.
+func (it *Item) Modified(published time.Time) time.Time {
+	v := strings.TrimSpace(it.ModifiedGMT)
+	if v == "" || strings.HasPrefix(v, "0000") {
+		return published
+	}
+	t, err := time.Parse(wxrDate, v)
+	if err != nil || t.Before(published) {
+		return published
+	}
+	return t
+}
+
+// Reader reads the authors and items of a WXR export one at a time, so an
+// export is never held in memory as a whole.
+type Reader struct {
+	dec  *xml.Decoder
+	path []string
+	seen bool
+	// Site is the URL of the exported blog, known once the channel header
+	// has been read, which WordPress writes before any author or item.
+	Site string
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{dec: xml.NewDecoder(r)}
+}
+
+// Next returns the next *Author or *Item, or io.EOF at the end of the
+// export. Malformed XML and documents that are not WordPress exports are
+// reported as InvalidInputError.
+func (r *Reader) Next() (any, error) {
+	v, err := r.next()
+	var syntaxErr *xml.SyntaxError
+	switch {
+	case err == io.EOF && !r.seen:
+		return nil, apperrors.NewInvalidInputError(
+			"not a WordPress export: no authors or items")
+	case errors.As(err, &syntaxErr):
+		return nil, apperrors.NewInvalidInputError(
+			"invalid WordPress export: " + syntaxErr.Error())
+	case err == nil && r.Site == "":
+		return nil, apperrors.NewInvalidInputError(
+			"not a WordPress export: channel has no site URL")
+	}
+	if err == nil {
+		r.seen = true
+	}
+	return v, err
+}
+
+func (r *Reader) next() (any, error) {
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			inChannel := len(r.path) > 0 && r.path[len(r.path)-1] == "channel"
+			switch {
+			case inChannel && t.Name.Local == "author":
+				var a Author
+				if err := r.dec.DecodeElement(&a, &t); err != nil {
+					return nil, err
+				}
+				return &a, nil
+			case inChannel && t.Name.Local == "item":
+				var it Item
+				if err := r.dec.DecodeElement(&it, &t); err != nil {
+					return nil, err
+				}
+				return &it, nil
+			case inChannel && (t.Name.Local == "base_blog_url" ||
+				t.Name.Local == "link" && t.Name.Space == ""):
+				var url string
+				if err := r.dec.DecodeElement(&url, &t); err != nil {
+					return nil, err
+				}
+				if r.Site == "" || t.Name.Local == "base_blog_url" {
+					r.Site = strings.TrimRight(strings.TrimSpace(url), "/")
+				}
+			default:
+				r.path = append(r.path, t.Name.Local)
+			}
+		case xml.EndElement:
+			if len(r.path) > 0 {
+				r.path = r.path[:len(r.path)-1]
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package wordpress
+
+import (
+	"github.com/pandahawk/blog-api/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleWXR = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<title>Old Blog</title>
+	<link>https://old.example.com</link>
+	<wp:wxr_version>1.2</wp:wxr_version>
+	<wp:base_site_url>https://old.example.com</wp:base_site_url>
+	<wp:base_blog_url>https://old.example.com/</wp:base_blog_url>
+	<wp:author>
+		<wp:author_id>2</wp:author_id>
+		<wp:author_login><![CDATA[jane.doe]]></wp:author_login>
+		<wp:author_email><![CDATA[jane@example.com]]></wp:author_email>
+		<wp:author_display_name><![CDATA[Jane Doe]]></wp:author_display_name>
+	</wp:author>
+	<item>
+		<title>Hello &amp; welcome</title>
+		<link>https://old.example.com/2019/05/hello/</link>
+		<pubDate>Wed, 01 May 2019 08:30:00 +0000</pubDate>
+		<dc:creator><![CDATA[jane.doe]]></dc:creator>
+		<content:encoded><![CDATA[<p>First post</p>]]></content:encoded>
+		<excerpt:encoded><![CDATA[excerpt]]></excerpt:encoded>
+		<wp:post_id>42</wp:post_id>
+		<wp:post_date><![CDATA[2019-05-01 10:30:00]]></wp:post_date>
+		<wp:post_date_gmt><![CDATA[2019-05-01 08:30:00]]></wp:post_date_gmt>
+		<wp:post_modified_gmt><![CDATA[2020-01-02 03:04:05]]></wp:post_modified_gmt>
+		<wp:status><![CDATA[publish]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+		<wp:postmeta>
+			<wp:meta_key><![CDATA[_edit_last]]></wp:meta_key>
+			<wp:meta_value><![CDATA[1]]></wp:meta_value>
+		</wp:postmeta>
+	</item>
+	<item>
+		<title>About</title>
+		<dc:creator><![CDATA[jane.doe]]></dc:creator>
+		<wp:post_id>43</wp:post_id>
+		<wp:status><![CDATA[publish]]></wp:status>
+		<wp:post_type><![CDATA[page]]></wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+func TestReader_Next(t *testing.T) {
+	r := NewReader(strings.NewReader(sampleWXR))
+
+	var got []any
+	for {
+		v, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+
+	assert.Equal(t, "https://old.example.com", r.Site)
+	require.Len(t, got, 3)
+	assert.Equal(t, &Author{ID: 2, Login: "jane.doe",
+		Email: "jane@example.com", DisplayName: "Jane Doe"}, got[0])
+	post := got[1].(*Item)
+	assert.Equal(t, "Hello & welcome", post.Title)
+	assert.Equal(t, "jane.doe", post.Creator)
+	assert.Equal(t, "<p>First post</p>", post.Content)
+	assert.Equal(t, int64(42), post.PostID)
+	assert.Equal(t, "https://old.example.com/2019/05/hello/", post.Link)
+	assert.Equal(t, "page", got[2].(*Item).PostType)
+}
+
+func TestReader_Next_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{
+			name:    "not xml",
+			body:    `{"type":"header"}`,
+			wantErr: "not a WordPress export",
+		},
+		{
+			name:    "malformed",
+			body:    `<rss><channel><link>x</link><item><title>a</item>`,
+			wantErr: "invalid WordPress export",
+		},
+		{
+			name:    "no site",
+			body:    `<rss><channel><item><title>a</title></item></channel></rss>`,
+			wantErr: "channel has no site URL",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewReader(strings.NewReader(test.body)).Next()
+
+			assert.IsType(t, &apperrors.InvalidInputError{}, err)
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestItem_Published(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    Item
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "gmt date",
+			item: Item{PostDateGMT: "2019-05-01 08:30:00",
+				PostDate: "2019-05-01 10:30:00"},
+			want: time.Date(2019, 5, 1, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "zero gmt date falls back to local date",
+			item: Item{PostDateGMT: "0000-00-00 00:00:00",
+				PostDate: "2019-05-01 10:30:00"},
+			want: time.Date(2019, 5, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "pubDate",
+			item: Item{PubDate: "Wed, 01 May 2019 08:30:00 +0000"},
+			want: time.Date(2019, 5, 1, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "no date",
+			item:    Item{PostID: 7},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.item.Published()
+
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, test.want.Equal(got), "got %s", got)
+		})
+	}
+}
@@ -1,17 +1,11 @@
 package main
 
 import (
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
+	"fmt"
 	"github.com/joho/godotenv"
 	_ "github.com/pandahawk/blog-api/docs"
-	"github.com/pandahawk/blog-api/internal/database"
-	"github.com/pandahawk/blog-api/router"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
-	"log"
+	"github.com/pandahawk/blog-api/internal/cli"
 	"os"
-	"time"
 )
 
 // @title       Blog API
@@ -29,23 +23,10 @@ import (
 // @BasePath  /api/v1
 
 func main() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	_ = godotenv.Load()
 
-	db := database.ConnectWithRetry(5, 5*time.Second)
-
-	r := gin.Default()
-	r.Use(cors.Default())
-
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	router.SetupRoutes(r, db)
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal(err)
+	if err := cli.Run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 }
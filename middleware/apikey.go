@@ -1,18 +1,70 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/apikey"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/metrics"
+	"log/slog"
 	"net/http"
-	"os"
 )
 
-func ApiKey() gin.HandlerFunc {
-	apiKey := os.Getenv("API_KEY")
+// ApiKey accepts either the static key from the configuration or, when keys
+// is not nil, any stored key that has not been revoked. An empty static key
+// disables the static check instead of matching requests without a header.
+func ApiKey(cfg config.AuthConfig, keys apikey.Service) gin.HandlerFunc {
+	apiKey := []byte(cfg.APIKey)
 	return func(c *gin.Context) {
-		if c.GetHeader("X-API-KEY") != apiKey {
-			c.AbortWithStatusJSON(
-				http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		header := c.GetHeader("X-API-KEY")
+		if len(apiKey) > 0 &&
+			subtle.ConstantTimeCompare([]byte(header), apiKey) == 1 {
+			setPrincipal(c, "api_key:static")
+			c.Next()
+			return
+		}
+		if keys != nil && header != "" {
+			if key, err := keys.Authenticate(header); err == nil {
+				setPrincipal(c, "api_key:"+key.ID.String())
+				if key.UserID != nil {
+					c.Request = c.Request.WithContext(
+						auth.WithUser(c.Request.Context(), *key.UserID))
+				}
+				c.Next()
+				return
+			}
+		}
+		reason := "invalid"
+		if header == "" {
+			reason = "missing"
+		}
+		metrics.AuthFailures.WithLabelValues(reason).Inc()
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+	}
+}
+
+// AdminOnly lets through requests made with the static key or a stored key
+// that is not linked to a user. Keys handed to users act as that user and
+// must not reach operations on the whole blog.
+func AdminOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := auth.UserID(c.Request.Context()); ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "the API key is linked to a user and cannot use admin endpoints"})
+			return
 		}
 		c.Next()
 	}
 }
+
+// setPrincipal records who is calling, both for the request log line and in
+// the logger handlers and services see.
+func setPrincipal(c *gin.Context, principal string) {
+	c.Set(PrincipalKey, principal)
+	ctx := c.Request.Context()
+	c.Request = c.Request.WithContext(logging.WithLogger(ctx,
+		logging.FromContext(ctx).With(slog.String("principal", principal))))
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/apikey"
+	"github.com/pandahawk/blog-api/internal/auth"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestApiKey(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name          string
+		configured    string
+		header        string
+		expectMock    func(keys *apikey.MockService)
+		wantStatus    int
+		wantPrincipal string
+		wantUser      *uuid.UUID
+	}{
+		{
+			name:          "static key matches",
+			configured:    "secret",
+			header:        "secret",
+			wantStatus:    http.StatusOK,
+			wantPrincipal: "api_key:static",
+		},
+		{
+			name:       "static key does not match",
+			configured: "secret",
+			header:     "wrong",
+			expectMock: func(keys *apikey.MockService) {
+				keys.EXPECT().Authenticate("wrong").
+					Return(nil, apikey.ErrInvalidKey)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "empty static key does not accept missing header",
+			configured: "",
+			header:     "",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "stored key",
+			header: "bk_stored",
+			expectMock: func(keys *apikey.MockService) {
+				keys.EXPECT().Authenticate("bk_stored").
+					Return(&model.APIKey{ID: uuid.MustParse(
+						"6f1c2f4e-2b7a-4c55-9d0e-0a3f7f1d2c11")}, nil)
+			},
+			wantStatus:    http.StatusOK,
+			wantPrincipal: "api_key:6f1c2f4e-2b7a-4c55-9d0e-0a3f7f1d2c11",
+		},
+		{
+			name:   "stored key linked to a user",
+			header: "bk_user",
+			expectMock: func(keys *apikey.MockService) {
+				keys.EXPECT().Authenticate("bk_user").Return(&model.APIKey{
+					ID:     uuid.MustParse("6f1c2f4e-2b7a-4c55-9d0e-0a3f7f1d2c11"),
+					UserID: &userID}, nil)
+			},
+			wantStatus:    http.StatusOK,
+			wantPrincipal: "api_key:6f1c2f4e-2b7a-4c55-9d0e-0a3f7f1d2c11",
+			wantUser:      &userID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			keys := apikey.NewMockService(ctrl)
+			if test.expectMock != nil {
+				test.expectMock(keys)
+			}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			var principal string
+			var user *uuid.UUID
+			router.GET("/", ApiKey(config.AuthConfig{APIKey: test.configured}, keys),
+				func(c *gin.Context) {
+					principal = c.GetString(PrincipalKey)
+					if id, ok := auth.UserID(c.Request.Context()); ok {
+						user = &id
+					}
+					c.Status(http.StatusOK)
+				})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			if test.header != "" {
+				req.Header.Set("X-API-KEY", test.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Equal(t, test.wantPrincipal, principal)
+			assert.Equal(t, test.wantUser, user)
+		})
+	}
+}
+
+func TestAdminOnly(t *testing.T) {
+	staticKey := "secret"
+	userID := uuid.New()
+	tests := []struct {
+		name       string
+		header     string
+		key        *model.APIKey
+		wantStatus int
+	}{
+		{
+			name:       "static key",
+			header:     staticKey,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "stored key without a user",
+			header:     "bk_admin",
+			key:        &model.APIKey{ID: uuid.New()},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "stored key linked to a user",
+			header:     "bk_user",
+			key:        &model.APIKey{ID: uuid.New(), UserID: &userID},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			keys := apikey.NewMockService(ctrl)
+			if test.key != nil {
+				keys.EXPECT().Authenticate(test.header).Return(test.key, nil)
+			}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/admin/export",
+				ApiKey(config.AuthConfig{APIKey: staticKey}, keys), AdminOnly(),
+				func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/admin/export", nil)
+			req.Header.Set("X-API-KEY", test.header)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+		})
+	}
+}
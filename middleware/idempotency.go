@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/idempotency"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"io"
+	"net/http"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// responseRecorder keeps a copy of the response body while writing it.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes POST requests that carry an Idempotency-Key header safe
+// to retry. The first request is processed and its response stored; retries
+// with the same key and body get the stored response back, retries with a
+// different body get 422 and retries while the first request is still
+// running wait for it or get 409. Keys are scoped to the calling principal,
+// and responses with a 5xx status are not stored so the request can be
+// retried.
+func Idempotency(service idempotency.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || header == "" {
+			c.Next()
+			return
+		}
+		if len(header) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Idempotency-Key must not be longer than 255 characters"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				gin.H{"error": "invalid request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		client := c.GetString(PrincipalKey)
+		if client == "" {
+			client = "ip:" + c.ClientIP()
+		}
+		key := client + " " + header
+		sum := sha256.Sum256([]byte(c.Request.Method + " " +
+			c.Request.URL.Path + "\n" + string(body)))
+		fingerprint := hex.EncodeToString(sum[:])
+
+		ctx := c.Request.Context()
+		stored, err := service.Begin(ctx, key, fingerprint)
+		switch {
+		case errors.Is(err, idempotency.ErrMismatch):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity,
+				gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, idempotency.ErrInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict,
+				gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			logging.FromContext(ctx).Error("begin idempotent request",
+				"error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError,
+				gin.H{"error": "could not check the Idempotency-Key"})
+			return
+		case stored != nil:
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		// The key must be settled even if the client has gone away.
+		ctx = context.WithoutCancel(ctx)
+		settled := false
+		defer func() {
+			if settled {
+				return
+			}
+			// The handler panicked; free the key so the request can be
+			// retried instead of waiting for the lock to time out.
+			if err := service.Release(ctx, key); err != nil {
+				logging.FromContext(ctx).Error("release idempotent request",
+					"error", err)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		settled = true
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			err = service.Release(ctx, key)
+		} else {
+			err = service.Complete(ctx, key, status,
+				recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error("settle idempotent request",
+				"status", status, "error", err)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/pandahawk/blog-api/internal/idempotency"
+	"github.com/pandahawk/blog-api/internal/shared/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIdempotency(t *testing.T) {
+	const key = "api_key:a 5f0c"
+	tests := []struct {
+		name        string
+		method      string
+		header      string
+		status      int
+		expectMock  func(service *idempotency.MockService)
+		wantStatus  int
+		wantBody    string
+		wantHandled bool
+		wantReplay  bool
+	}{
+		{
+			name:        "without a key",
+			method:      http.MethodPost,
+			status:      http.StatusCreated,
+			wantStatus:  http.StatusCreated,
+			wantBody:    `{"id":1}`,
+			wantHandled: true,
+		},
+		{
+			name:        "other methods are ignored",
+			method:      http.MethodPut,
+			header:      "5f0c",
+			status:      http.StatusOK,
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"id":1}`,
+			wantHandled: true,
+		},
+		{
+			name:   "first request is stored",
+			method: http.MethodPost,
+			header: "5f0c",
+			status: http.StatusCreated,
+			expectMock: func(service *idempotency.MockService) {
+				service.EXPECT().Begin(gomock.Any(), key, gomock.Any()).Return(nil, nil)
+				service.EXPECT().Complete(gomock.Any(), key, http.StatusCreated,
+					"application/json; charset=utf-8", []byte(`{"id":1}`)).Return(nil)
+			},
+			wantStatus:  http.StatusCreated,
+			wantBody:    `{"id":1}`,
+			wantHandled: true,
+		},
+		{
+			name:   "server errors release the key",
+			method: http.MethodPost,
+			header: "5f0c",
+			status: http.StatusInternalServerError,
+			expectMock: func(service *idempotency.MockService) {
+				service.EXPECT().Begin(gomock.Any(), key, gomock.Any()).Return(nil, nil)
+				service.EXPECT().Release(gomock.Any(), key).Return(nil)
+			},
+			wantStatus:  http.StatusInternalServerError,
+			wantBody:    `{"id":1}`,
+			wantHandled: true,
+		},
+		{
+			name:   "retry is replayed",
+			method: http.MethodPost,
+			header: "5f0c",
+			expectMock: func(service *idempotency.MockService) {
+				service.EXPECT().Begin(gomock.Any(), key, gomock.Any()).
+					Return(&model.IdempotencyKey{StatusCode: http.StatusCreated,
+						ContentType: "application/json", Body: []byte(`{"id":0}`)}, nil)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"id":0}`,
+			wantReplay: true,
+		},
+		{
+			name:   "different request with the same key",
+			method: http.MethodPost,
+			header: "5f0c",
+			expectMock: func(service *idempotency.MockService) {
+				service.EXPECT().Begin(gomock.Any(), key, gomock.Any()).
+					Return(nil, idempotency.ErrMismatch)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"error":"this Idempotency-Key was already used for a different request"}`,
+		},
+		{
+			name:   "concurrent retry",
+			method: http.MethodPost,
+			header: "5f0c",
+			expectMock: func(service *idempotency.MockService) {
+				service.EXPECT().Begin(gomock.Any(), key, gomock.Any()).
+					Return(nil, idempotency.ErrInProgress)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"error":"a request with this Idempotency-Key is still being processed"}`,
+		},
+		{
+			name:       "key too long",
+			method:     http.MethodPost,
+			header:     strings.Repeat("k", 256),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"Idempotency-Key must not be longer than 255 characters"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			service := idempotency.NewMockService(ctrl)
+			if test.expectMock != nil {
+				test.expectMock(service)
+			}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(func(c *gin.Context) { c.Set(PrincipalKey, "api_key:a") })
+			router.Use(Idempotency(service))
+			handled := false
+			handler := func(c *gin.Context) {
+				handled = true
+				body, _ := c.GetRawData()
+				assert.Equal(t, `{"title":"hello"}`, string(body))
+				c.JSON(test.status, gin.H{"id": 1})
+			}
+			router.POST("/posts", handler)
+			router.PUT("/posts", handler)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(test.method, "/posts",
+				strings.NewReader(`{"title":"hello"}`))
+			if test.header != "" {
+				req.Header.Set(IdempotencyKeyHeader, test.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.JSONEq(t, test.wantBody, w.Body.String())
+			assert.Equal(t, test.wantHandled, handled)
+			if test.wantReplay {
+				assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+			}
+		})
+	}
+}
+
+func TestIdempotency_FingerprintCoversBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := idempotency.NewMockService(ctrl)
+	var fingerprints []string
+	service.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Times(3).
+		DoAndReturn(func(_, _ any, fingerprint string) (*model.IdempotencyKey, error) {
+			fingerprints = append(fingerprints, fingerprint)
+			return nil, idempotency.ErrInProgress
+		})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/posts", Idempotency(service))
+
+	for _, body := range []string{`{"a":1}`, `{"a":1}`, `{"a":2}`} {
+		req, _ := http.NewRequest(http.MethodPost, "/posts", strings.NewReader(body))
+		req.Header.Set(IdempotencyKeyHeader, "k")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, fingerprints[0], fingerprints[1])
+	assert.NotEqual(t, fingerprints[0], fingerprints[2])
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := idempotency.NewMockService(ctrl)
+	gomock.InOrder(
+		service.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, nil),
+		service.EXPECT().Release(gomock.Any(), gomock.Any()).Return(nil),
+	)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Recovery())
+	router.POST("/posts", Idempotency(service), func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/posts", strings.NewReader(`{}`))
+	req.Header.Set(IdempotencyKeyHeader, "k")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
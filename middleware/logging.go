@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"time"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// PrincipalKey is the gin context key under which authentication stores
+// who made the request.
+const PrincipalKey = "principal"
+
+// requestIDPattern limits propagated request IDs to what is safe to echo
+// back and write to logs.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestLogger assigns every request an ID, reusing a well-formed
+// X-Request-ID from the client, and stores a logger carrying it in the
+// request context. Once the request is done it logs one line with the
+// method, route, status, latency, response size and principal. Headers are
+// only logged at debug level, with credentials redacted.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		reqLogger := logger.With(slog.String("request_id", requestID))
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+			reqLogger = reqLogger.With(slog.String("trace_id", sc.TraceID().String()))
+		}
+		c.Request = c.Request.WithContext(
+			logging.WithLogger(c.Request.Context(), reqLogger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms",
+				float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if principal := c.GetString(PrincipalKey); principal != "" {
+			attrs = append(attrs, slog.String("principal", principal))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+		ctx := c.Request.Context()
+		if reqLogger.Enabled(ctx, slog.LevelDebug) {
+			attrs = append(attrs, logging.Headers("headers", c.Request.Header))
+		}
+		reqLogger.LogAttrs(ctx, level, "request", attrs...)
+	}
+}
+
+// Recovery turns a panic into a 500 response and logs it with the request
+// logger instead of gin's plain-text writer.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, err any) {
+		logging.FromContext(c.Request.Context()).Error("panic recovered",
+			slog.Any("panic", err), slog.String("stack", string(debug.Stack())))
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": "internal server error"})
+	})
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestLogger(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestID     string
+		wantRequestID func(t *testing.T, got string)
+	}{
+		{
+			name:      "propagates a valid request id",
+			requestID: "abc-123",
+			wantRequestID: func(t *testing.T, got string) {
+				assert.Equal(t, "abc-123", got)
+			},
+		},
+		{
+			name:      "replaces an unsafe request id",
+			requestID: "bad id\n",
+			wantRequestID: func(t *testing.T, got string) {
+				assert.Len(t, got, 36)
+			},
+		},
+		{
+			name: "assigns a missing request id",
+			wantRequestID: func(t *testing.T, got string) {
+				assert.Len(t, got, 36)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := logging.New(&buf, config.LogConfig{Level: "debug", Format: config.LogJSON})
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(RequestLogger(logger))
+			router.GET("/users/:id", ApiKey(config.AuthConfig{APIKey: "secret"}, nil),
+				func(c *gin.Context) {
+					logging.FromContext(c.Request.Context()).Info("in handler")
+					c.String(http.StatusCreated, "hello")
+				})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/users/42", nil)
+			req.Header.Set("X-API-KEY", "secret")
+			req.Header.Set("Authorization", "Bearer token")
+			if test.requestID != "" {
+				req.Header.Set(RequestIDHeader, test.requestID)
+			}
+			router.ServeHTTP(w, req)
+
+			requestID := w.Header().Get(RequestIDHeader)
+			test.wantRequestID(t, requestID)
+
+			lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+			require.Len(t, lines, 2)
+			var handlerLine, requestLine map[string]any
+			require.NoError(t, json.Unmarshal(lines[0], &handlerLine))
+			require.NoError(t, json.Unmarshal(lines[1], &requestLine))
+
+			assert.Equal(t, requestID, handlerLine["request_id"])
+			assert.Equal(t, "api_key:static", handlerLine["principal"])
+
+			assert.Equal(t, "request", requestLine["msg"])
+			assert.Equal(t, requestID, requestLine["request_id"])
+			assert.Equal(t, "GET", requestLine["method"])
+			assert.Equal(t, "/users/:id", requestLine["route"])
+			assert.Equal(t, float64(http.StatusCreated), requestLine["status"])
+			assert.Equal(t, float64(5), requestLine["bytes"])
+			assert.Equal(t, "api_key:static", requestLine["principal"])
+			assert.Contains(t, requestLine, "latency_ms")
+			headers := requestLine["headers"].(map[string]any)
+			assert.Equal(t, "[REDACTED]", headers["X-Api-Key"])
+			assert.Equal(t, "[REDACTED]", headers["Authorization"])
+			assert.NotContains(t, buf.String(), "secret")
+		})
+	}
+}
+
+func TestRequestLogger_OmitsHeadersAboveDebug(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, config.LogConfig{Level: "info", Format: config.LogJSON})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestLogger(logger))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/missing", nil)
+	router.ServeHTTP(w, req)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "WARN", line["level"])
+	assert.Equal(t, "unmatched", line["route"])
+	assert.NotContains(t, line, "headers")
+}
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, config.LogConfig{Level: "info", Format: config.LogJSON})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestLogger(logger), Recovery())
+	router.GET("/", func(c *gin.Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":"internal server error"}`, w.Body.String())
+	assert.Contains(t, buf.String(), `"panic":"boom"`)
+}
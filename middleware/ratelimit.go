@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/logging"
+	"github.com/pandahawk/blog-api/internal/metrics"
+	"github.com/pandahawk/blog-api/internal/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit limits each client to the read budget for GET, HEAD and OPTIONS
+// requests and to the write budget for everything else. Clients are
+// identified by the authenticated principal, falling back to the client IP,
+// and every group draws from its own buckets. If the store fails the
+// request is let through rather than turning an outage of the store into
+// an outage of the API.
+func RateLimit(store ratelimit.Store, group string,
+	read, write ratelimit.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		budget, policy := "write", write
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			budget, policy = "read", read
+		}
+		client := c.GetString(PrincipalKey)
+		if client == "" {
+			client = "ip:" + c.ClientIP()
+		}
+
+		ctx := c.Request.Context()
+		result, err := store.Take(ctx,
+			group+":"+budget+":"+client, policy)
+		if err != nil {
+			logging.FromContext(ctx).Error("rate limit store failed",
+				"group", group, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", seconds(result.ResetAfter))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%s",
+			policy.Requests, seconds(policy.Per)))
+		if !result.Allowed {
+			metrics.RateLimited.WithLabelValues(group, budget).Inc()
+			c.Header("Retry-After", seconds(result.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests,
+				gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// seconds rounds d up to whole seconds, as the headers require.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
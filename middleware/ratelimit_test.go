@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/pandahawk/blog-api/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	read := ratelimit.Policy{Requests: 2, Per: time.Minute}
+	write := ratelimit.Policy{Requests: 1, Per: time.Minute}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if p := c.GetHeader("X-Test-Principal"); p != "" {
+			c.Set(PrincipalKey, p)
+		}
+	})
+	router.Use(RateLimit(ratelimit.NewMemoryStore(), "posts", read, write))
+	router.GET("/posts", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/posts", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	do := func(method, principal string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/posts", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if principal != "" {
+			req.Header.Set("X-Test-Principal", principal)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "api_key:a")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "1;w=60", w.Header().Get("RateLimit-Policy"))
+
+	w = do(http.MethodPost, "api_key:a")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"rate limit exceeded"}`, w.Body.String())
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "api_key:a").Code,
+		"reads have their own budget")
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "api_key:b").Code,
+		"principals have their own budget")
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "").Code,
+		"anonymous clients are limited by IP")
+	assert.Equal(t, http.StatusTooManyRequests, do(http.MethodPost, "").Code)
+}
+
+func TestRateLimit_StoreFailureLetsRequestsThrough(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := ratelimit.NewMockStore(ctrl)
+	store.EXPECT().Take(gomock.Any(), "users:read:ip:10.0.0.1", gomock.Any()).
+		Return(nil, errors.New("connection refused"))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	policy := ratelimit.Policy{Requests: 1, Per: time.Second}
+	router.GET("/users", RateLimit(store, "users", policy, policy),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
@@ -2,36 +2,171 @@ package router
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/analytics"
+	"github.com/pandahawk/blog-api/internal/apikey"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/health"
+	"github.com/pandahawk/blog-api/internal/idempotency"
+	"github.com/pandahawk/blog-api/internal/metrics"
 	"github.com/pandahawk/blog-api/internal/post"
+	"github.com/pandahawk/blog-api/internal/ratelimit"
+	"github.com/pandahawk/blog-api/internal/readinglist"
+	"github.com/pandahawk/blog-api/internal/site"
+	"github.com/pandahawk/blog-api/internal/transfer"
+	"github.com/pandahawk/blog-api/internal/trending"
 	"github.com/pandahawk/blog-api/internal/user"
+	"github.com/pandahawk/blog-api/internal/wordpress"
 	"github.com/pandahawk/blog-api/middleware"
 	"gorm.io/gorm"
+	"net/http"
+	"strings"
+	"time"
 )
 
-func setupResourceRoutes(r *gin.Engine, db *gorm.DB) {
-	v1 := r.Group("/api/v1", middleware.ApiKey())
+func setupResourceRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config,
+	views *analytics.Recorder) {
+	apiKeyRepository := apikey.NewRepository(db)
+	apiKeyService := apikey.NewService(apiKeyRepository)
+	v1 := r.Group("/api/v1", middleware.ApiKey(cfg.Auth, apiKeyService))
+	limit := rateLimiter(db, cfg.RateLimit)
+	idempotent := middleware.Idempotency(idempotency.NewService(
+		idempotency.NewRepository(db), cfg.Idempotency))
 
 	userRepository := user.NewRepository(db)
-	userService := user.NewService(userRepository)
+	userService := user.NewTracedService(user.NewService(userRepository))
 	userHandler := user.NewHandler(userService)
-	userGroup := v1.Group("/users")
+	userGroup := v1.Group("/users", limit("users")...)
+	userGroup.Use(idempotent)
 	userHandler.RegisterRoutes(userGroup)
 
 	postRepository := post.NewRepository(db)
-	postService := post.NewService(postRepository)
+	postService := post.NewTracedService(post.NewService(postRepository))
 	postHandler := post.NewHandler(postService)
-	postGroup := v1.Group("/posts")
+	if views != nil {
+		postHandler.Views = views
+	}
+	postGroup := v1.Group("/posts", limit("posts")...)
+	postGroup.Use(idempotent)
 	postHandler.RegisterRoutes(postGroup)
+	postHandler.RegisterUserRoutes(userGroup)
+	postHandler.RegisterFeedRoutes(v1.Group("/feed", limit("posts")...))
+
+	analyticsHandler := analytics.NewHandler(analytics.NewTracedService(
+		analytics.NewService(analytics.NewRepository(db))))
+	analyticsHandler.RegisterPostRoutes(postGroup)
+	analyticsHandler.RegisterRoutes(v1.Group("/analytics", limit("posts")...))
+
+	trendingHandler := trending.NewHandler(trending.NewTracedService(
+		trending.NewService(trending.NewRepository(db), cfg.Trending)))
+	trendingHandler.RegisterPostRoutes(postGroup)
+
+	readingListHandler := readinglist.NewHandler(readinglist.NewTracedService(
+		readinglist.NewService(readinglist.NewRepository(db))))
+	readingListGroup := v1.Group("/reading-lists", limit("reading_lists")...)
+	readingListGroup.Use(idempotent)
+	readingListHandler.RegisterRoutes(readingListGroup)
+	// Shared lists are read through a link, without an API key.
+	readingListHandler.RegisterSharedRoutes(
+		r.Group("/api/v1/shared", limit("reading_lists")...))
+
+	userHandler.MaxBatchSize = cfg.Batch.MaxItems
+	postHandler.MaxBatchSize = cfg.Batch.MaxItems
+	v1.POST("/:collection", customMethods(map[string]gin.HandlersChain{
+		"users:batch": append(limit("users"), userHandler.Batch),
+		"posts:batch": append(limit("posts"), postHandler.Batch),
+	}))
+
+	transferHandler := transfer.NewHandler(
+		transfer.NewService(transfer.NewRepository(db)))
+	adminGroup := v1.Group("/admin", middleware.AdminOnly())
+	transferHandler.RegisterRoutes(adminGroup)
+	wordpressHandler := wordpress.NewHandler(
+		wordpress.NewService(wordpress.NewRepository(db)))
+	wordpressHandler.RegisterRoutes(adminGroup)
+}
+
+// customMethods serves routes of the form /{collection}:{method}. Gin cannot
+// register those next to /{collection} because of the colon, so they share
+// one parameter route and are dispatched on the last path segment. Each
+// chain may hold middleware that calls c.Next only as its last step, like
+// the rate limiter.
+func customMethods(routes map[string]gin.HandlersChain) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		chain, ok := routes[c.Param("collection")]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				gin.H{"error": "not found"})
+			return
+		}
+		for _, h := range chain {
+			if c.IsAborted() {
+				return
+			}
+			h(c)
+		}
+	}
+}
 
+// rateLimiter returns the rate limiting middleware for a route group, with
+// the group's budgets from the configuration, or nothing when rate limiting
+// is disabled. All groups share one store.
+func rateLimiter(db *gorm.DB,
+	cfg config.RateLimitConfig) func(group string) []gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(string) []gin.HandlerFunc { return nil }
+	}
+	store := ratelimit.NewMemoryStore()
+	if cfg.Store == config.RateLimitPostgres {
+		store = ratelimit.NewPostgresStore(db)
+	}
+	return func(group string) []gin.HandlerFunc {
+		read, write := cfg.Policies(group)
+		return []gin.HandlerFunc{middleware.RateLimit(store, group,
+			ratelimit.Policy(read), ratelimit.Policy(write))}
+	}
 }
 
-func SetupRoutes(r *gin.Engine, db *gorm.DB) {
+// SetupRoutes mounts the health checks, the metrics and, with a database,
+// the API. views counts the reads of posts unless it is nil.
+func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config,
+	monitor *health.Monitor, views *analytics.Recorder) {
+
+	timeout := 2 * time.Second
+	if cfg != nil {
+		timeout = cfg.Server.HealthCheckTimeout
+	}
+	healthHandler := health.NewHandler(health.NewChecker(db, monitor, timeout))
+	healthHandler.RegisterRoutes(r)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
 	if db != nil {
-		setupResourceRoutes(r, db)
+		setupResourceRoutes(r, db, cfg, views)
 	}
 
 }
+
+// SetupFrontend mounts the public HTML pages at the configured path. Unlike
+// the API they need no key. views counts the reads of posts unless it is
+// nil.
+func SetupFrontend(r *gin.Engine, db *gorm.DB, cfg config.FrontendConfig,
+	views *analytics.Recorder) error {
+	theme, err := site.OpenTheme(cfg.Theme, cfg.ThemeDir)
+	if err != nil {
+		return err
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = cfg.Path
+	}
+	handler := site.NewHandler(
+		post.NewTracedService(post.NewService(post.NewRepository(db))),
+		user.NewTracedService(user.NewService(user.NewRepository(db))),
+		theme, &site.Site{Title: cfg.Title, BaseURL: baseURL})
+	handler.PerPage = cfg.PerPage
+	handler.MaxAge = cfg.CacheMaxAge
+	if views != nil {
+		handler.Views = views
+	}
+	handler.RegisterRoutes(r.Group(strings.TrimRight(cfg.Path, "/")))
+	return nil
+}
@@ -1,22 +1,105 @@
 package router
 
 import (
+	"encoding/json"
 	"github.com/gin-gonic/gin"
+	"github.com/pandahawk/blog-api/internal/config"
+	"github.com/pandahawk/blog-api/internal/health"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 )
 
-func TestHealthEndpoint(t *testing.T) {
+func TestLivezEndpoint(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 
-	SetupRoutes(router, nil)
+	SetupRoutes(router, nil, nil, nil, nil)
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest(http.MethodGet, "/health", nil)
+	req, _ := http.NewRequest(http.MethodGet, "/livez", nil)
 
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
 }
+
+func TestReadyzEndpointWithoutDatabase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	monitor := health.NewMonitor()
+	monitor.SetReady(true)
+
+	SetupRoutes(router, nil, nil, monitor, nil)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var report health.Report
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, "database is not configured", report.Database.Error)
+}
+
+func TestCustomMethods(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	v1 := router.Group("/api/v1")
+	v1.POST("/users", func(c *gin.Context) { c.String(http.StatusCreated, "create") })
+	v1.POST("/admin/import", func(c *gin.Context) { c.String(http.StatusOK, "import") })
+	abort := func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusTooManyRequests)
+	}
+	next := func(c *gin.Context) { c.Next() }
+	v1.POST("/:collection", customMethods(map[string]gin.HandlersChain{
+		"users:batch": {next, func(c *gin.Context) { c.String(http.StatusOK, "batch") }},
+		"posts:batch": {abort, func(c *gin.Context) { c.String(http.StatusOK, "batch") }},
+	}))
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{path: "/api/v1/users", wantStatus: http.StatusCreated, wantBody: "create"},
+		{path: "/api/v1/admin/import", wantStatus: http.StatusOK, wantBody: "import"},
+		{path: "/api/v1/users:batch", wantStatus: http.StatusOK, wantBody: "batch"},
+		{path: "/api/v1/posts:batch", wantStatus: http.StatusTooManyRequests},
+		{path: "/api/v1/users:purge", wantStatus: http.StatusNotFound,
+			wantBody: `{"error":"not found"}`},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, test.path, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Equal(t, test.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestSetupFrontend(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	db := &gorm.DB{Config: &gorm.Config{}}
+	cfg := &config.Config{RateLimit: config.RateLimitConfig{Enabled: false}}
+
+	SetupRoutes(router, db, cfg, nil, nil)
+	err := SetupFrontend(router, db, config.FrontendConfig{Path: "/",
+		Theme: "default", PerPage: 10}, nil)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/static/style.css", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	err = SetupFrontend(gin.New(), db, config.FrontendConfig{Path: "/",
+		Theme: "neon"}, nil)
+	assert.ErrorContains(t, err, `unknown theme "neon"`)
+}
@@ -1,4 +1,3 @@
 #!/bin/bash
 
-migrate -path ./internal/database/migrations \
-  -database "postgres://${DB_USER}:${DB_PASSWORD}@${DB_HOST}:${DB_PORT}/${DB_NAME}?sslmode=disable" up
+go run . migrate up && go run . seed